@@ -0,0 +1,190 @@
+// Package accuracy checks the numerical accuracy of the solver against the certified values of
+// the NIST Statistical Reference Datasets (StRD) for linear least squares, reporting the number of
+// agreeing significant digits, so that changes to the solver can be validated.
+package accuracy
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/Synthace/regression"
+	"github.com/Synthace/regression/datasets"
+)
+
+// maxDigits caps the digits of agreement, as exact agreement has an infinite log relative error.
+const maxDigits = 15
+
+// Reference is a dataset with certified values for its coefficients, in the order of the
+// regression's coefficients starting with the offset.
+type Reference struct {
+	Name string
+	// Difficulty is the NIST rating of the dataset: lower, average or higher.
+	Difficulty   string
+	Build        func() *regression.Regression
+	Coefficients []float64
+	StdErrs      []float64
+	ResidualSD   float64
+	R2           float64
+}
+
+// Result is the agreement of a fit with its reference, as the log relative error (LRE): the
+// number of significant digits in agreement, or of decimal places where the certified value is 0.
+type Result struct {
+	Name         string
+	Coefficients []float64
+	StdErrs      []float64
+	ResidualSD   float64
+	R2           float64
+	// Min is the lowest agreement of any certified value.
+	Min float64
+}
+
+// Check fits the reference dataset and compares the fit with the certified values.
+func Check(ref Reference) (*Result, error) {
+	r := ref.Build()
+	if err := r.Run(); err != nil {
+		return nil, err
+	}
+	summary, err := r.Glance()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Name: ref.Name, Min: maxDigits}
+	digits := func(got, want float64) float64 {
+		d := lre(got, want)
+		res.Min = math.Min(res.Min, d)
+		return d
+	}
+	for i, want := range ref.Coefficients {
+		res.Coefficients = append(res.Coefficients, digits(r.Coeff(i), want))
+	}
+	for i, want := range ref.StdErrs {
+		res.StdErrs = append(res.StdErrs, digits(r.StdErr(i), want))
+	}
+	res.ResidualSD = digits(summary.Sigma, ref.ResidualSD)
+	res.R2 = digits(summary.R2, ref.R2)
+	return res, nil
+}
+
+// lre returns the log relative error of got, or the log absolute error if want is 0, between 0 and maxDigits.
+func lre(got, want float64) float64 {
+	if math.IsNaN(got) {
+		return 0
+	}
+	e := math.Abs(got - want)
+	if want != 0 {
+		e /= math.Abs(want)
+	}
+	if e == 0 {
+		return maxDigits
+	}
+	return math.Max(0, math.Min(maxDigits, -math.Log10(e)))
+}
+
+// StRD returns the available reference datasets: Longley, Filip, Wampler1 and Wampler2. Wampler3
+// to Wampler5 are not included, as their observations add published noise to the polynomial; they
+// can be checked by building a Reference from the NIST files.
+func StRD() []Reference {
+	return []Reference{Longley(), Filip(), Wampler1(), Wampler2()}
+}
+
+// Longley is the NIST reference for Longley's employment data, a regression on six highly
+// collinear variables.
+func Longley() Reference {
+	return Reference{
+		Name:       "Longley",
+		Difficulty: "higher",
+		Build: func() *regression.Regression {
+			r := new(regression.Regression)
+			datasets.Longley().Train(r)
+			return r
+		},
+		Coefficients: []float64{
+			-3482258.63459582, 15.0618722713733, -0.358191792925910e-01,
+			-2.02022980381683, -1.03322686717359, -0.511041056535807e-01, 1829.15146461355,
+		},
+		StdErrs: []float64{
+			890420.383607373, 84.9149257747669, 0.334910077722432e-01,
+			0.488399681651699, 0.214274163161675, 0.226073200069370, 455.478499142212,
+		},
+		ResidualSD: 304.854073561965,
+		R2:         0.995479004577296,
+	}
+}
+
+// Filip is the NIST reference for Filip's data, a tenth degree polynomial whose powers of x are so
+// nearly collinear that the design has a condition number of about 10¹⁵. Rounding the powers to
+// float64 perturbs the design enough that even an exact solver agrees to only about 7.5 digits.
+func Filip() Reference {
+	return Reference{
+		Name:       "Filip",
+		Difficulty: "higher",
+		Build: func() *regression.Regression {
+			r := new(regression.Regression)
+			datasets.Filip().Train(r)
+			for power := 2; power <= 10; power++ {
+				r.AddCross(regression.PowCross(0, float64(power)))
+			}
+			return r
+		},
+		Coefficients: []float64{
+			-1467.48961422980, -2772.17959193342, -2316.37108160893, -1127.97394098372,
+			-354.478233703349, -75.1242017393757, -10.8753180355343, -1.06221498588947,
+			-0.670191154593408e-01, -0.246781078275479e-02, -0.402962525080404e-04,
+		},
+		StdErrs: []float64{
+			298.084530995537, 559.779865474950, 466.477572127796, 227.204274477751,
+			71.6478660875927, 15.2897178747400, 2.23691159816033, 0.221624321934227,
+			0.142363763154724e-01, 0.535617408889821e-03, 0.896632837373868e-05,
+		},
+		ResidualSD: 0.334801051324544e-02,
+		R2:         0.996727416185620,
+	}
+}
+
+// Wampler1 is the NIST reference for the exact fifth degree polynomial y = 1 + x + x² + x³ + x⁴ + x⁵
+// at x = 0, 1, ..., 20.
+func Wampler1() Reference {
+	return wampler("Wampler1", 1)
+}
+
+// Wampler2 is the NIST reference for the exact fifth degree polynomial y = 1 + 0.1x + 0.01x² + ... + 0.00001x⁵
+// at x = 0, 1, ..., 20.
+func Wampler2() Reference {
+	return wampler("Wampler2", 0.1)
+}
+
+// wampler builds the reference for the polynomial whose coefficient of xⁱ is baseⁱ.
+func wampler(name string, base float64) Reference {
+	coeffs := make([]float64, 6)
+	for i := range coeffs {
+		coeffs[i] = math.Pow(base, float64(i))
+		// Round to the decimal value certified by NIST
+		coeffs[i], _ = strconv.ParseFloat(strconv.FormatFloat(coeffs[i], 'g', 6, 64), 64)
+	}
+	return Reference{
+		Name:       name,
+		Difficulty: "higher",
+		Build: func() *regression.Regression {
+			r := new(regression.Regression)
+			for x := 0; x <= 20; x++ {
+				var y float64
+				for i, c := range coeffs {
+					y += c * math.Pow(float64(x), float64(i))
+				}
+				// The published observations are exact to five decimal places
+				y, _ = strconv.ParseFloat(strconv.FormatFloat(y, 'f', 5, 64), 64)
+				r.Train(regression.DataPoint(y, []float64{float64(x)}))
+			}
+			for power := 2; power <= 5; power++ {
+				r.AddCross(regression.PowCross(0, float64(power)))
+			}
+			return r
+		},
+		Coefficients: coeffs,
+		StdErrs:      make([]float64, 6),
+		ResidualSD:   0,
+		R2:           1,
+	}
+}
@@ -0,0 +1,81 @@
+package accuracy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Synthace/regression"
+)
+
+func TestStRD(t *testing.T) {
+	// Minimum agreeing digits the solver is expected to reach for each dataset
+	minimum := map[string]float64{"Longley": 8, "Filip": 7, "Wampler1": 8, "Wampler2": 10}
+	for _, ref := range StRD() {
+		res, err := Check(ref)
+		if err != nil {
+			t.Errorf("%s: %v", ref.Name, err)
+			continue
+		}
+		if res.Min < minimum[ref.Name] {
+			t.Errorf("%s: expected at least %v digits, got %+v", ref.Name, minimum[ref.Name], res)
+		}
+	}
+}
+
+func TestLRE(t *testing.T) {
+	cases := []struct {
+		got, want, digits float64
+	}{
+		{1.0001, 1, 4},
+		{1, 1, maxDigits},
+		{1e-6, 0, 6},
+		{2, 1, 0},
+		{math.NaN(), 1, 0},
+	}
+	for _, c := range cases {
+		if d := lre(c.got, c.want); math.Abs(d-c.digits) > 1e-6 {
+			t.Errorf("lre(%v, %v): expected %v, got %v", c.got, c.want, c.digits, d)
+		}
+	}
+}
+
+func TestInternalScaling(t *testing.T) {
+	ref := Longley()
+	build := ref.Build
+	ref.Build = func() *regression.Regression {
+		r := build()
+		r.SetInternalScaling(true)
+		return r
+	}
+	res, err := Check(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Min < 11 {
+		t.Errorf("Expected at least 11 digits with internal scaling, got %+v", res)
+	}
+}
+
+func TestBigFloatSolver(t *testing.T) {
+	for _, ref := range StRD() {
+		build := ref.Build
+		ref.Build = func() *regression.Regression {
+			r := build()
+			r.SetSolver(regression.SolverBigFloat)
+			return r
+		}
+		res, err := Check(ref)
+		if err != nil {
+			t.Errorf("%s: %v", ref.Name, err)
+			continue
+		}
+		// Filip is limited by the rounding of its design rather than by the solver
+		minimum := 12.0
+		if ref.Name == "Filip" {
+			minimum = 7.5
+		}
+		if res.Min < minimum {
+			t.Errorf("%s: expected at least %v digits with the big float solver, got %+v", ref.Name, minimum, res)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Autocorrelation holds the autocorrelation and partial autocorrelation functions of the residuals
+// in the order the data was trained, for lags 1 to MaxLag at index lag-1.
+type Autocorrelation struct {
+	MaxLag int
+	ACF    []float64
+	PACF   []float64
+	// Bound is the half-width of the approximate 95% confidence band about zero, 1.96/√n, outside of
+	// which autocorrelations are significant for white noise residuals.
+	Bound float64
+}
+
+// ResidualAutocorrelation computes the autocorrelation and partial autocorrelation of the residuals
+// up to maxLag, complementing tests such as Durbin-Watson for time ordered data. If maxLag is zero
+// it defaults to 10·log₁₀(n), as in R's acf.
+func (r *Regression) ResidualAutocorrelation(maxLag int) (*Autocorrelation, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	n := len(r.Data)
+	if maxLag <= 0 {
+		maxLag = min(int(10*math.Log10(float64(n))), n-1)
+	}
+	if maxLag >= n {
+		return nil, fmt.Errorf("%w: lag %d for %d residuals", ErrNotEnoughData, maxLag, n)
+	}
+
+	e := make([]float64, n)
+	var mean float64
+	for i, d := range r.Data {
+		e[i] = d.Observed - d.Predicted
+		mean += e[i] / float64(n)
+	}
+	var c0 float64
+	for i := range e {
+		e[i] -= mean
+		c0 += e[i] * e[i]
+	}
+
+	a := &Autocorrelation{
+		MaxLag: maxLag,
+		ACF:    make([]float64, maxLag),
+		PACF:   make([]float64, maxLag),
+		Bound:  distuv.UnitNormal.Quantile(0.975) / math.Sqrt(float64(n)),
+	}
+	for k := 1; k <= maxLag; k++ {
+		var ck float64
+		for t := 0; t+k < n; t++ {
+			ck += e[t] * e[t+k]
+		}
+		a.ACF[k-1] = ck / c0
+	}
+
+	// Durbin-Levinson recursion, where phi holds the coefficients of the AR(k) fit
+	phi := make([]float64, maxLag)
+	prev := make([]float64, maxLag)
+	for k := 1; k <= maxLag; k++ {
+		num, den := a.ACF[k-1], 1.0
+		for j := 1; j < k; j++ {
+			num -= prev[j-1] * a.ACF[k-j-1]
+			den -= prev[j-1] * a.ACF[j-1]
+		}
+		phi[k-1] = num / den
+		for j := 1; j < k; j++ {
+			phi[j-1] = prev[j-1] - phi[k-1]*prev[k-j-1]
+		}
+		a.PACF[k-1] = phi[k-1]
+		copy(prev, phi)
+	}
+	return a, nil
+}
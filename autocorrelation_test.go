@@ -0,0 +1,55 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestResidualAutocorrelation(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if _, err := r.ResidualAutocorrelation(3); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+
+	// Residuals that follow an AR(1) process with coefficient 0.8
+	rng := rand.New(rand.NewSource(1))
+	var data [][]float64
+	e := 0.0
+	for i := 0; i < 200; i++ {
+		e = 0.8*e + rng.NormFloat64()
+		data = append(data, []float64{1 + 0.5*float64(i%17) + e, float64(i % 17)})
+	}
+	r = new(Regression)
+	r.Train(MakeDataPoints(data, 0)...)
+	r.Run()
+	a, err := r.ResidualAutocorrelation(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.MaxLag != 23 || len(a.ACF) != 23 || len(a.PACF) != 23 {
+		t.Errorf("Expected the default of 23 lags, got %d", a.MaxLag)
+	}
+	if math.Abs(a.Bound-1.96/math.Sqrt(200)) > 1e-3 {
+		t.Errorf("Expected a bound of 1.96/√200, got %v", a.Bound)
+	}
+	// The partial autocorrelation at lag 1 equals the autocorrelation, and an AR(1) process
+	// has a large autocorrelation at lag 1 and no significant partial autocorrelation after it
+	if a.PACF[0] != a.ACF[0] || a.ACF[0] < 0.6 {
+		t.Errorf("Expected a lag 1 autocorrelation near 0.8, got %v and %v", a.ACF[0], a.PACF[0])
+	}
+	if a.ACF[1] < 0.4 {
+		t.Errorf("Expected a decaying autocorrelation, got %v at lag 2", a.ACF[1])
+	}
+	for k := 2; k < 5; k++ {
+		if math.Abs(a.PACF[k]) > 2*a.Bound {
+			t.Errorf("Expected no partial autocorrelation at lag %d, got %v", k+1, a.PACF[k])
+		}
+	}
+
+	if _, err := r.ResidualAutocorrelation(200); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
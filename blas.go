@@ -0,0 +1,34 @@
+package regression
+
+import (
+	"reflect"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// UseBLAS sets the BLAS implementation used by gonum for the matrix operations of the solvers, such
+// as an OpenBLAS or MKL binding through cgo from gonum.org/v1/netlib/blas/netlib:
+//
+//	regression.UseBLAS(netlib.Implementation{})
+//
+// The pure Go implementation of gonum is used by default. The setting is global to gonum, so it also
+// applies to any other use of gonum in the program, and should be made once at startup rather than
+// while fits are running.
+func UseBLAS(impl blas.Float64) {
+	blas64.Use(impl)
+}
+
+// BLASBackend returns the import path and name of the type of the BLAS implementation in use, such as
+// "gonum.org/v1/gonum/blas/gonum.Implementation" for the default, so that deployments can verify at
+// runtime that an optimized backend is active.
+func BLASBackend() string {
+	t := reflect.TypeOf(blas64.Implementation())
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
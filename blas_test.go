@@ -0,0 +1,48 @@
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// countingBLAS counts the level 3 matrix multiplications it is asked for.
+type countingBLAS struct {
+	blas.Float64
+	gemm int
+}
+
+func (b *countingBLAS) Dgemm(tA, tB blas.Transpose, m, n, k int, alpha float64, a []float64, lda int, x []float64, ldb int, beta float64, c []float64, ldc int) {
+	b.gemm++
+	b.Float64.Dgemm(tA, tB, m, n, k, alpha, a, lda, x, ldb, beta, c, ldc)
+}
+
+func TestUseBLAS(t *testing.T) {
+	if got := BLASBackend(); got != "gonum.org/v1/gonum/blas/gonum.Implementation" {
+		t.Errorf("Expected the gonum backend by default, got %q", got)
+	}
+	want := new(Regression)
+	want.Train(MakeDataPoints(anscombe, 0)...)
+	want.Run()
+
+	defaultImpl := blas64.Implementation()
+	defer UseBLAS(defaultImpl)
+	counting := &countingBLAS{Float64: defaultImpl}
+	UseBLAS(counting)
+	if got := BLASBackend(); got != "github.com/Synthace/regression.countingBLAS" {
+		t.Errorf("Expected the counting backend, got %q", got)
+	}
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if counting.gemm == 0 {
+		t.Error("Expected the fit to use the configured backend")
+	}
+	if math.Abs(r.Coeff(1)-want.Coeff(1)) > 1e-12 {
+		t.Errorf("Expected coefficient %v, got %v", want.Coeff(1), r.Coeff(1))
+	}
+}
@@ -0,0 +1,92 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// calibrationBins is the number of bins in a calibration table, one for each decile of the predictions.
+const calibrationBins = 10
+
+// Calibration compares observed values with predictions. A well calibrated model has an intercept
+// near 0 and a slope near 1 when the observed values are regressed on the predictions; a slope
+// below 1 suggests that the predictions are too extreme, as from an overfitted model.
+type Calibration struct {
+	Intercept       float64
+	Slope           float64
+	InterceptStdErr float64
+	SlopeStdErr     float64
+	// Bins holds the calibration table, grouping the data points by decile of their prediction.
+	Bins []CalibrationBin
+}
+
+// CalibrationBin is a row of a calibration table.
+type CalibrationBin struct {
+	// Lower and Upper are the smallest and largest predictions in the bin.
+	Lower         float64
+	Upper         float64
+	N             int
+	MeanPredicted float64
+	MeanObserved  float64
+}
+
+// Calibrate measures the calibration of the fitted model on a set of data points, such as held-out
+// data or data scored since the model was deployed. With fewer than 10 points each bin holds one point.
+func (r *Regression) Calibrate(test DataPoints) (*Calibration, error) {
+	if !r.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	n := len(test)
+	if n < 3 {
+		return nil, fmt.Errorf("%w: %d data points, need at least 3", ErrNotEnoughData, n)
+	}
+	predicted := make([]float64, n)
+	for i, d := range test {
+		p, err := r.predict(d.Variables)
+		if err != nil {
+			return nil, &DataPointError{Index: i, Err: err}
+		}
+		predicted[i] = p
+	}
+
+	// Regress observed on predicted
+	var meanP, meanO float64
+	for i, d := range test {
+		meanP += predicted[i] / float64(n)
+		meanO += d.Observed / float64(n)
+	}
+	var sxx, sxy float64
+	for i, d := range test {
+		sxx += (predicted[i] - meanP) * (predicted[i] - meanP)
+		sxy += (predicted[i] - meanP) * (d.Observed - meanO)
+	}
+	c := &Calibration{Slope: sxy / sxx}
+	c.Intercept = meanO - c.Slope*meanP
+	var sse float64
+	for i, d := range test {
+		e := d.Observed - c.Intercept - c.Slope*predicted[i]
+		sse += e * e
+	}
+	sigma2 := sse / float64(n-2)
+	c.SlopeStdErr = math.Sqrt(sigma2 / sxx)
+	c.InterceptStdErr = math.Sqrt(sigma2 * (1/float64(n) + meanP*meanP/sxx))
+
+	// Group by decile of the predictions
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return predicted[order[a]] < predicted[order[b]] })
+	bins := min(calibrationBins, n)
+	for b := 0; b < bins; b++ {
+		rows := order[b*n/bins : (b+1)*n/bins]
+		bin := CalibrationBin{Lower: predicted[rows[0]], Upper: predicted[rows[len(rows)-1]], N: len(rows)}
+		for _, i := range rows {
+			bin.MeanPredicted += predicted[i] / float64(len(rows))
+			bin.MeanObserved += test[i].Observed / float64(len(rows))
+		}
+		c.Bins = append(c.Bins, bin)
+	}
+	return c, nil
+}
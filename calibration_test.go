@@ -0,0 +1,56 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCalibrate(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if _, err := r.Calibrate(MakeDataPoints(anscombe, 0)); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Run()
+
+	// Least squares is calibrated in sample by construction
+	c, err := r.Calibrate(MakeDataPoints(anscombe, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(c.Slope-1) > 1e-9 || math.Abs(c.Intercept) > 1e-9 {
+		t.Errorf("Expected intercept 0 and slope 1 in sample, got %v and %v", c.Intercept, c.Slope)
+	}
+	if len(c.Bins) != 10 {
+		t.Fatalf("Expected 10 bins, got %d", len(c.Bins))
+	}
+	var total int
+	for i, bin := range c.Bins {
+		total += bin.N
+		if i > 0 && bin.Lower < c.Bins[i-1].Upper {
+			t.Errorf("Expected bins in order of prediction, got %+v after %+v", bin, c.Bins[i-1])
+		}
+	}
+	if total != len(anscombe) {
+		t.Errorf("Expected %d points in the bins, got %d", len(anscombe), total)
+	}
+
+	// Observed values that vary half as much as the predictions give a slope of 1/2
+	var test DataPoints
+	coeffs := r.GetCoeffs()
+	for x := 4.0; x <= 14; x++ {
+		p := coeffs[0] + coeffs[1]*x
+		test = append(test, DataPoint(7.5+(p-7.5)/2, []float64{x}))
+	}
+	if c, err = r.Calibrate(test); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(c.Slope-0.5) > 1e-9 || c.SlopeStdErr > 1e-9 {
+		t.Errorf("Expected an exact slope of 0.5, got %v ± %v", c.Slope, c.SlopeStdErr)
+	}
+
+	if _, err := r.Calibrate(test[:2]); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// interval is the range [lower, upper] known to contain a value, with infinite bounds for a range
+// open on that side.
+type interval struct {
+	lower, upper float64
+}
+
+// CensoredDataPoint creates a data point whose observed value is known only to lie in [lower, upper],
+// such as a concentration below the limit of quantification of an assay, with lower -Inf, or above
+// the top of its range, with upper +Inf. Regressions trained with censored points are fitted by
+// maximum likelihood. The Observed value of the point is the midpoint of the interval, or its finite
+// bound if it is open on one side, and is only used for display and the residual statistics.
+func CensoredDataPoint(lower, upper float64, vars []float64) *dataPoint {
+	d := &dataPoint{Variables: vars, censored: &interval{lower: lower, upper: upper}}
+	switch {
+	case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+		d.Observed = math.NaN()
+	case math.IsInf(lower, -1):
+		d.Observed = upper
+	case math.IsInf(upper, 1):
+		d.Observed = lower
+	default:
+		d.Observed = (lower + upper) / 2
+	}
+	return d
+}
+
+// SetTruncation declares that the observed values are truncated to [lower, upper]: data points were
+// only collected when their value fell in the interval, so the sample says nothing about values outside
+// it. Use -Inf or +Inf for a side that is not truncated. A truncated regression is fitted by maximum
+// likelihood, as with censored data points, rather than by least squares, which is biased towards zero
+// under truncation.
+func (r *Regression) SetTruncation(lower, upper float64) {
+	r.truncation = &interval{lower: lower, upper: upper}
+}
+
+// maximumLikelihood reports whether the regression must be fitted by maximum likelihood, because some
+// of its data points are censored or its observed values truncated.
+func (r *Regression) maximumLikelihood() bool {
+	if r.truncation != nil {
+		return true
+	}
+	for _, d := range r.Data {
+		if d.censored != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCensoring checks that the censoring intervals and truncation are well formed and consistent.
+func (r *Regression) checkCensoring() error {
+	t := r.truncation
+	if t != nil && !(t.lower < t.upper) {
+		return fmt.Errorf("%w: truncation to [%v, %v]", ErrDesign, t.lower, t.upper)
+	}
+	for i, d := range r.Data {
+		c := d.censored
+		if c == nil {
+			if t != nil && (d.Observed < t.lower || d.Observed > t.upper) {
+				return &DataPointError{Index: i, Err: fmt.Errorf("%w: observed value %v outside the truncation [%v, %v]", ErrDesign, d.Observed, t.lower, t.upper)}
+			}
+			continue
+		}
+		if !(c.lower <= c.upper) {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: censoring interval [%v, %v]", ErrDesign, c.lower, c.upper)}
+		}
+		if t != nil && (c.upper < t.lower || c.lower > t.upper) {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: censoring interval [%v, %v] outside the truncation [%v, %v]", ErrDesign, c.lower, c.upper, t.lower, t.upper)}
+		}
+	}
+	return nil
+}
+
+// censoredMaximumLikelihood fits the regression to censored or truncated observed values by maximizing
+// the normal log likelihood over the coefficients and log σ, starting from the least squares fit to the
+// Observed values. Exact observations contribute their density, censored ones the probability of their
+// interval, and under truncation each is divided by the probability of the truncation interval. The
+// covariance of the coefficients is the inverse of the observed information.
+func (r *Regression) censoredMaximumLikelihood(observed, variables *mat.Dense) ([]float64, error) {
+	rows, k := variables.Dims()
+	bounds := make([]*interval, rows)
+	for i, d := range r.Data {
+		bounds[i] = d.censored
+	}
+
+	start, err := leastSquares(variables, observed)
+	if err != nil {
+		return nil, err
+	}
+	sigma := math.Sqrt(sumOfSquaredResiduals(variables, observed, start) / float64(rows-k))
+	if !(sigma > 0) {
+		sigma = 1
+	}
+	start = append(start, math.Log(sigma))
+
+	logLik := func(theta, grad []float64) float64 {
+		beta, s := theta[:k], math.Exp(theta[k])
+		for j := range grad {
+			grad[j] = 0
+		}
+		var total float64
+		for i := 0; i < rows; i++ {
+			var mu float64
+			for j := 0; j < k; j++ {
+				mu += variables.At(i, j) * beta[j]
+			}
+			var l, dMu, dLogSigma float64
+			if c := bounds[i]; c == nil || c.lower == c.upper {
+				y := observed.At(i, 0)
+				if c != nil {
+					y = c.lower
+				}
+				z := (y - mu) / s
+				l = -0.5*z*z - math.Log(s) - 0.5*math.Log(2*math.Pi)
+				dMu, dLogSigma = z/s, z*z-1
+			} else {
+				l, dMu, dLogSigma = intervalLogProb(c.lower, c.upper, mu, s)
+			}
+			if t := r.truncation; t != nil {
+				lt, dMuT, dLogSigmaT := intervalLogProb(t.lower, t.upper, mu, s)
+				l, dMu, dLogSigma = l-lt, dMu-dMuT, dLogSigma-dLogSigmaT
+			}
+			total += l
+			if grad != nil {
+				for j := 0; j < k; j++ {
+					grad[j] += dMu * variables.At(i, j)
+				}
+				grad[k] += dLogSigma
+			}
+		}
+		return total
+	}
+
+	problem := optimize.Problem{
+		Func: func(theta []float64) float64 {
+			return -logLik(theta, nil)
+		},
+		Grad: func(grad, theta []float64) {
+			logLik(theta, grad)
+			for j := range grad {
+				grad[j] = -grad[j]
+			}
+		},
+	}
+	result, err := optimize.Minimize(problem, start, r.convergence.settings(r.progress, rows), &optimize.BFGS{})
+	if err != nil && result == nil {
+		return nil, err
+	}
+	r.iterations, r.converged = result.Stats.MajorIterations, converged(result.Status)
+	if math.IsInf(result.F, 0) || math.IsNaN(result.F) {
+		return nil, fmt.Errorf("%w: the likelihood of the censored data could not be maximized", ErrSingular)
+	}
+	theta := result.X
+
+	// Observed information, the Jacobian of the gradient of the negative log likelihood
+	info := mat.NewDense(k+1, k+1, nil)
+	fd.Jacobian(info, problem.Grad, theta, nil)
+	sym := mat.NewSymDense(k+1, nil)
+	for i := 0; i <= k; i++ {
+		for j := i; j <= k; j++ {
+			sym.SetSym(i, j, (info.At(i, j)+info.At(j, i))/2)
+		}
+	}
+	r.cov = nil
+	var chol mat.Cholesky
+	if chol.Factorize(sym) {
+		inv := new(mat.SymDense)
+		if err := chol.InverseTo(inv); err == nil {
+			r.cov = mat.NewSymDense(k, nil)
+			r.cov.CopySym(inv.SliceSym(0, k))
+		}
+	}
+	r.residualDF = rows - k
+	r.mlSigma = math.Exp(theta[k])
+	r.logLik = -result.F
+	return theta[:k], nil
+}
+
+// intervalLogProb returns the log probability that a normal value with mean mu and standard deviation
+// s lies in [lower, upper], with its derivatives with respect to mu and log s.
+func intervalLogProb(lower, upper, mu, s float64) (l, dMu, dLogSigma float64) {
+	a, b := (lower-mu)/s, (upper-mu)/s
+	// Work in the upper tail when the interval lies above the mean, where Φ rounds to 1
+	var p float64
+	if a > 0 {
+		p = normalSurvival(a) - normalSurvival(b)
+	} else {
+		p = normalCDF(b) - normalCDF(a)
+	}
+	phiA, phiB := normalDensity(a), normalDensity(b)
+	// zφ(z) is 0 at infinite z
+	zphiA, zphiB := a*phiA, b*phiB
+	if math.IsInf(a, 0) {
+		zphiA = 0
+	}
+	if math.IsInf(b, 0) {
+		zphiB = 0
+	}
+	return math.Log(p), (phiA - phiB) / (s * p), (zphiA - zphiB) / p
+}
+
+func normalDensity(z float64) float64 {
+	return math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(z float64) float64 {
+	return math.Erfc(-z/math.Sqrt2) / 2
+}
+
+func normalSurvival(z float64) float64 {
+	return math.Erfc(z/math.Sqrt2) / 2
+}
+
+// LogLikelihood returns the maximized log likelihood of a model fitted by maximum likelihood to
+// censored or truncated data, or NaN for a least squares fit.
+func (r *Regression) LogLikelihood() float64 {
+	if !r.hasRun || !r.maximumLikelihood() {
+		return math.NaN()
+	}
+	return r.logLik
+}
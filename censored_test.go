@@ -0,0 +1,107 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCensoredRegression(t *testing.T) {
+	// y = 1 + 2x + ε, with values below the limit of quantification 3 reported only as below it
+	rng := rand.New(rand.NewSource(3))
+	r, naive := new(Regression), new(Regression)
+	for i := 0; i < 500; i++ {
+		x := rng.Float64() * 2
+		y := 1 + 2*x + rng.NormFloat64()
+		if y < 3 {
+			r.Train(CensoredDataPoint(math.Inf(-1), 3, []float64{x}))
+			naive.Train(DataPoint(3, []float64{x}))
+		} else {
+			r.Train(DataPoint(y, []float64{x}))
+			naive.Train(DataPoint(y, []float64{x}))
+		}
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := naive.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Coeff(1)-2) > 3*r.StdErr(1) || math.Abs(r.Coeff(0)-1) > 3*r.StdErr(0) {
+		t.Errorf("Expected coefficients near 1 and 2, got %.3f ± %.3f and %.3f ± %.3f", r.Coeff(0), r.StdErr(0), r.Coeff(1), r.StdErr(1))
+	}
+	if naive.Coeff(1) > 1.8 {
+		t.Errorf("Expected substituting the limit to attenuate the slope, got %.3f", naive.Coeff(1))
+	}
+	if math.Abs(r.Sigma()-1) > 0.15 {
+		t.Errorf("Expected σ near 1, got %.3f", r.Sigma())
+	}
+	if math.IsNaN(r.LogLikelihood()) || !math.IsNaN(naive.LogLikelihood()) {
+		t.Errorf("Expected a log likelihood for the censored fit only, got %v and %v", r.LogLikelihood(), naive.LogLikelihood())
+	}
+}
+
+func TestIntervalCensoredRegression(t *testing.T) {
+	// Observed values only known to the nearest whole number
+	rng := rand.New(rand.NewSource(4))
+	r := new(Regression)
+	for i := 0; i < 300; i++ {
+		x := rng.Float64() * 5
+		y := 0.5 + 1.5*x + 0.5*rng.NormFloat64()
+		r.Train(CensoredDataPoint(math.Floor(y), math.Floor(y)+1, []float64{x}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Coeff(1)-1.5) > 3*r.StdErr(1) {
+		t.Errorf("Expected a slope near 1.5, got %.3f ± %.3f", r.Coeff(1), r.StdErr(1))
+	}
+	if math.Abs(r.Sigma()-0.5) > 0.1 {
+		t.Errorf("Expected σ near 0.5, got %.3f", r.Sigma())
+	}
+}
+
+func TestTruncatedRegression(t *testing.T) {
+	// Only data points with y above 2 were collected
+	rng := rand.New(rand.NewSource(5))
+	r := new(Regression)
+	r.SetTruncation(2, math.Inf(1))
+	for len(r.Data) < 400 {
+		x := rng.Float64() * 2
+		if y := 1 + 2*x + rng.NormFloat64(); y > 2 {
+			r.Train(DataPoint(y, []float64{x}))
+		}
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Coeff(1)-2) > 3*r.StdErr(1) {
+		t.Errorf("Expected a slope near 2, got %.3f ± %.3f", r.Coeff(1), r.StdErr(1))
+	}
+
+	outside := new(Regression)
+	outside.SetTruncation(5, math.Inf(1))
+	outside.Train(MakeDataPoints(anscombe, 0)...)
+	var pointErr *DataPointError
+	if err := outside.Run(); !errors.Is(err, ErrDesign) || !errors.As(err, &pointErr) {
+		t.Errorf("Expected a DataPointError wrapping ErrDesign for a value outside the truncation, got %v", err)
+	}
+}
+
+func TestCensoringErrors(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Train(CensoredDataPoint(5, 4, []float64{3}))
+	if err := r.Run(); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for an empty censoring interval, got %v", err)
+	}
+
+	r = new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Train(CensoredDataPoint(math.Inf(-1), 4, []float64{3}))
+	r.SetCovarianceType(HC3)
+	if err := r.Run(); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions for censored data with robust covariance, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+// Command regress fits a linear regression to the columns of a CSV file, then prints the
+// coefficient table and model statistics or writes the fitted model as JSON.
+//
+// The model is given either as a formula, where C(name) marks a categorical column and a:b is
+// the interaction of two columns,
+//
+//	regress -formula 'Yield ~ Temp + pH + C(Batch) + Temp:pH' data.csv
+//
+// or as an observed column and a comma separated list of variable columns, which defaults to
+// every other column:
+//
+//	regress -y Yield -x Temp,pH -json model.json data.csv
+//
+// The CSV file must have a header row. It is read from standard input if no file is given.
+// Empty and NA values are read as missing, and rows with missing values are dropped.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Synthace/regression"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "regress:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("regress", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	formula := flags.String("formula", "", "model formula, such as 'y ~ x1 + x2 + C(group) + x1:x2'")
+	observed := flags.String("y", "", "observed column, if no formula is given")
+	variables := flags.String("x", "", "comma separated variable columns, if no formula is given (default every other column)")
+	jsonPath := flags.String("json", "", "write the fitted model as JSON to this file, or - for standard output, instead of printing the summary")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	in := stdin
+	if path := flags.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	header, columns, err := readCSV(in)
+	if err != nil {
+		return err
+	}
+
+	var s *spec
+	switch {
+	case *formula != "":
+		s, err = parseFormula(*formula)
+	case *observed != "":
+		s, err = columnSpec(*observed, *variables, header)
+	default:
+		err = errors.New("either -formula or -y is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	r, err := s.fit(columns)
+	if err != nil {
+		return err
+	}
+	for _, w := range r.Warnings() {
+		fmt.Fprintln(stderr, "warning:", w.Message)
+	}
+
+	switch *jsonPath {
+	case "":
+		return printSummary(stdout, s, r)
+	case "-":
+		return writeModel(stdout, r)
+	default:
+		f, err := os.Create(*jsonPath)
+		if err != nil {
+			return err
+		}
+		if err := writeModel(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+}
+
+// readCSV reads the header and the values of each column.
+func readCSV(in io.Reader) ([]string, map[string][]string, error) {
+	records, err := csv.NewReader(in).ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("empty CSV file")
+	}
+	header := records[0]
+	columns := make(map[string][]string, len(header))
+	for _, record := range records[1:] {
+		for j, name := range header {
+			columns[name] = append(columns[name], record[j])
+		}
+	}
+	return header, columns, nil
+}
+
+// parseColumn converts the values of a numeric column, reading empty and NA values as NaN.
+// isMissing reports whether a CSV value is empty or NA.
+func isMissing(v string) bool {
+	v = strings.TrimSpace(v)
+	return v == "" || v == "NA"
+}
+
+func parseColumn(name string, values []string) ([]float64, error) {
+	parsed := make([]float64, len(values))
+	for i, v := range values {
+		if isMissing(v) {
+			parsed[i] = math.NaN()
+			continue
+		}
+		v = strings.TrimSpace(v)
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %q, row %d: %v", name, i+1, err)
+		}
+		parsed[i] = f
+	}
+	return parsed, nil
+}
+
+func printSummary(w io.Writer, s *spec, r *regression.Regression) error {
+	rows, err := r.Tidy()
+	if err != nil {
+		return err
+	}
+	summary, err := r.Glance()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Model: %s\n\n", s)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "\tEstimate\tStd. Error\tt value\tPr(>|t|)\t")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%.6g\t%.6g\t%.3f\t%.4g\t\n", row.Term, row.Estimate, row.StdErr, row.TValue, row.PValue)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nResidual standard error: %.6g on %d degrees of freedom\n", summary.Sigma, summary.DFResidual)
+	fmt.Fprintf(w, "R-squared: %.4f, Adjusted R-squared: %.4f\n", summary.R2, summary.AdjustedR2)
+	fmt.Fprintf(w, "F-statistic: %.4g on %d and %d DF, p-value: %.4g\n", summary.FStatistic, summary.DF, summary.DFResidual, summary.PValue)
+	return nil
+}
+
+// writeModel writes the fitted model in the versioned JSON format of the regression package, so that
+// it can be loaded with json.Unmarshal into a regression.Regression.
+func writeModel(w io.Writer, r *regression.Regression) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Synthace/regression"
+)
+
+const testCSV = `y,x,group
+1.1,0,a
+2.9,1,b
+5.2,2,a
+6.8,3,b
+9.1,4,a
+NA,5,b
+13.2,6,a
+`
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-formula", "y ~ x + C(group)"}, strings.NewReader(testCSV), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Model: y ~ x + C(group)", "group[b]", "R-squared"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected summary to contain %q, got\n%s", want, stdout.String())
+		}
+	}
+	if !strings.Contains(stderr.String(), "dropped 1 data points") {
+		t.Errorf("Expected a dropped row warning, got %q", stderr.String())
+	}
+
+	stdout.Reset()
+	if err := run([]string{"-y", "y", "-x", "x", "-json", "-"}, strings.NewReader(testCSV), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var m regression.Regression
+	if err := json.Unmarshal(stdout.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(m.Coeff(1)-2) > 0.1 || m.GetObserved() != "y" || m.GetVar(0) != "x" {
+		t.Errorf("Unexpected model %v", m.Formula)
+	}
+	if got, err := m.Predict([]float64{4}); err != nil || math.Abs(got-9) > 0.5 {
+		t.Errorf("Expected the loaded model to predict about 9, got %v, %v", got, err)
+	}
+
+	if err := run([]string{"-y", "z"}, strings.NewReader(testCSV), &stdout, &stderr); err == nil {
+		t.Error("Expected an error for a missing column")
+	}
+	if err := run(nil, strings.NewReader(testCSV), &stdout, &stderr); err == nil {
+		t.Error("Expected an error without a formula or observed column")
+	}
+}
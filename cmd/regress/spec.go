@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Synthace/regression"
+)
+
+// spec is a model specification: the observed column and the terms of the design, each of which
+// is a column or an interaction of columns.
+type spec struct {
+	observed    string
+	terms       [][]string
+	categorical map[string]bool
+}
+
+// parseFormula parses a formula such as "y ~ x1 + C(group) + x1:x2".
+func parseFormula(formula string) (*spec, error) {
+	lhs, rhs, ok := strings.Cut(formula, "~")
+	if !ok {
+		return nil, fmt.Errorf("formula %q has no ~", formula)
+	}
+	s := &spec{observed: strings.TrimSpace(lhs), categorical: make(map[string]bool)}
+	if s.observed == "" {
+		return nil, fmt.Errorf("formula %q has no observed column", formula)
+	}
+	for _, term := range strings.Split(rhs, "+") {
+		var names []string
+		for _, name := range strings.Split(term, ":") {
+			name = strings.TrimSpace(name)
+			if strings.HasPrefix(name, "C(") && strings.HasSuffix(name, ")") {
+				name = strings.TrimSpace(name[2 : len(name)-1])
+				if !s.categorical[name] && strings.Contains(term, ":") {
+					return nil, fmt.Errorf("categorical column %q must be a term before its interactions", name)
+				}
+				s.categorical[name] = true
+			}
+			if name == "" {
+				return nil, fmt.Errorf("formula %q has an empty term", formula)
+			}
+			names = append(names, name)
+		}
+		s.terms = append(s.terms, names)
+	}
+	return s, nil
+}
+
+// columnSpec specifies a model from the observed column and a comma separated list of variable
+// columns, defaulting to every other column in the header.
+func columnSpec(observed, variables string, header []string) (*spec, error) {
+	s := &spec{observed: observed, categorical: make(map[string]bool)}
+	if variables == "" {
+		for _, name := range header {
+			if name != observed {
+				s.terms = append(s.terms, []string{name})
+			}
+		}
+	} else {
+		for _, name := range strings.Split(variables, ",") {
+			s.terms = append(s.terms, []string{strings.TrimSpace(name)})
+		}
+	}
+	if len(s.terms) == 0 {
+		return nil, fmt.Errorf("no variable columns")
+	}
+	return s, nil
+}
+
+// fit builds the design from the columns and runs the regression.
+func (s *spec) fit(columns map[string][]string) (*regression.Regression, error) {
+	values, ok := columns[s.observed]
+	if !ok {
+		return nil, fmt.Errorf("no column %q", s.observed)
+	}
+	observed, err := parseColumn(s.observed, values)
+	if err != nil {
+		return nil, err
+	}
+	missing := make([]bool, len(observed))
+
+	design := regression.NewDesign()
+	numeric := make(map[string][]float64)
+	categorical := make(map[string][]string)
+	added := make(map[string]bool)
+	for _, term := range s.terms {
+		for _, name := range term {
+			values, ok := columns[name]
+			if !ok {
+				return nil, fmt.Errorf("no column %q", name)
+			}
+			if s.categorical[name] {
+				if categorical[name] == nil {
+					categorical[name] = fillMissing(values, missing)
+				}
+			} else if numeric[name] == nil {
+				if numeric[name], err = parseColumn(name, values); err != nil {
+					return nil, err
+				}
+			}
+		}
+		switch {
+		case len(term) > 1:
+			design.Interaction(term...)
+		case s.categorical[term[0]] && !added[term[0]]:
+			design.Categorical(term[0], regression.TreatmentContrast)
+			added[term[0]] = true
+		case !s.categorical[term[0]]:
+			design.Numeric(term[0])
+		}
+	}
+
+	for i := range missing {
+		if missing[i] {
+			observed[i] = math.NaN()
+		}
+	}
+
+	m, err := design.Build(numeric, categorical)
+	if err != nil {
+		return nil, err
+	}
+	r := new(regression.Regression)
+	r.SetObserved(s.observed)
+	if err := m.Train(r, observed); err != nil {
+		return nil, err
+	}
+	if err := r.Run(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// fillMissing returns a copy of a categorical column with empty and NA values replaced by another
+// level of the column, so that they are not encoded as a level of their own, and marks their rows
+// as missing. The observed values of missing rows are set to NaN, so the rows are dropped.
+func fillMissing(values []string, missing []bool) []string {
+	var fill string
+	for _, v := range values {
+		if !isMissing(v) {
+			fill = v
+			break
+		}
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		if isMissing(v) {
+			v, missing[i] = fill, true
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// String formats the specification as a formula.
+func (s *spec) String() string {
+	terms := make([]string, len(s.terms))
+	for i, term := range s.terms {
+		names := make([]string, len(term))
+		for j, name := range term {
+			if s.categorical[name] {
+				name = "C(" + name + ")"
+			}
+			names[j] = name
+		}
+		terms[i] = strings.Join(names, ":")
+	}
+	return s.observed + " ~ " + strings.Join(terms, " + ")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormula(t *testing.T) {
+	s, err := parseFormula("Yield ~ Temp + C(Batch) + Temp:C(Batch)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"Temp"}, {"Batch"}, {"Temp", "Batch"}}
+	if s.observed != "Yield" || !reflect.DeepEqual(s.terms, want) || !s.categorical["Batch"] {
+		t.Errorf("Unexpected specification %+v", s)
+	}
+	if got := s.String(); got != "Yield ~ Temp + C(Batch) + Temp:C(Batch)" {
+		t.Errorf("Expected the formula back, got %q", got)
+	}
+
+	for _, bad := range []string{"Yield", "~ Temp", "Yield ~ Temp + ", "Yield ~ Temp:C(Batch)"} {
+		if _, err := parseFormula(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestColumnSpec(t *testing.T) {
+	s, err := columnSpec("y", "", []string{"a", "y", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.terms, [][]string{{"a"}, {"b"}}) {
+		t.Errorf("Expected every other column, got %v", s.terms)
+	}
+	if _, err := columnSpec("y", "", []string{"y"}); err == nil {
+		t.Error("Expected an error without variable columns")
+	}
+}
+
+func TestFitMissingLevel(t *testing.T) {
+	s, err := parseFormula("y ~ x + C(group)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := map[string][]string{
+		"y":     {"1", "2", "8", "4", "5", "9", "7"},
+		"x":     {"0", "1", "2", "3", "4", "5", "6"},
+		"group": {"a", "a", "", "b", "b", "NA", "a"},
+	}
+	r, err := s.fit(columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Variables(); !reflect.DeepEqual(got, []string{"x", "group[b]"}) {
+		t.Errorf("Expected missing groups not to be a level, got %v", got)
+	}
+	if got := r.ResidualDF(); got != 2 {
+		t.Errorf("Expected the 2 rows with a missing group to be dropped, leaving 2 residual degrees of freedom, got %d", got)
+	}
+}
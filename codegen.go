@@ -0,0 +1,64 @@
+package regression
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"strconv"
+)
+
+// GoPredictor generates the source of a self-contained Go file in package pkg, declaring a function
+// funcName(vars []float64) float64 that implements Predict for the fitted model with the coefficients
+// inlined. This allows models to be embedded in services without importing this package or gonum.
+// Models using feature crosses cannot yet be generated.
+func (r *Regression) GoPredictor(pkg, funcName string) ([]byte, error) {
+	if len(r.coeff) == 0 {
+		return nil, ErrRegressionNotRun
+	}
+	if len(r.crosses) > 0 {
+		return nil, ErrUnsupportedCross
+	}
+	for _, name := range []string{pkg, funcName} {
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("invalid Go identifier %q", name)
+		}
+	}
+
+	observed := commentText(r.GetObserved())
+	if observed == "" {
+		observed = "the observed value"
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by github.com/Synthace/regression. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "// %s predicts %s from the variables, which are in the order:\n", funcName, observed)
+	for i := 0; i < len(r.coeff)-1; i++ {
+		fmt.Fprintf(&b, "//\t%d: %s\n", i, commentText(r.GetVar(i)))
+	}
+	fmt.Fprintf(&b, "func %s(vars []float64) float64 {\n", funcName)
+	fmt.Fprintf(&b, "\treturn %s", strconv.FormatFloat(r.Coeff(0), 'g', -1, 64))
+	for i := 1; i < len(r.coeff); i++ {
+		c := r.Coeff(i)
+		if c == 0 {
+			continue
+		}
+		sign := "+"
+		if c < 0 {
+			sign = "-"
+		}
+		fmt.Fprintf(&b, " %s\n\t\t%s*vars[%d]", sign, strconv.FormatFloat(math.Abs(c), 'g', -1, 64), i-1)
+	}
+	fmt.Fprintf(&b, "\n}\n")
+	return format.Source(b.Bytes())
+}
+
+// commentText returns name as it can be written in a line comment: unchanged if it is printable
+// text without quotes, otherwise quoted, so that a newline cannot end the comment.
+func commentText(name string) string {
+	if quoted := strconv.Quote(name); quoted[1:len(quoted)-1] != name {
+		return quoted
+	}
+	return name
+}
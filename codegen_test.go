@@ -0,0 +1,97 @@
+package regression
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// evalPredictor evaluates the expression returned by a generated predictor function.
+func evalPredictor(t *testing.T, e ast.Expr, vars []float64) float64 {
+	switch e := e.(type) {
+	case *ast.BinaryExpr:
+		x, y := evalPredictor(t, e.X, vars), evalPredictor(t, e.Y, vars)
+		switch e.Op {
+		case token.ADD:
+			return x + y
+		case token.SUB:
+			return x - y
+		case token.MUL:
+			return x * y
+		}
+	case *ast.BasicLit:
+		v, err := strconv.ParseFloat(e.Value, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	case *ast.UnaryExpr:
+		if e.Op == token.SUB {
+			return -evalPredictor(t, e.X, vars)
+		}
+	case *ast.IndexExpr:
+		i, err := strconv.Atoi(e.Index.(*ast.BasicLit).Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vars[i]
+	}
+	t.Fatalf("Unexpected expression %#v", e)
+	return 0
+}
+
+func TestGoPredictor(t *testing.T) {
+	r := new(Regression)
+	if _, err := r.GoPredictor("model", "Predict"); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.SetObserved("Yield")
+	r.SetVar(0, "Temp")
+	r.Train(MakeDataPoints([][]float64{
+		{1, 1, 5}, {3, 2, 3}, {2, 3, 4}, {5, 4, 1}, {7, 5, 2},
+	}, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GoPredictor("model", "not valid"); err == nil {
+		t.Error("Expected an error for an invalid function name")
+	}
+
+	src, err := r.GoPredictor("model", "PredictYield")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "// PredictYield predicts Yield from the variables") || !strings.Contains(string(src), "//\t0: Temp\n") {
+		t.Errorf("Expected the function to be documented, got\n%s", src)
+	}
+
+	// Names are quoted if they could break out of their comment
+	r.SetVar(1, "Dose\nfunc init() { panic(0) }")
+	if src, err = r.GoPredictor("model", "PredictYield"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), `//	1: "Dose\nfunc init() { panic(0) }"`) {
+		t.Errorf("Expected the variable name to be quoted, got\n%s", src)
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), "model.go", src, 0)
+	if err != nil {
+		t.Fatalf("Generated source does not parse: %v\n%s", err, src)
+	}
+	if f.Name.Name != "model" || len(f.Imports) != 0 || len(f.Decls) != 1 {
+		t.Errorf("Expected a self-contained file in package model, got\n%s", src)
+	}
+	fn := f.Decls[0].(*ast.FuncDecl)
+	ret := fn.Body.List[0].(*ast.ReturnStmt).Results[0]
+	for _, vars := range [][]float64{{1, 2}, {-3, 0.5}} {
+		expected, _ := r.Predict(vars)
+		if got := evalPredictor(t, ret, vars); math.Abs(got-expected) > 1e-12 {
+			t.Errorf("Expected the generated predictor to give %v, got %v", expected, got)
+		}
+	}
+}
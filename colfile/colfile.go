@@ -0,0 +1,208 @@
+// Package colfile reads and writes a simple binary file of float64 columns that can be memory
+// mapped, for fitting the same large dataset repeatedly without parsing it each time. Unlike the
+// columnar module it depends only on the standard library and the regression package.
+package colfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unsafe"
+
+	"github.com/Synthace/regression"
+)
+
+var (
+	// ErrFormat signals that a column file is malformed.
+	ErrFormat = errors.New("invalid column file")
+	// ErrMissingColumn signals that a requested column is not in the file.
+	ErrMissingColumn = errors.New("column not found")
+)
+
+// magic identifies a column file and the version of its layout.
+var magic = [8]byte{'R', 'G', 'C', 'O', 'L', 'S', '0', '1'}
+
+// A column file holds float64 columns laid out so that they can be used in place once the file is
+// memory mapped. All integers and values are little endian:
+//
+//	magic   [8]byte  "RGCOLS01"
+//	columns uint32
+//	names   uint32   length in bytes of the names section, including padding
+//	rows    uint64
+//	names   for each column a uint16 length and the name, padded with zeros to a multiple of 8 bytes
+//	data    for each column in turn, rows float64 values
+const headerSize = 24
+
+// Write writes the named columns, which must all have the same length, as a column file that
+// Open can memory map, so that a large dataset can be fitted repeatedly without parsing it again.
+func Write(w io.Writer, names []string, columns [][]float64) error {
+	if len(names) != len(columns) || len(columns) == 0 {
+		return fmt.Errorf("%w: %d names for %d columns", ErrFormat, len(names), len(columns))
+	}
+	var section bytes.Buffer
+	seen := make(map[string]bool, len(names))
+	for j, name := range names {
+		if len(name) > math.MaxUint16 || seen[name] {
+			return fmt.Errorf("%w: column name %q is too long or repeated", ErrFormat, name)
+		}
+		if len(columns[j]) != len(columns[0]) {
+			return fmt.Errorf("%w: column %q has %d rows, expected %d", ErrFormat, name, len(columns[j]), len(columns[0]))
+		}
+		seen[name] = true
+		binary.Write(&section, binary.LittleEndian, uint16(len(name)))
+		section.WriteString(name)
+	}
+	section.Write(make([]byte, (8-section.Len()%8)%8))
+	rows := len(columns[0])
+
+	b := bufio.NewWriter(w)
+	header := make([]byte, headerSize)
+	copy(header, magic[:])
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(columns)))
+	binary.LittleEndian.PutUint32(header[12:], uint32(section.Len()))
+	binary.LittleEndian.PutUint64(header[16:], uint64(rows))
+	b.Write(header)
+	b.Write(section.Bytes())
+	var value [8]byte
+	for _, col := range columns {
+		for _, v := range col {
+			binary.LittleEndian.PutUint64(value[:], math.Float64bits(v))
+			b.Write(value[:])
+		}
+	}
+	return b.Flush()
+}
+
+// File is a column file opened by Open. Its columns refer to the mapped file, so they
+// are only valid until Close is called.
+type File struct {
+	names   []string
+	rows    int
+	data    []byte
+	offsets map[string]int
+	unmap   func() error
+}
+
+// Open opens a column file written by Write, memory mapping it where the operating
+// system allows and otherwise reading it into memory.
+func Open(path string) (*File, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parse(data)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+	f.unmap = unmap
+	return f, nil
+}
+
+// parse reads the header of a column file and checks that it holds all of its columns.
+func parse(data []byte) (*File, error) {
+	if len(data) < headerSize || !bytes.Equal(data[:8], magic[:]) {
+		return nil, fmt.Errorf("%w: not a column file", ErrFormat)
+	}
+	cols := int(binary.LittleEndian.Uint32(data[8:]))
+	section := int(binary.LittleEndian.Uint32(data[12:]))
+	rows := binary.LittleEndian.Uint64(data[16:])
+	start := headerSize + section
+	// Compare by division, as the size of the columns computed from the header can overflow
+	if cols == 0 || section%8 != 0 || start > len(data) || rows > uint64(len(data)-start)/8/uint64(cols) ||
+		uint64(len(data)-start) != 8*rows*uint64(cols) {
+		return nil, fmt.Errorf("%w: %d bytes do not hold %d columns of %d rows", ErrFormat, len(data), cols, rows)
+	}
+
+	f := &File{rows: int(rows), data: data, offsets: make(map[string]int, cols)}
+	names := data[headerSize:start]
+	for j := 0; j < cols; j++ {
+		if len(names) < 2 || len(names) < 2+int(binary.LittleEndian.Uint16(names)) {
+			return nil, fmt.Errorf("%w: truncated name of column %d", ErrFormat, j)
+		}
+		n := int(binary.LittleEndian.Uint16(names))
+		name := string(names[2 : 2+n])
+		names = names[2+n:]
+		if _, ok := f.offsets[name]; ok {
+			return nil, fmt.Errorf("%w: repeated column %q", ErrFormat, name)
+		}
+		f.names = append(f.names, name)
+		f.offsets[name] = start + 8*j*f.rows
+	}
+	return f, nil
+}
+
+// Names returns the names of the columns in the order they were written.
+func (f *File) Names() []string {
+	return append([]string(nil), f.names...)
+}
+
+// Rows returns the number of rows of each column.
+func (f *File) Rows() int {
+	return f.rows
+}
+
+// Column returns the values of the named column. On little endian machines the values are read in
+// place from the mapped file, so the slice must not be used after Close or written to; elsewhere
+// they are copied.
+func (f *File) Column(name string) ([]float64, error) {
+	offset, ok := f.offsets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMissingColumn, name)
+	}
+	if f.rows == 0 {
+		return []float64{}, nil
+	}
+	raw := f.data[offset : offset+8*f.rows]
+	if littleEndian && uintptr(unsafe.Pointer(&raw[0]))%unsafe.Alignof(float64(0)) == 0 {
+		return unsafe.Slice((*float64)(unsafe.Pointer(&raw[0])), f.rows), nil
+	}
+	col := make([]float64, f.rows)
+	for i := range col {
+		col[i] = math.Float64frombits(binary.LittleEndian.Uint64(raw[8*i:]))
+	}
+	return col, nil
+}
+
+// DataPoints returns a data point for each row of the file, with the observed value from the column
+// observed and the variables from the columns named by variables, in order. The variables of each row
+// are copied so that the data points remain valid after Close.
+func (f *File) DataPoints(observed string, variables ...string) (regression.DataPoints, error) {
+	obs, err := f.Column(observed)
+	if err != nil {
+		return nil, err
+	}
+	vars := make([][]float64, len(variables))
+	for j, name := range variables {
+		if vars[j], err = f.Column(name); err != nil {
+			return nil, err
+		}
+	}
+	values := make([]float64, f.rows*len(vars))
+	points := make(regression.DataPoints, f.rows)
+	for i := range points {
+		row := values[i*len(vars) : (i+1)*len(vars) : (i+1)*len(vars)]
+		for j := range vars {
+			row[j] = vars[j][i]
+		}
+		points[i] = regression.DataPoint(obs[i], row)
+	}
+	return points, nil
+}
+
+// Close releases the mapping of the file. Columns returned by Column must not be used afterwards.
+func (f *File) Close() error {
+	unmap := f.unmap
+	f.data, f.offsets, f.unmap = nil, nil, func() error { return nil }
+	return unmap()
+}
+
+// littleEndian reports whether the machine stores float64 values in the byte order of column files.
+var littleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
@@ -0,0 +1,117 @@
+package colfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Synthace/regression"
+)
+
+func TestFile(t *testing.T) {
+	temp := []float64{1, 2, 3, 4, 5, 6}
+	pH := []float64{7, 6.5, 7, 7.5, 7, 6}
+	yield := make([]float64, len(temp))
+	for i := range yield {
+		yield[i] = 1 + 2*temp[i] - 3*pH[i] + float64(i%2)
+	}
+	var b bytes.Buffer
+	if err := Write(&b, []string{"Yield", "Temp", "pH"}, [][]float64{yield, temp, pH}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "data.cols")
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if names := f.Names(); f.Rows() != 6 || len(names) != 3 || names[2] != "pH" {
+		t.Errorf("Expected 3 columns of 6 rows, got %v of %d", names, f.Rows())
+	}
+	col, err := f.Column("pH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range pH {
+		if col[i] != pH[i] {
+			t.Errorf("Expected pH %v, got %v", pH, col)
+			break
+		}
+	}
+
+	points, err := f.DataPoints("Yield", "Temp", "pH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := new(regression.Regression)
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	rows := make([][]float64, len(yield))
+	for i := range rows {
+		rows[i] = []float64{yield[i], temp[i], pH[i]}
+	}
+	want := new(regression.Regression)
+	want.Train(regression.MakeDataPoints(rows, 0)...)
+	want.Run()
+	for i, c := range want.GetCoeffs() {
+		if r.Coeff(i) != c {
+			t.Errorf("Expected coefficient %d to be %v, got %v", i, c, r.Coeff(i))
+		}
+	}
+
+	if _, err := f.Column("Batch"); !errors.Is(err, ErrMissingColumn) {
+		t.Errorf("Expected ErrMissingColumn, got %v", err)
+	}
+	if _, err := f.DataPoints("Yield", "Batch"); !errors.Is(err, ErrMissingColumn) {
+		t.Errorf("Expected ErrMissingColumn for a variable, got %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Error(err)
+	}
+	if points[0].Variables[0] != temp[0] || points[5].Observed != yield[5] {
+		t.Error("Expected data points to remain valid after Close")
+	}
+}
+
+func TestFileFormat(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, []string{"x", "x"}, [][]float64{{1}, {2}}); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected ErrFormat for repeated names, got %v", err)
+	}
+	if err := Write(&b, []string{"x", "y"}, [][]float64{{1}, {2, 3}}); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected ErrFormat for ragged columns, got %v", err)
+	}
+
+	if err := Write(&b, nil, nil); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected ErrFormat for no columns, got %v", err)
+	}
+
+	b.Reset()
+	Write(&b, []string{"x", "y"}, [][]float64{{1, 2}, {3, 4}})
+	good := b.Bytes()
+	// A header of rows without columns, which would otherwise match the empty data section
+	noColumns := append([]byte(nil), good[:headerSize]...)
+	binary.LittleEndian.PutUint32(noColumns[8:], 0)
+	binary.LittleEndian.PutUint32(noColumns[12:], 0)
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("NOTCOLS!"), good[8:]...),
+		"truncated": good[:len(good)-1],
+		"columns":   noColumns,
+	} {
+		path := filepath.Join(t.TempDir(), name)
+		os.WriteFile(path, data, 0o644)
+		if _, err := Open(path); !errors.Is(err, ErrFormat) {
+			t.Errorf("%s: expected ErrFormat, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// DroppedVariable describes a variable that was removed from the model before fitting.
+// Its coefficient is reported as zero.
+type DroppedVariable struct {
+	Index  int
+	Name   string
+	Reason string
+}
+
+// SetCollinearityTolerance enables pruning of collinear variables before fitting. Variables are
+// considered in order, and one is dropped if regressing it on the offset and the variables kept
+// so far gives an R² above 1 - tol. A tolerance of zero, the default, disables pruning.
+func (r *Regression) SetCollinearityTolerance(tol float64) {
+	r.collinearityTol = tol
+}
+
+// SetDropConstant sets whether variables with zero variance are dropped before fitting. By default
+// Run returns an error naming them, as they cannot be distinguished from the offset.
+func (r *Regression) SetDropConstant(drop bool) {
+	r.dropConstant = drop
+}
+
+// Dropped returns the variables that were removed from the model before fitting, and why.
+func (r *Regression) Dropped() []DroppedVariable {
+	return r.dropped
+}
+
+// pruneColumns returns the columns of the design matrix to fit, recording any that are dropped.
+// It returns an error naming any constant columns unless they may be dropped.
+func (r *Regression) pruneColumns(variables *mat.Dense) ([]int, error) {
+	_, cols := variables.Dims()
+	active := []int{0}
+	r.dropped = nil
+	var constant []string
+	for j := 1; j < cols; j++ {
+		y := columns(variables, []int{j})
+		sd := stat.StdDev(mat.Col(nil, 0, y), nil)
+		if sd == 0 {
+			if r.dropConstant || r.collinearityTol > 0 {
+				r.drop(j, "constant, collinear with the offset")
+			} else {
+				constant = append(constant, r.GetVar(j-1))
+			}
+			continue
+		}
+		if r.collinearityTol <= 0 {
+			active = append(active, j)
+			continue
+		}
+
+		x := columns(variables, active)
+		r2, _, err := goodnessOfFit(x, y)
+		if err != nil || r2 <= 1-r.collinearityTol {
+			active = append(active, j)
+			continue
+		}
+
+		// Name the kept variables that contribute materially to the collinear combination
+		gamma, _ := leastSquares(x, y)
+		var names []string
+		for k, c := range active[1:] {
+			if math.Abs(gamma[k+1])*stat.StdDev(mat.Col(nil, k+1, x), nil) > 1e-3*sd {
+				names = append(names, r.GetVar(c-1))
+			}
+		}
+		if len(names) == 0 {
+			names = append(names, "the offset")
+		}
+		r.drop(j, fmt.Sprintf("collinear with %s (R² = %.6f)", strings.Join(names, ", "), r2))
+	}
+	if len(constant) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrConstantVariable, strings.Join(constant, ", "))
+	}
+	return active, nil
+}
+
+func (r *Regression) drop(col int, reason string) {
+	r.dropped = append(r.dropped, DroppedVariable{Index: col - 1, Name: r.GetVar(col - 1), Reason: reason})
+	r.logger().Warn("dropped variable", "variable", r.GetVar(col-1), "reason", reason)
+}
+
+// expandColumns maps coefficients, and the covariance, fitted on the active columns back onto all cols
+// columns of the design matrix. Dropped columns have a zero coefficient and an unknown variance.
+func (r *Regression) expandColumns(c []float64, active []int, cols int) []float64 {
+	if len(active) == cols {
+		return c
+	}
+	full := make([]float64, cols)
+	for k, j := range active {
+		full[j] = c[k]
+	}
+	if r.cov != nil {
+		cov := mat.NewSymDense(cols, nil)
+		for j := 0; j < cols; j++ {
+			cov.SetSym(j, j, math.NaN())
+		}
+		for k, j := range active {
+			for l, m := range active[:k+1] {
+				cov.SetSym(j, m, r.cov.At(k, l))
+			}
+		}
+		r.cov = cov
+	}
+	return full
+}
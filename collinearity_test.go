@@ -0,0 +1,93 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func collinearData() []*dataPoint {
+	var points []*dataPoint
+	for i := 0; i < 20; i++ {
+		a := float64(i)
+		b := math.Sin(float64(i))
+		points = append(points, DataPoint(1+2*a+3*b, []float64{a, 2*a + 3, b, 7}))
+	}
+	return points
+}
+
+func TestCollinearityPruning(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "A")
+	r.SetVar(1, "Twice A")
+	r.SetVar(2, "B")
+	r.SetVar(3, "Seven")
+	r.Train(collinearData()...)
+	r.SetCollinearityTolerance(1e-8)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	dropped := r.Dropped()
+	if len(dropped) != 2 {
+		t.Fatalf("Expected 2 dropped variables, got %v", dropped)
+	}
+	if dropped[0].Index != 1 || dropped[0].Name != "Twice A" || !strings.HasPrefix(dropped[0].Reason, "collinear with A (") {
+		t.Errorf("Unexpected dropped variable %+v", dropped[0])
+	}
+	if dropped[1].Index != 3 || !strings.Contains(dropped[1].Reason, "constant") {
+		t.Errorf("Unexpected dropped variable %+v", dropped[1])
+	}
+
+	expected := []float64{1, 2, 0, 3, 0}
+	for i, c := range r.GetCoeffs() {
+		if math.Abs(c-expected[i]) > 1e-9 {
+			t.Errorf("Expected coefficient %v to be %v, got %v", i, expected[i], c)
+		}
+	}
+	if !math.IsNaN(r.StdErr(2)) || math.IsNaN(r.StdErr(3)) {
+		t.Errorf("Expected unknown standard errors only for dropped variables, got %v", r.GetStdErrs())
+	}
+	p, err := r.Predict([]float64{10, 23, 0.5, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(p-22.5) > 1e-9 {
+		t.Errorf("Expected a prediction of 22.5, got %v", p)
+	}
+}
+
+func TestCollinearityPruningDisabled(t *testing.T) {
+	r := new(Regression)
+	r.Train(collinearData()...)
+	r.SetCollinearityTolerance(0)
+	r.Run()
+	if len(r.Dropped()) != 0 {
+		t.Errorf("Expected nothing to be dropped, got %v", r.Dropped())
+	}
+}
+
+func TestConstantVariables(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(3, "Seven")
+	for _, d := range collinearData() {
+		r.Train(DataPoint(d.Observed, []float64{d.Variables[0], d.Variables[2], 0, d.Variables[3]}))
+	}
+	err := r.Run()
+	if !errors.Is(err, ErrConstantVariable) || !strings.HasSuffix(err.Error(), ": X2, Seven") {
+		t.Errorf("Expected ErrConstantVariable naming X2 and Seven, got %v", err)
+	}
+
+	// A failed run leaves the model untrained, so it can be run again once the constants are dropped
+	r.SetDropConstant(true)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Dropped()) != 2 || r.Dropped()[0].Index != 2 || r.Dropped()[1].Index != 3 {
+		t.Errorf("Expected the constant variables to be dropped, got %v", r.Dropped())
+	}
+	if math.Abs(r.Coeff(1)-2) > 1e-9 || math.Abs(r.Coeff(2)-3) > 1e-9 || r.Coeff(3) != 0 || r.Coeff(4) != 0 {
+		t.Errorf("Unexpected coefficients %v", r.GetCoeffs())
+	}
+}
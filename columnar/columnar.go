@@ -0,0 +1,159 @@
+// Package columnar loads data points from Apache Arrow record batches and Parquet files, so that
+// data exported in a columnar format can be fitted without a round trip through CSV. It is a separate
+// module, so that programs that do not import it do not depend on Arrow.
+package columnar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Synthace/regression"
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+)
+
+var (
+	// ErrMissingColumn signals that a requested column is not in the schema.
+	ErrMissingColumn = errors.New("column not found")
+	// ErrColumnType signals that a column does not hold numeric values.
+	ErrColumnType = errors.New("column is not numeric")
+)
+
+// Columns selects the columns of a record batch holding the observed value and the variables, in
+// variable order. Label optionally names a column whose values label the data points.
+type Columns struct {
+	Observed  string
+	Variables []string
+	Label     string
+}
+
+// SetNames names the observed value and variables of r after the columns.
+func (c Columns) SetNames(r *regression.Regression) {
+	r.SetObserved(c.Observed)
+	for i, name := range c.Variables {
+		r.SetVar(i, name)
+	}
+}
+
+// FromRecord returns a data point for each row of the record batch. Null values are read as NaN,
+// so the rows holding them are dropped with a warning when the regression is run.
+func FromRecord(rec arrow.Record, c Columns) (regression.DataPoints, error) {
+	observed, err := floatColumn(rec, c.Observed)
+	if err != nil {
+		return nil, err
+	}
+	vars := make([][]float64, len(c.Variables))
+	for j, name := range c.Variables {
+		if vars[j], err = floatColumn(rec, name); err != nil {
+			return nil, err
+		}
+	}
+	var labels arrow.Array
+	if c.Label != "" {
+		if labels, err = column(rec, c.Label); err != nil {
+			return nil, err
+		}
+	}
+
+	points := make(regression.DataPoints, rec.NumRows())
+	for i := range points {
+		row := make([]float64, len(vars))
+		for j := range vars {
+			row[j] = vars[j][i]
+		}
+		if labels != nil && labels.IsValid(i) {
+			points[i] = regression.LabeledDataPoint(labels.ValueStr(i), observed[i], row)
+		} else {
+			points[i] = regression.DataPoint(observed[i], row)
+		}
+	}
+	return points, nil
+}
+
+// FromTable returns a data point for each row of the table, reading it a record batch at a time.
+func FromTable(tbl arrow.Table, c Columns) (regression.DataPoints, error) {
+	reader := array.NewTableReader(tbl, 0)
+	defer reader.Release()
+	var points regression.DataPoints
+	for reader.Next() {
+		batch, err := FromRecord(reader.Record(), c)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, batch...)
+	}
+	return points, reader.Err()
+}
+
+// ReadParquet reads the selected columns of a Parquet file and returns a data point for each row.
+func ReadParquet(ctx context.Context, r parquet.ReaderAtSeeker, c Columns) (regression.DataPoints, error) {
+	tbl, err := pqarrow.ReadTable(ctx, r, parquet.NewReaderProperties(memory.DefaultAllocator), pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+	defer tbl.Release()
+	return FromTable(tbl, c)
+}
+
+func column(rec arrow.Record, name string) (arrow.Array, error) {
+	indices := rec.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrMissingColumn, name)
+	}
+	return rec.Column(indices[0]), nil
+}
+
+// floatColumn converts a numeric column to float64, reading nulls as NaN.
+func floatColumn(rec arrow.Record, name string) ([]float64, error) {
+	col, err := column(rec, name)
+	if err != nil {
+		return nil, err
+	}
+	var at func(i int) float64
+	switch a := col.(type) {
+	case *array.Float64:
+		at = a.Value
+	case *array.Float32:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Int64:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Int32:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Int16:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Int8:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Uint64:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Uint32:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Uint16:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Uint8:
+		at = func(i int) float64 { return float64(a.Value(i)) }
+	case *array.Boolean:
+		at = func(i int) float64 {
+			if a.Value(i) {
+				return 1
+			}
+			return 0
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q has type %s", ErrColumnType, name, col.DataType())
+	}
+
+	values := make([]float64, col.Len())
+	for i := range values {
+		if col.IsNull(i) {
+			values[i] = math.NaN()
+		} else {
+			values[i] = at(i)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,87 @@
+package columnar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Synthace/regression"
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+)
+
+func testRecord() arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "Sample", Type: arrow.BinaryTypes.String},
+		{Name: "Yield", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "Temp", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "pH", Type: arrow.PrimitiveTypes.Float32},
+	}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).AppendValues([]string{"a", "b", "c", "d", "e"}, nil)
+	b.Field(1).(*array.Float64Builder).AppendValues([]float64{3, 5, 0, 9, 11}, []bool{true, true, false, true, true})
+	b.Field(2).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	b.Field(3).(*array.Float32Builder).AppendValues([]float32{7, 6.5, 7, 7.5, 7}, nil)
+	return b.NewRecord()
+}
+
+func TestFromRecord(t *testing.T) {
+	rec := testRecord()
+	defer rec.Release()
+	cols := Columns{Observed: "Yield", Variables: []string{"Temp", "pH"}, Label: "Sample"}
+
+	points, err := FromRecord(rec, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 5 {
+		t.Fatalf("Expected 5 points, got %d", len(points))
+	}
+	if points[1].Observed != 5 || points[1].Variables[0] != 2 || points[1].Variables[1] != 6.5 || points[1].Label != "b" {
+		t.Errorf("Unexpected point %+v", *points[1])
+	}
+	if !math.IsNaN(points[2].Observed) {
+		t.Errorf("Expected a null to be read as NaN, got %v", points[2].Observed)
+	}
+
+	r := new(regression.Regression)
+	cols.SetNames(r)
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if w := r.Warnings(); r.GetVar(1) != "pH" || len(w) == 0 || w[0].Kind != regression.DroppedRows {
+		t.Errorf("Expected named variables and a dropped row warning, got %q and %v", r.GetVar(1), w)
+	}
+
+	if _, err := FromRecord(rec, Columns{Observed: "Yield", Variables: []string{"Pressure"}}); !errors.Is(err, ErrMissingColumn) {
+		t.Errorf("Expected ErrMissingColumn, got %v", err)
+	}
+	if _, err := FromRecord(rec, Columns{Observed: "Sample"}); !errors.Is(err, ErrColumnType) {
+		t.Errorf("Expected ErrColumnType, got %v", err)
+	}
+}
+
+func TestReadParquet(t *testing.T) {
+	rec := testRecord()
+	defer rec.Release()
+	tbl := array.NewTableFromRecords(rec.Schema(), []arrow.Record{rec})
+	defer tbl.Release()
+
+	var buf bytes.Buffer
+	if err := pqarrow.WriteTable(tbl, &buf, 2, nil, pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatal(err)
+	}
+	points, err := ReadParquet(context.Background(), bytes.NewReader(buf.Bytes()), Columns{Observed: "Yield", Variables: []string{"Temp", "pH"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 5 || points[4].Observed != 11 || points[4].Variables[1] != 7 {
+		t.Errorf("Unexpected points %v", points)
+	}
+}
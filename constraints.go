@@ -0,0 +1,260 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// linearConstraint restricts a linear combination of the coefficients to a value.
+type linearConstraint struct {
+	coeffs []float64
+	value  float64
+}
+
+// AddConstraint restricts the fit so that the sum of coeffs[i]*β[i] over the coefficients β, starting
+// with the offset, equals value. For example {0, 1, 1, 1} with value 1 makes the coefficients of three
+// variables sum to one, as in a mixture model. The constraints must be linearly independent. The
+// constrained coefficients are found by least squares in the null space of the constraints, and the
+// residual degrees of freedom gain one for each constraint.
+func (r *Regression) AddConstraint(coeffs []float64, value float64) {
+	r.constraints = append(r.constraints, linearConstraint{coeffs: coeffs, value: value})
+}
+
+// constrainedLeastSquares fits the coefficients subject to the equality constraints and records their
+// covariance.
+func (r *Regression) constrainedLeastSquares(observed, variables *mat.Dense) ([]float64, error) {
+	n, k := variables.Dims()
+	m := len(r.constraints)
+	c := mat.NewDense(m, k, nil)
+	d := mat.NewDense(m, 1, nil)
+	for i, con := range r.constraints {
+		if len(con.coeffs) != k {
+			return nil, fmt.Errorf("%w: constraint %d has %d elements, expected %d", ErrConstraint, i, len(con.coeffs), k)
+		}
+		c.SetRow(i, con.coeffs)
+		d.Set(i, 0, con.value)
+	}
+	if n <= k-m {
+		return nil, fmt.Errorf("%w: %d observations for %d free coefficients", ErrTooManyVars, n, k-m)
+	}
+
+	// The coefficients are a particular solution of the constraints plus a combination of the
+	// null space basis z
+	var svd mat.SVD
+	if !svd.Factorize(c, mat.SVDFull) || svd.Rank(1e-12) < m {
+		return nil, fmt.Errorf("%w: the constraints are not linearly independent", ErrConstraint)
+	}
+	var b0 mat.Dense
+	svd.SolveTo(&b0, d, m)
+	var v mat.Dense
+	svd.VTo(&v)
+	z := v.Slice(0, k, m, k)
+
+	var xz, y0 mat.Dense
+	xz.Mul(variables, z)
+	y0.Mul(variables, &b0)
+	y0.Sub(observed, &y0)
+	gamma, err := leastSquares(&xz, &y0)
+	if err != nil {
+		return nil, err
+	}
+	var b mat.Dense
+	b.Mul(z, mat.NewDense(k-m, 1, gamma))
+	b.Add(&b, &b0)
+	coeffs := mat.Col(nil, 0, &b)
+
+	// Cov(β) = σ² z (zᵀxᵀxz)⁻¹ zᵀ
+	r.residualDF = n - k + m
+	r.cov = nil
+	if inv, err := crossProductInverse(&xz); err == nil {
+		sigma2 := sumOfSquaredResiduals(variables, observed, coeffs) / float64(r.residualDF)
+		var zi, cov mat.Dense
+		zi.Mul(z, inv)
+		cov.Mul(&zi, z.T())
+		r.cov = mat.NewSymDense(k, nil)
+		for i := 0; i < k; i++ {
+			for j := i; j < k; j++ {
+				r.cov.SetSym(i, j, sigma2*cov.At(i, j))
+			}
+			// Coefficients fixed by the constraints have zero variance, up to rounding
+			r.cov.SetSym(i, i, math.Max(r.cov.At(i, i), 0))
+		}
+	}
+	return coeffs, nil
+}
+
+// CoeffSign restricts the sign of a coefficient.
+type CoeffSign int
+
+const (
+	// Unconstrained allows a coefficient to take any value.
+	Unconstrained CoeffSign = iota
+	// NonNegative restricts a coefficient to be zero or more.
+	NonNegative
+	// NonPositive restricts a coefficient to be zero or less.
+	NonPositive
+)
+
+// SetCoeffSign restricts the sign of the coefficient of variable i, for effects that domain knowledge
+// says can only go one way. Sign constrained fits use the Lawson and Hanson active set method. A
+// coefficient held at zero by its constraint has an unknown standard error.
+func (r *Regression) SetCoeffSign(i int, sign CoeffSign) {
+	if r.signs == nil {
+		r.signs = make(map[int]CoeffSign)
+	}
+	r.signs[i] = sign
+}
+
+// signConstrained reports whether any coefficient has a sign constraint.
+func (r *Regression) signConstrained() bool {
+	for _, sign := range r.signs {
+		if sign != Unconstrained {
+			return true
+		}
+	}
+	return false
+}
+
+// signConstrainedLeastSquares fits the coefficients subject to the sign constraints, using the Lawson and
+// Hanson active set method on the design with non-positive columns negated. active maps the columns of
+// variables to the columns of the full design matrix.
+func (r *Regression) signConstrainedLeastSquares(observed, variables *mat.Dense, active []int) ([]float64, error) {
+	n, k := variables.Dims()
+	x := mat.DenseCopyOf(variables)
+	bounded := make([]bool, k)
+	negated := make([]bool, k)
+	for col, j := range active {
+		if j == 0 {
+			continue
+		}
+		switch r.signs[j-1] {
+		case NonNegative:
+			bounded[col] = true
+		case NonPositive:
+			bounded[col] = true
+			negated[col] = true
+			for i := 0; i < n; i++ {
+				x.Set(i, col, -x.At(i, col))
+			}
+		}
+	}
+
+	// The passive set starts with the unbounded columns, and bounded columns start at zero
+	passive := make([]bool, k)
+	for j := range passive {
+		passive[j] = !bounded[j]
+	}
+	b := make([]float64, k)
+	solve := func() ([]float64, error) {
+		var cols []int
+		for j, p := range passive {
+			if p {
+				cols = append(cols, j)
+			}
+		}
+		z := make([]float64, k)
+		if len(cols) == 0 {
+			return z, nil
+		}
+		sub, err := leastSquares(columns(x, cols), observed)
+		if err != nil {
+			return nil, err
+		}
+		for l, j := range cols {
+			z[j] = sub[l]
+		}
+		return z, nil
+	}
+	z, err := solve()
+	if err != nil {
+		return nil, err
+	}
+	copy(b, z)
+
+	const tol = 1e-10
+	iterations, converged := 0, false
+	for ; iterations < r.convergence.maxIterations(3*k); iterations++ {
+		// Move the bounded column whose gradient most improves the fit into the passive set
+		residuals := mat.NewVecDense(n, nil)
+		residuals.MulVec(x, mat.NewVecDense(k, b))
+		residuals.SubVec(observed.ColView(0), residuals)
+		r.reportProgress(Progress{Stage: "fit", Rows: n, Iteration: iterations, Loss: mat.Dot(residuals, residuals)})
+		gradient := mat.NewVecDense(k, nil)
+		gradient.MulVec(x.T(), residuals)
+		next, best := -1, tol*mat.Norm(residuals, 2)
+		for j := range b {
+			if bounded[j] && !passive[j] && gradient.AtVec(j) > best {
+				next, best = j, gradient.AtVec(j)
+			}
+		}
+		if next < 0 {
+			converged = true
+			break
+		}
+		passive[next] = true
+
+		for {
+			z, err = solve()
+			if err != nil {
+				return nil, err
+			}
+			feasible := true
+			alpha := 1.0
+			for j := range z {
+				if bounded[j] && passive[j] && z[j] <= 0 {
+					feasible = false
+					alpha = math.Min(alpha, b[j]/(b[j]-z[j]))
+				}
+			}
+			if feasible {
+				copy(b, z)
+				break
+			}
+			// Step back to the boundary and fix the columns that reach it at zero
+			for j := range b {
+				b[j] += alpha * (z[j] - b[j])
+				if bounded[j] && passive[j] && b[j] <= tol {
+					b[j] = 0
+					passive[j] = false
+				}
+			}
+		}
+	}
+	r.iterations, r.converged = iterations, converged
+	r.logger().Debug("sign constrained least squares finished", "iterations", iterations, "converged", converged)
+
+	// The covariance is that of least squares on the free columns
+	var free []int
+	for j, p := range passive {
+		if p {
+			free = append(free, j)
+		}
+	}
+	r.residualDF = n - len(free)
+	r.cov = nil
+	if inv, err := crossProductInverse(columns(x, free)); err == nil {
+		sigma2 := sumOfSquaredResiduals(x, observed, b) / float64(r.residualDF)
+		r.cov = mat.NewSymDense(k, nil)
+		for j := 0; j < k; j++ {
+			r.cov.SetSym(j, j, math.NaN())
+		}
+		for l, j := range free {
+			for m, o := range free[:l+1] {
+				v := sigma2 * inv.At(l, m)
+				if negated[j] != negated[o] {
+					v = -v
+				}
+				r.cov.SetSym(j, o, v)
+			}
+		}
+	}
+
+	for j := range b {
+		if negated[j] {
+			b[j] = -b[j]
+		}
+	}
+	return b, nil
+}
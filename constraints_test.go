@@ -0,0 +1,118 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConstrainedLeastSquares(t *testing.T) {
+	// Constraining β1 + β2 = 1 is the same as regressing y - x2 on x1 - x2
+	r := new(Regression)
+	r.AddConstraint([]float64{0, 1, 1}, 1)
+	reparameterised := new(Regression)
+	for i := 0; i < 25; i++ {
+		x1, x2 := math.Sin(float64(i)), float64(i%5)
+		y := 0.5 + 0.3*x1 + 0.6*x2 + 0.1*math.Cos(float64(i*i))
+		r.Train(DataPoint(y, []float64{x1, x2}))
+		reparameterised.Train(DataPoint(y-x2, []float64{x1 - x2}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	reparameterised.Run()
+
+	if sum := r.Coeff(1) + r.Coeff(2); math.Abs(sum-1) > 1e-12 {
+		t.Errorf("Expected the coefficients to sum to 1, got %v", sum)
+	}
+	for _, v := range [][2]float64{
+		{r.Coeff(0), reparameterised.Coeff(0)},
+		{r.Coeff(1), reparameterised.Coeff(1)},
+		{r.StdErr(1), reparameterised.StdErr(1)},
+		{r.StdErr(2), reparameterised.StdErr(1)},
+		{r.ResidualStdErr(), reparameterised.ResidualStdErr()},
+	} {
+		if math.Abs(v[0]-v[1]) > 1e-9 {
+			t.Errorf("Expected %v, got %v", v[1], v[0])
+		}
+	}
+}
+
+func TestConstraintErrors(t *testing.T) {
+	train := func(r *Regression) {
+		for i := 0; i < 10; i++ {
+			r.Train(DataPoint(float64(i), []float64{float64(i * i), math.Sin(float64(i))}))
+		}
+	}
+
+	r := new(Regression)
+	r.AddConstraint([]float64{0, 1}, 1)
+	train(r)
+	if err := r.Run(); !errors.Is(err, ErrConstraint) {
+		t.Errorf("Expected ErrConstraint for the wrong length, got %v", err)
+	}
+
+	r = new(Regression)
+	r.AddConstraint([]float64{0, 1, 1}, 1)
+	r.AddConstraint([]float64{0, 2, 2}, 1)
+	train(r)
+	if err := r.Run(); !errors.Is(err, ErrConstraint) {
+		t.Errorf("Expected ErrConstraint for dependent constraints, got %v", err)
+	}
+
+	r = new(Regression)
+	r.AddConstraint([]float64{0, 1, 1}, 1)
+	r.SetFixedEffects(true)
+	train(r)
+	if err := r.Run(); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions, got %v", err)
+	}
+}
+
+func TestSignConstrainedLeastSquares(t *testing.T) {
+	// The unconstrained fit gives x1 a small negative coefficient and x2 a positive one
+	train := func(r *Regression) {
+		for i := 0; i < 30; i++ {
+			x1, x2, x3 := math.Sin(float64(i)), float64(i%6), math.Cos(float64(3*i))
+			y := 1 - 0.05*x1 + 0.8*x2 - 2*x3 + 0.3*math.Cos(float64(i*i))
+			r.Train(DataPoint(y, []float64{x1, x2, x3}))
+		}
+	}
+	unconstrained := new(Regression)
+	train(unconstrained)
+	unconstrained.Run()
+	if unconstrained.Coeff(1) >= 0 {
+		t.Fatalf("Expected a negative unconstrained coefficient, got %v", unconstrained.Coeff(1))
+	}
+
+	r := new(Regression)
+	r.SetCoeffSign(0, NonNegative)
+	r.SetCoeffSign(2, NonPositive)
+	train(r)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Coeff(1) != 0 || !math.IsNaN(r.StdErr(1)) {
+		t.Errorf("Expected variable 0 to be held at zero, got %v ± %v", r.Coeff(1), r.StdErr(1))
+	}
+	if r.Coeff(3) >= 0 {
+		t.Errorf("Expected variable 2 to stay negative, got %v", r.Coeff(3))
+	}
+
+	// The remaining coefficients are the least squares fit without variable 0
+	reduced := new(Regression)
+	for _, d := range r.Data {
+		reduced.Train(DataPoint(d.Observed, []float64{d.Variables[1], d.Variables[2]}))
+	}
+	reduced.Run()
+	for _, v := range [][2]float64{
+		{r.Coeff(0), reduced.Coeff(0)},
+		{r.Coeff(2), reduced.Coeff(1)},
+		{r.Coeff(3), reduced.Coeff(2)},
+		{r.StdErr(3), reduced.StdErr(2)},
+	} {
+		if math.Abs(v[0]-v[1]) > 1e-9 {
+			t.Errorf("Expected %v, got %v", v[1], v[0])
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Convergence controls when an iterative solver stops: the Levenberg-Marquardt fit of Nonlinear, the
+// maximum likelihood fit of a Regression with censored or truncated data, the active set fit of a
+// Regression with sign constraints and the hyperparameter search of a GaussianProcess. A zero field
+// keeps the default of the solver.
+type Convergence struct {
+	// MaxIterations is the largest number of iterations the solver takes.
+	MaxIterations int
+	// Tolerance is the relative change in the objective, the residual sum of squares or the log
+	// likelihood, below which the solver has converged. The active set solver ends exactly and ignores it.
+	Tolerance float64
+	// StepSize bounds the steps of the solver, for fits that overshoot from poor starting values. It
+	// limits the change in each parameter of a Levenberg-Marquardt iteration to StepSize·max(|θ|, 1) and
+	// sets the initial simplex of the Nelder-Mead search, in log hyperparameters. Solvers without a step
+	// of their own, the line searches of the maximum likelihood fit and the active set, ignore it.
+	StepSize float64
+}
+
+// maxIterations returns the iteration limit, or def if none is set.
+func (c Convergence) maxIterations(def int) int {
+	if c.MaxIterations > 0 {
+		return c.MaxIterations
+	}
+	return def
+}
+
+// tolerance returns the convergence tolerance, or def if none is set.
+func (c Convergence) tolerance(def float64) float64 {
+	if c.Tolerance > 0 {
+		return c.Tolerance
+	}
+	return def
+}
+
+// settings returns the settings of a gonum optimization with the iteration limit and tolerance, reporting
+// its iterations over rows data points to the progress function if it is not nil, or nil to use the
+// defaults of gonum.
+func (c Convergence) settings(progress func(Progress), rows int) *optimize.Settings {
+	if c.MaxIterations <= 0 && c.Tolerance <= 0 && progress == nil {
+		return nil
+	}
+	settings := &optimize.Settings{MajorIterations: c.MaxIterations}
+	if progress != nil {
+		settings.Recorder = progressRecorder{fn: progress, rows: rows}
+	}
+	if c.Tolerance > 0 {
+		settings.Converger = &optimize.FunctionConverge{Relative: c.Tolerance, Iterations: 100}
+	}
+	return settings
+}
+
+// converged reports whether a gonum optimization ended at a minimum rather than at one of its limits.
+// A failed line search counts as converged, as it happens when the objective can no longer decrease in
+// floating point close to the minimum.
+func converged(status optimize.Status) bool {
+	switch status {
+	case optimize.IterationLimit, optimize.FunctionEvaluationLimit, optimize.GradientEvaluationLimit,
+		optimize.HessianEvaluationLimit, optimize.RuntimeLimit:
+		return false
+	}
+	return true
+}
+
+// limitStep scales step down so that no parameter changes by more than StepSize·max(|θ|, 1).
+func (c Convergence) limitStep(theta, step []float64) {
+	if c.StepSize <= 0 {
+		return
+	}
+	scale := 1.0
+	for j, s := range step {
+		if limit := c.StepSize * math.Max(math.Abs(theta[j]), 1); math.Abs(s) > limit {
+			scale = math.Min(scale, limit/math.Abs(s))
+		}
+	}
+	for j := range step {
+		step[j] *= scale
+	}
+}
+
+// SetConvergence sets when the iterative solvers of the regression stop, for data that is censored,
+// truncated or fitted with sign constraints. Other fits are solved directly.
+func (r *Regression) SetConvergence(c Convergence) {
+	r.convergence = c
+}
+
+// Iterations returns the number of iterations taken by an iterative solver in Run, or 0 for a fit
+// solved directly.
+func (r *Regression) Iterations() int {
+	return r.iterations
+}
+
+// Converged reports whether the fit converged. An iterative fit that stops at the iteration limit is
+// kept, with a NotConverged warning, and should be checked before use. Fits solved directly always converge.
+func (r *Regression) Converged() bool {
+	return r.hasRun && r.converged
+}
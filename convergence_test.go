@@ -0,0 +1,106 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestConvergence(t *testing.T) {
+	decay := NonlinearModel{
+		Name:   "exponential decay",
+		Params: []string{"y0", "k"},
+		Func: func(x, theta []float64) float64 {
+			return theta[0] * math.Exp(-theta[1]*x[0])
+		},
+	}
+	fit := func(c Convergence) (*Nonlinear, error) {
+		n := NewNonlinear(decay)
+		for x := 0.0; x < 5; x += 0.5 {
+			n.Train(DataPoint(3*math.Exp(-0.7*x), []float64{x}))
+		}
+		n.SetStart([]float64{1, 0.1})
+		n.SetConvergence(c)
+		return n, n.Run()
+	}
+	free, err := fit(Convergence{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !free.Converged() || free.Iterations() == 0 {
+		t.Errorf("Expected a converged fit with its iterations, got %v after %d", free.Converged(), free.Iterations())
+	}
+	if _, err := fit(Convergence{MaxIterations: 2}); !errors.Is(err, ErrNotConverged) {
+		t.Errorf("Expected ErrNotConverged after 2 iterations, got %v", err)
+	}
+	short, err := fit(Convergence{StepSize: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(short.Param(1)-0.7) > 1e-6 || short.Iterations() <= free.Iterations() {
+		t.Errorf("Expected short steps to reach k = 0.7 in more than %d iterations, got %v in %d", free.Iterations(), short.Param(1), short.Iterations())
+	}
+
+	// A censored regression stopped early is kept with a warning
+	censored := func(c Convergence) *Regression {
+		rng := rand.New(rand.NewSource(6))
+		r := new(Regression)
+		for i := 0; i < 200; i++ {
+			x := rng.Float64() * 2
+			if y := 1 + 2*x + rng.NormFloat64(); y < 3 {
+				r.Train(CensoredDataPoint(math.Inf(-1), 3, []float64{x}))
+			} else {
+				r.Train(DataPoint(y, []float64{x}))
+			}
+		}
+		r.SetConvergence(c)
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	notConverged := func(r *Regression) bool {
+		for _, w := range r.Warnings() {
+			if w.Kind == NotConverged {
+				return true
+			}
+		}
+		return false
+	}
+	if r := censored(Convergence{}); !r.Converged() || r.Iterations() == 0 || notConverged(r) {
+		t.Errorf("Expected the censored fit to converge, got %v after %d iterations with warnings %v", r.Converged(), r.Iterations(), r.Warnings())
+	}
+	r := censored(Convergence{MaxIterations: 1})
+	if r.Converged() || r.Iterations() != 1 {
+		t.Errorf("Expected the censored fit to stop after 1 iteration, got %v after %d", r.Converged(), r.Iterations())
+	}
+	if !notConverged(r) {
+		t.Errorf("Expected a NotConverged warning, got %v", r.Warnings())
+	}
+	// Resampled copies are fitted with the same convergence settings
+	bag, err := r.Bag(3, WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range bag.Models {
+		if m.Iterations() != 1 {
+			t.Errorf("Expected each bagged fit to stop after 1 iteration, got %d", m.Iterations())
+		}
+	}
+
+	// Least squares is solved directly
+	direct := new(Regression)
+	for x := 0.0; x < 5; x++ {
+		direct.Train(DataPoint(1+x*x, []float64{x}))
+	}
+	if direct.Converged() {
+		t.Error("Expected a regression that has not run not to have converged")
+	}
+	if err := direct.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !direct.Converged() || direct.Iterations() != 0 {
+		t.Errorf("Expected a direct fit to converge in 0 iterations, got %v after %d", direct.Converged(), direct.Iterations())
+	}
+}
@@ -1,59 +1,190 @@
 package regression
 
 import (
+	"fmt"
 	"math"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 type featureCross interface {
 	Calculate([]float64) []float64 //must return the same number of features each run
 	ExtendNames(map[int]string, int) int
+	// Inputs returns the indices of the variables the cross is calculated from.
+	Inputs() []int
+	// Outputs returns the number of features the cross generates.
+	Outputs() int
+	// ID returns an identifier of the cross and its parameters that is stable between runs, such
+	// as "pow(0,2)".
+	ID() string
 }
 
 type functionalCross struct {
-	functionName string
-	boundVars    []int
-	crossFn      func([]float64) []float64
+	id        string
+	boundVars []int
+	outputs   int
+	crossFn   func([]float64) []float64
+	nameFn    func([]string) []string
+	spec      CrossSpec
+}
+
+// crossID formats the identifier of a cross of the given type from its inputs and parameters.
+func crossID(kind string, vars []int, params ...float64) string {
+	var args []string
+	for _, v := range vars {
+		args = append(args, strconv.Itoa(v))
+	}
+	for _, p := range params {
+		args = append(args, strconv.FormatFloat(p, 'g', -1, 64))
+	}
+	return kind + "(" + strings.Join(args, ",") + ")"
+}
+
+// CrossSpec is the encoded form of a feature cross: the name under which its decoder is registered
+// and the parameters it was created with.
+type CrossSpec struct {
+	Type   string    `json:"type"`
+	Vars   []int     `json:"vars,omitempty"`
+	Params []float64 `json:"params,omitempty"`
+}
+
+// EncodableCross is a feature cross that can be saved with a model, by describing itself as a
+// CrossSpec whose type has a decoder registered with RegisterCross.
+type EncodableCross interface {
+	Calculate([]float64) []float64
+	ExtendNames(map[int]string, int) int
+	Inputs() []int
+	Outputs() int
+	ID() string
+	Spec() CrossSpec
+}
+
+var (
+	crossDecodersMu sync.RWMutex
+	crossDecoders   = map[string]func(CrossSpec) (EncodableCross, error){
+		"pow":        decodePowCross,
+		"multiplier": decodeMultiplierCross,
+	}
+)
+
+// RegisterCross registers the decoder of a type of feature cross, so that models using crosses of
+// that type can be loaded. It is usually called from an init function, and replaces any decoder
+// registered under the same name.
+func RegisterCross(name string, decode func(CrossSpec) (EncodableCross, error)) {
+	crossDecodersMu.Lock()
+	defer crossDecodersMu.Unlock()
+	crossDecoders[name] = decode
+}
+
+// encodeCross returns the spec of a feature cross, or ErrUnsupportedCross if it cannot be encoded.
+func encodeCross(cross featureCross) (CrossSpec, error) {
+	e, ok := cross.(EncodableCross)
+	if !ok {
+		return CrossSpec{}, fmt.Errorf("%w: %T cannot be encoded", ErrUnsupportedCross, cross)
+	}
+	spec := e.Spec()
+	crossDecodersMu.RLock()
+	defer crossDecodersMu.RUnlock()
+	if crossDecoders[spec.Type] == nil {
+		return CrossSpec{}, fmt.Errorf("%w: no decoder registered for %q", ErrUnsupportedCross, spec.Type)
+	}
+	return spec, nil
+}
+
+// decodeCross creates the feature cross described by spec using its registered decoder.
+func decodeCross(spec CrossSpec) (featureCross, error) {
+	crossDecodersMu.RLock()
+	decode := crossDecoders[spec.Type]
+	crossDecodersMu.RUnlock()
+	if decode == nil {
+		return nil, fmt.Errorf("%w: no decoder registered for %q", ErrUnsupportedCross, spec.Type)
+	}
+	cross, err := decode(spec)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %q feature cross: %w", spec.Type, err)
+	}
+	return cross, nil
+}
+
+func decodePowCross(spec CrossSpec) (EncodableCross, error) {
+	if len(spec.Vars) != 1 || len(spec.Params) != 1 {
+		return nil, fmt.Errorf("%w: a power cross needs one variable and one power", ErrModelFormat)
+	}
+	return PowCross(spec.Vars[0], spec.Params[0]).(EncodableCross), nil
+}
+
+func decodeMultiplierCross(spec CrossSpec) (EncodableCross, error) {
+	if len(spec.Vars) == 0 {
+		return nil, fmt.Errorf("%w: a multiplier cross needs variables", ErrModelFormat)
+	}
+	return MultiplierCross(spec.Vars...).(EncodableCross), nil
+}
+
+// Spec describes the cross for encoding.
+func (c *functionalCross) Spec() CrossSpec {
+	return c.spec
 }
 
 func (c *functionalCross) Calculate(input []float64) []float64 {
 	return c.crossFn(input)
 }
 
+// ExtendNames names the generated features after the inputs, if they are all named, and returns
+// the number of generated features.
 func (c *functionalCross) ExtendNames(input map[int]string, initialSize int) int {
+	names := make([]string, len(c.boundVars))
 	for i, varIndex := range c.boundVars {
-		if input[varIndex] != "" {
-			input[initialSize+i] = "(" + input[varIndex] + ")" + c.functionName
+		if input[varIndex] == "" {
+			return c.outputs
 		}
+		names[i] = input[varIndex]
 	}
-	return len(c.boundVars)
+	for i, name := range c.nameFn(names) {
+		input[initialSize+i] = name
+	}
+	return c.outputs
+}
+
+func (c *functionalCross) Inputs() []int {
+	return append([]int(nil), c.boundVars...)
+}
+
+func (c *functionalCross) Outputs() int {
+	return c.outputs
+}
+
+func (c *functionalCross) ID() string {
+	return c.id
 }
 
 // Feature cross based on computing the power of an input.
 func PowCross(i int, power float64) featureCross {
 	return &functionalCross{
-		functionName: "^" + strconv.FormatFloat(power, 'f', -1, 64),
-		boundVars:    []int{i},
+		id:        crossID("pow", []int{i}, power),
+		boundVars: []int{i},
+		outputs:   1,
+		spec:      CrossSpec{Type: "pow", Vars: []int{i}, Params: []float64{power}},
 		crossFn: func(vars []float64) []float64 {
 
 			return []float64{math.Pow(vars[i], power)}
 		},
+		nameFn: func(names []string) []string {
+			return []string{"(" + names[0] + ")^" + strconv.FormatFloat(power, 'f', -1, 64)}
+		},
 	}
 }
 
 // Feature cross based on the multiplication of multiple inputs.
 func MultiplierCross(vars ...int) featureCross {
-	name := ""
-	for i, v := range vars {
-		name += strconv.Itoa(v)
-		if i < (len(vars) - 1) {
-			name += "*"
-		}
-	}
-
 	return &functionalCross{
-		functionName: name,
-		boundVars:    vars,
+		id:        crossID("multiplier", vars),
+		boundVars: vars,
+		outputs:   1,
+		spec:      CrossSpec{Type: "multiplier", Vars: vars},
+		nameFn: func(names []string) []string {
+			return []string{"(" + strings.Join(names, ")*(") + ")"}
+		},
 		crossFn: func(input []float64) []float64 {
 			var output float64 = 1
 			for _, variableIndex := range vars {
@@ -1,6 +1,10 @@
 package regression
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
 	"testing"
 )
 
@@ -43,3 +47,131 @@ func TestFunctionalCrossExtendNames(t *testing.T) {
 		t.Error("Expected 1 new var")
 	}
 }
+
+// logCross is a feature cross defined outside the package's own crosses, to test registration.
+type logCross struct {
+	i int
+}
+
+func (c logCross) Calculate(vars []float64) []float64 {
+	return []float64{math.Log(vars[c.i])}
+}
+
+func (c logCross) ExtendNames(names map[int]string, size int) int {
+	names[size] = "log(" + names[c.i] + ")"
+	return 1
+}
+
+func (c logCross) Inputs() []int {
+	return []int{c.i}
+}
+
+func (c logCross) Outputs() int {
+	return 1
+}
+
+func (c logCross) ID() string {
+	return "log(" + strconv.Itoa(c.i) + ")"
+}
+
+func (c logCross) Spec() CrossSpec {
+	return CrossSpec{Type: "test.log", Vars: []int{c.i}}
+}
+
+func TestCrossEncoding(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(logCross{i: 0})
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := json.Marshal(r); !errors.Is(err, ErrUnsupportedCross) {
+		t.Errorf("Expected ErrUnsupportedCross for an unregistered cross, got %v", err)
+	}
+
+	RegisterCross("test.log", func(spec CrossSpec) (EncodableCross, error) {
+		return logCross{i: spec.Vars[0]}, nil
+	})
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(Regression)
+	if err := json.Unmarshal(b, loaded); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict([]float64{7})
+	if got, err := loaded.Predict([]float64{7}); err != nil || got != want {
+		t.Errorf("Expected prediction %v, got %v, %v", want, got, err)
+	}
+	if got := loaded.GetVar(2); got != r.GetVar(2) {
+		t.Errorf("Expected variable name %v, got %v", r.GetVar(2), got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"version":1,"variables":["x"],"coefficients":[1,2],"crosses":[{"type":"unknown"}]}`), new(Regression)); !errors.Is(err, ErrUnsupportedCross) {
+		t.Errorf("Expected ErrUnsupportedCross for an unknown cross, got %v", err)
+	}
+}
+
+func TestCrossIntrospection(t *testing.T) {
+	pow, mult := PowCross(1, 2), MultiplierCross(0, 2)
+	if pow.ID() != "pow(1,2)" || mult.ID() != "multiplier(0,2)" {
+		t.Errorf("Expected IDs pow(1,2) and multiplier(0,2), got %v and %v", pow.ID(), mult.ID())
+	}
+	if in := mult.Inputs(); len(in) != 2 || in[0] != 0 || in[1] != 2 || mult.Outputs() != 1 {
+		t.Errorf("Expected inputs [0 2] and 1 output, got %v and %v", in, mult.Outputs())
+	}
+
+	// A multiplier cross generates a single, named feature
+	names := map[int]string{0: "a", 1: "b", 2: "c"}
+	if n := mult.ExtendNames(names, 3); n != 1 || names[3] != "(a)*(c)" || len(names) != 4 {
+		t.Errorf("Expected one feature named (a)*(c), got %d and %v", n, names)
+	}
+
+	r := new(Regression)
+	r.SetVar(0, "x")
+	r.SetVar(1, "z")
+	for i := 0; i < 10; i++ {
+		x, z := float64(i), float64(i*i%7)
+		r.Train(DataPoint(x+z, []float64{x, z}))
+	}
+	r.AddCross(MultiplierCross(0, 1))
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.GetVar(3); got != "(x)^2" {
+		t.Errorf("Expected the power cross to be named (x)^2 after the multiplier, got %v", got)
+	}
+	info := r.Crosses()
+	if len(info) != 2 || info[1].ID != "pow(0,2)" || len(info[1].Outputs) != 1 || info[1].Outputs[0] != 3 {
+		t.Errorf("Expected the power cross to generate variable 3, got %+v", info)
+	}
+}
+
+func TestCrossesLeaveDataUnchanged(t *testing.T) {
+	points := MakeDataPoints(anscombe, 0)
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range points {
+		if len(d.Variables) != 1 || d.Variables[0] != anscombe[i][1] {
+			t.Fatalf("Expected data point %d to keep its variables, got %v", i, d.Variables)
+		}
+	}
+
+	// The same points can train another regression with the same crosses
+	again := new(Regression)
+	again.AddCross(PowCross(0, 2))
+	again.Train(points...)
+	if err := again.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if again.NumFeatures() != 2 || again.Coeff(2) != r.Coeff(2) {
+		t.Errorf("Expected the refit to match with 2 features, got %d and %v", again.NumFeatures(), again.Coeff(2))
+	}
+}
@@ -0,0 +1,241 @@
+package regression
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// crossProductChunk is the number of rows accumulated by each job of AccumulateCrossProducts.
+const crossProductChunk = 4096
+
+// CrossProducts holds the sufficient statistics of a least squares fit with an offset, XᵀX, Xᵀy and
+// yᵀy, so that models can be fitted to data too large to hold as data points. Rows are accumulated
+// in a single pass, and statistics accumulated separately, such as on different cores or machines,
+// can be merged before fitting.
+type CrossProducts struct {
+	vars int
+	n    int
+	// xtx holds the upper triangle of XᵀX in row major order, including the column of ones
+	xtx []float64
+	xty []float64
+	yty float64
+}
+
+// NewCrossProducts returns empty cross products for data points with the given number of variables.
+func NewCrossProducts(vars int) *CrossProducts {
+	k := vars + 1
+	return &CrossProducts{vars: vars, xtx: make([]float64, k*k), xty: make([]float64, k)}
+}
+
+// N returns the number of rows accumulated.
+func (c *CrossProducts) N() int {
+	return c.n
+}
+
+// Add accumulates a row with observed value obs. Rows with NaN or infinite values are rejected with
+// ErrDesign, as they would make the whole fit undefined.
+func (c *CrossProducts) Add(obs float64, vars []float64) error {
+	if len(vars) != c.vars {
+		return fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), c.vars)
+	}
+	if math.IsNaN(obs) || math.IsInf(obs, 0) {
+		return fmt.Errorf("%w: observed value is %v", ErrDesign, obs)
+	}
+	for j, v := range vars {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: variable %d is %v", ErrDesign, j, v)
+		}
+	}
+	k := c.vars + 1
+	c.xtx[0]++
+	for j, v := range vars {
+		c.xtx[j+1] += v
+		row := c.xtx[(j+1)*k:]
+		for l := j; l < len(vars); l++ {
+			row[l+1] += v * vars[l]
+		}
+		c.xty[j+1] += v * obs
+	}
+	c.xty[0] += obs
+	c.yty += obs * obs
+	c.n++
+	return nil
+}
+
+// Merge adds the rows accumulated in o.
+func (c *CrossProducts) Merge(o *CrossProducts) error {
+	if o.vars != c.vars {
+		return fmt.Errorf("%w: merging cross products of %d variables with %d", ErrVariableCount, o.vars, c.vars)
+	}
+	for i, v := range o.xtx {
+		c.xtx[i] += v
+	}
+	for i, v := range o.xty {
+		c.xty[i] += v
+	}
+	c.yty += o.yty
+	c.n += o.n
+	return nil
+}
+
+// Fit fits the least squares model to the accumulated rows by Cholesky decomposition of XᵀX. Forming
+// XᵀX squares the condition number of the design, so ill-conditioned problems are better fitted by
+// Run. The model has no training data, so diagnostics that need it return ErrNotEnoughData.
+func (c *CrossProducts) Fit() (*Regression, error) {
+	k := c.vars + 1
+	if c.n < 3 {
+		return nil, fmt.Errorf("%w: %d rows accumulated, need at least 3", ErrNotEnoughData, c.n)
+	}
+	if c.n < k {
+		return nil, fmt.Errorf("%w: %d observations for %d variables and the offset", ErrTooManyVars, c.n, c.vars)
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(mat.NewSymDense(k, append([]float64(nil), c.xtx...))) {
+		return nil, fmt.Errorf("%w: the cross product matrix is not positive definite", ErrSingular)
+	}
+	xty := mat.NewVecDense(k, append([]float64(nil), c.xty...))
+	b := mat.NewVecDense(k, nil)
+	// An ill-conditioned XᵀX is reported as a mat.Condition error, but the solution is still returned
+	var condition mat.Condition
+	if err := chol.SolveVecTo(b, xty); err != nil && !errors.As(err, &condition) {
+		return nil, fmt.Errorf("%w: %v", ErrSingular, err)
+	}
+
+	n := float64(c.n)
+	r := &Regression{initialised: true, hasRun: true, rawVars: c.vars, coeff: b.RawVector().Data, residualDF: c.n - k}
+	r.totalSS = c.yty - c.xty[0]*c.xty[0]/n
+	r.residualSS = math.Max(0, c.yty-mat.Dot(b, xty))
+	r.R2 = 1 - r.residualSS/r.totalSS
+	r.AdjustedR2 = 1 - (1-r.R2)*(n-1)/float64(r.residualDF)
+	r.residualStdErr = math.Sqrt(r.residualSS / float64(r.residualDF))
+	r.Varianceobserved = r.totalSS / (n - 1)
+	r.VariancePredicted = (r.totalSS - r.residualSS) / (n - 1)
+	if r.residualDF > 0 {
+		r.cov = mat.NewSymDense(k, nil)
+		if err := chol.InverseTo(r.cov); err != nil && !errors.As(err, &condition) {
+			return nil, fmt.Errorf("%w: %v", ErrSingular, err)
+		}
+		r.cov.ScaleSym(r.residualSS/float64(r.residualDF), r.cov)
+	}
+	r.setFormula()
+	return r, nil
+}
+
+// AccumulateCrossProducts accumulates the cross products of rows in the format of MakeDataPoints,
+// with the observed value in column obsIndex, in parallel chunks that are merged once all are done.
+// The number of goroutines can be limited with WithConcurrency.
+func AccumulateCrossProducts(rows [][]float64, obsIndex int, opts ...Option) (*CrossProducts, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrNotEnoughData)
+	}
+	if obsIndex < 0 || obsIndex >= len(rows[0]) {
+		return nil, fmt.Errorf("%w: observed column %d of %d", ErrVariableIndex, obsIndex, len(rows[0]))
+	}
+	o := newOptions(opts)
+	vars := len(rows[0]) - 1
+	chunks := make([]*CrossProducts, (len(rows)+crossProductChunk-1)/crossProductChunk)
+	errs := make([]error, len(chunks))
+	parallelFor(o.concurrency, len(chunks), func(ch int) {
+		c := NewCrossProducts(vars)
+		x := make([]float64, vars)
+		for i := ch * crossProductChunk; i < len(rows) && i < (ch+1)*crossProductChunk; i++ {
+			if len(rows[i]) != vars+1 {
+				errs[ch] = &DataPointError{Index: i, Err: fmt.Errorf("%w: got %d columns, expected %d", ErrVariableCount, len(rows[i]), vars+1)}
+				return
+			}
+			x = append(append(x[:0], rows[i][:obsIndex]...), rows[i][obsIndex+1:]...)
+			if err := c.Add(rows[i][obsIndex], x); err != nil {
+				errs[ch] = &DataPointError{Index: i, Err: err}
+				return
+			}
+		}
+		chunks[ch] = c
+	})
+	return mergeCrossProducts(vars, chunks, errs)
+}
+
+// AccumulateCrossProductsFrom accumulates the cross products of rows with the given number of
+// variables read from next, which returns io.EOF once there are no more. Rows are read on the calling
+// goroutine and accumulated in parallel chunks, so next may reuse the slice it returns. Reading stops
+// at the first error, which is returned unless it is io.EOF. The number of goroutines accumulating
+// rows can be limited with WithConcurrency.
+func AccumulateCrossProductsFrom(vars int, next func() (obs float64, x []float64, err error), opts ...Option) (*CrossProducts, error) {
+	type chunk struct {
+		start int
+		obs   []float64
+		x     []float64
+	}
+	o := newOptions(opts)
+	workers := o.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	jobs := make(chan chunk)
+	partial := make([]*CrossProducts, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := range partial {
+		partial[w] = NewCrossProducts(vars)
+		wg.Add(1)
+		go func(c *CrossProducts, err *error) {
+			defer wg.Done()
+			for job := range jobs {
+				for i := 0; i < len(job.obs) && *err == nil; i++ {
+					if e := c.Add(job.obs[i], job.x[i*vars:(i+1)*vars]); e != nil {
+						*err = &DataPointError{Index: job.start + i, Err: e}
+					}
+				}
+			}
+		}(partial[w], &errs[w])
+	}
+
+	var readErr error
+	for n := 0; readErr == nil; {
+		job := chunk{start: n, obs: make([]float64, 0, crossProductChunk), x: make([]float64, 0, crossProductChunk*vars)}
+		for len(job.obs) < crossProductChunk {
+			obs, x, err := next()
+			if err != nil {
+				readErr = err
+				break
+			}
+			if len(x) != vars {
+				readErr = &DataPointError{Index: n, Err: fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(x), vars)}
+				break
+			}
+			job.obs = append(job.obs, obs)
+			job.x = append(job.x, x...)
+			n++
+		}
+		if len(job.obs) > 0 {
+			jobs <- job
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if !errors.Is(readErr, io.EOF) {
+		return nil, readErr
+	}
+	return mergeCrossProducts(vars, partial, errs)
+}
+
+// mergeCrossProducts returns the first error of errs, or else the sum of the partial cross products.
+func mergeCrossProducts(vars int, partial []*CrossProducts, errs []error) (*CrossProducts, error) {
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	total := NewCrossProducts(vars)
+	for _, c := range partial {
+		if err := total.Merge(c); err != nil {
+			return nil, err
+		}
+	}
+	return total, nil
+}
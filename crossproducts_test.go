@@ -0,0 +1,120 @@
+package regression
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCrossProducts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	rows := make([][]float64, 10000)
+	for i := range rows {
+		x1, x2 := rng.NormFloat64(), rng.Float64()
+		rows[i] = []float64{x1, 3 + 2*x1 - x2 + rng.NormFloat64(), x2}
+	}
+	var want *Regression
+	check := func(name string, c *CrossProducts, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		r, err := c.Fit()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for i := range want.GetCoeffs() {
+			if math.Abs(r.Coeff(i)-want.Coeff(i)) > 1e-9 || math.Abs(r.StdErr(i)-want.StdErr(i)) > 1e-9 {
+				t.Errorf("%s: coefficient %d: expected %v ± %v, got %v ± %v", name, i, want.Coeff(i), want.StdErr(i), r.Coeff(i), r.StdErr(i))
+			}
+		}
+		if math.Abs(r.R2-want.R2) > 1e-9 || math.Abs(r.ResidualStdErr()-want.ResidualStdErr()) > 1e-9 {
+			t.Errorf("%s: expected R² %v and σ %v, got %v and %v", name, want.R2, want.ResidualStdErr(), r.R2, r.ResidualStdErr())
+		}
+	}
+
+	// Fitting from the cross products of a sample agrees with Run
+	want = new(Regression)
+	want.Train(MakeDataPoints(rows[:500], 1)...)
+	if err := want.Run(); err != nil {
+		t.Fatal(err)
+	}
+	c, err := AccumulateCrossProducts(rows[:500], 1)
+	check("sample", c, err)
+
+	// Accumulating in parallel chunks agrees with accumulating in order
+	serial := NewCrossProducts(2)
+	for _, row := range rows {
+		serial.Add(row[1], []float64{row[0], row[2]})
+	}
+	if want, err = serial.Fit(); err != nil {
+		t.Fatal(err)
+	}
+	c, err = AccumulateCrossProducts(rows, 1, WithConcurrency(4))
+	check("rows", c, err)
+	if c.N() != len(rows) {
+		t.Errorf("Expected %d rows, got %d", len(rows), c.N())
+	}
+
+	i := 0
+	x := make([]float64, 2)
+	next := func() (float64, []float64, error) {
+		if i == len(rows) {
+			return 0, nil, io.EOF
+		}
+		row := rows[i]
+		x[0], x[1] = row[0], row[2]
+		i++
+		return row[1], x, nil
+	}
+	c, err = AccumulateCrossProductsFrom(2, next, WithConcurrency(3))
+	check("stream", c, err)
+
+	first, second := NewCrossProducts(2), NewCrossProducts(2)
+	for i, row := range rows {
+		part := first
+		if i%3 == 0 {
+			part = second
+		}
+		if err := part.Add(row[1], []float64{row[0], row[2]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check("merged", first, first.Merge(second))
+}
+
+func TestCrossProductsErrors(t *testing.T) {
+	rows := [][]float64{{1, 2}, {2, 3}, {3, math.NaN()}, {4, 5}}
+	var pointErr *DataPointError
+	if _, err := AccumulateCrossProducts(rows, 0); !errors.As(err, &pointErr) || pointErr.Index != 2 || !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for data point 2, got %v", err)
+	}
+	failed := errors.New("read failed")
+	n := 0
+	next := func() (float64, []float64, error) {
+		if n++; n > 5 {
+			return 0, nil, failed
+		}
+		return float64(n), []float64{float64(n)}, nil
+	}
+	if _, err := AccumulateCrossProductsFrom(1, next); !errors.Is(err, failed) {
+		t.Errorf("Expected the read error, got %v", err)
+	}
+	if err := NewCrossProducts(1).Merge(NewCrossProducts(2)); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+
+	c := NewCrossProducts(2)
+	for i := 0; i < 5; i++ {
+		x := float64(i)
+		c.Add(x, []float64{x, 2 * x})
+	}
+	if _, err := c.Fit(); !errors.Is(err, ErrSingular) {
+		t.Errorf("Expected ErrSingular for collinear variables, got %v", err)
+	}
+	if _, err := NewCrossProducts(1).Fit(); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+package regression
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/template"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Multiples of the standard deviation of the blank signal above the blank at which a standard curve
+// detects and quantifies an analyte, following ICH Q2.
+const (
+	lodMultiple = 3.3
+	loqMultiple = 10
+)
+
+// CurveModel selects the model of a standard curve.
+type CurveModel int
+
+const (
+	// LinearCurve fits a straight line, for assays whose signal is proportional to concentration.
+	LinearCurve CurveModel = iota
+	// FourPLCurve fits a four parameter logistic curve, for immunoassays and other assays whose signal
+	// saturates at high and low concentrations.
+	FourPLCurve
+	// FivePLCurve fits a five parameter logistic curve, for saturating assays whose curve is asymmetric.
+	FivePLCurve
+)
+
+// String returns the name of the curve model.
+func (m CurveModel) String() string {
+	switch m {
+	case LinearCurve:
+		return "linear"
+	case FourPLCurve:
+		return "4PL"
+	case FivePLCurve:
+		return "5PL"
+	}
+	return "unknown"
+}
+
+// StandardCurve is a calibration curve fitted to the signal measured for standards of known
+// concentration, used to read the concentration of unknown samples off their signal. Standards are
+// trained as data points whose observed value is the signal and whose single variable is the concentration.
+type StandardCurve struct {
+	Data []*dataPoint
+
+	model     CurveModel
+	blanks    []float64
+	linear    *Regression
+	nonlinear *Nonlinear
+	hasRun    bool
+	lod       float64
+	loq       float64
+}
+
+// NewStandardCurve creates a standard curve with the given model.
+func NewStandardCurve(model CurveModel) *StandardCurve {
+	return &StandardCurve{model: model}
+}
+
+// Train adds standards to the curve.
+func (s *StandardCurve) Train(d ...*dataPoint) {
+	s.Data = append(s.Data, d...)
+}
+
+// SetBlanks sets the signals measured for blank samples, which set the baseline and noise from which
+// the limits of detection and quantification are calculated. Without blanks the fitted signal at zero
+// concentration and the residual standard error of the curve are used instead.
+func (s *StandardCurve) SetBlanks(signals ...float64) {
+	s.blanks = append([]float64(nil), signals...)
+}
+
+// Run fits the curve to the standards and calculates the limits of detection and quantification.
+func (s *StandardCurve) Run() error {
+	if s.hasRun {
+		return ErrRegressionRun
+	}
+	for i, d := range s.Data {
+		if len(d.Variables) != 1 {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: a standard has one variable, its concentration, got %d", ErrVariableCount, len(d.Variables))}
+		}
+	}
+	switch s.model {
+	case LinearCurve:
+		s.linear = new(Regression)
+		s.linear.SetObserved("Signal")
+		s.linear.SetVar(0, "Concentration")
+		if err := s.linear.fitCopies(s.Data); err != nil {
+			return err
+		}
+	case FourPLCurve, FivePLCurve:
+		model := FourParameterLogistic()
+		if s.model == FivePLCurve {
+			model = FiveParameterLogistic()
+		}
+		s.nonlinear = NewNonlinear(model)
+		s.nonlinear.Train(s.rawData()...)
+		if err := s.nonlinear.Run(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown curve model %d", ErrDesign, s.model)
+	}
+	s.hasRun = true
+
+	baseline, sd := s.signal(0), s.sigma()
+	if len(s.blanks) > 1 {
+		baseline, sd = stat.MeanStdDev(s.blanks, nil)
+	}
+	top := s.Data[0].Variables[0]
+	for _, d := range s.Data {
+		top = math.Max(top, d.Variables[0])
+	}
+	direction := 1.0
+	if s.signal(top) < s.signal(0) {
+		direction = -1
+	}
+	s.lod = s.concentration(baseline + direction*lodMultiple*sd)
+	s.loq = s.concentration(baseline + direction*loqMultiple*sd)
+	return nil
+}
+
+// rawData returns copies of the standards, so that the fitted values recorded by the fit do not change them.
+func (s *StandardCurve) rawData() DataPoints {
+	points := make(DataPoints, len(s.Data))
+	for i, d := range s.Data {
+		points[i] = &dataPoint{Observed: d.Observed, Variables: append([]float64(nil), d.Variables...), Label: d.Label}
+	}
+	return points
+}
+
+// signal returns the fitted signal at a concentration.
+func (s *StandardCurve) signal(x float64) float64 {
+	if s.linear != nil {
+		y, _ := s.linear.Predict([]float64{x})
+		return y
+	}
+	y, _ := s.nonlinear.Predict([]float64{x})
+	return y
+}
+
+// sigma returns the residual standard error of the curve.
+func (s *StandardCurve) sigma() float64 {
+	if s.linear != nil {
+		return s.linear.ResidualStdErr()
+	}
+	return s.nonlinear.Sigma()
+}
+
+// concentration returns the concentration at which the fitted curve gives the signal, or NaN if the
+// signal is outside the range of the curve.
+func (s *StandardCurve) concentration(y float64) float64 {
+	if s.linear != nil {
+		return (y - s.linear.Coeff(0)) / s.linear.Coeff(1)
+	}
+	a, b, c, d := s.nonlinear.Param(0), s.nonlinear.Param(1), s.nonlinear.Param(2), s.nonlinear.Param(3)
+	g := 1.0
+	if s.model == FivePLCurve {
+		g = s.nonlinear.Param(4)
+	}
+	ratio := math.Pow((a-d)/(y-d), 1/g) - 1
+	if !(ratio > 0) {
+		return math.NaN()
+	}
+	return c * math.Pow(ratio, 1/b)
+}
+
+// Predict returns the fitted signal at the concentration vars[0].
+func (s *StandardCurve) Predict(vars []float64) (float64, error) {
+	if !s.hasRun {
+		return 0, ErrRegressionNotRun
+	}
+	if len(vars) != 1 {
+		return 0, fmt.Errorf("%w: got %d, expected 1", ErrVariableCount, len(vars))
+	}
+	return s.signal(vars[0]), nil
+}
+
+// Linear returns the regression of a linear curve, or nil for a logistic curve.
+func (s *StandardCurve) Linear() *Regression {
+	return s.linear
+}
+
+// Nonlinear returns the nonlinear fit of a logistic curve, or nil for a linear curve.
+func (s *StandardCurve) Nonlinear() *Nonlinear {
+	return s.nonlinear
+}
+
+// LOD returns the limit of detection, the concentration whose signal exceeds the blank by 3.3
+// standard deviations of the blank signal, or NaN if the curve does not reach that signal.
+func (s *StandardCurve) LOD() float64 {
+	return s.lod
+}
+
+// LOQ returns the limit of quantification, the concentration whose signal exceeds the blank by 10
+// standard deviations of the blank signal, or NaN if the curve does not reach that signal.
+func (s *StandardCurve) LOQ() float64 {
+	return s.loq
+}
+
+// BackCalculate estimates the concentration of an unknown sample from one or more replicate signals,
+// with a confidence interval at the given level. Linear curves use Fieller's interval, as
+// InversePredict. For logistic curves the interval is the delta method approximation, dividing the standard
+// error of the signal by the slope of the curve at the estimate. ErrTransform is returned if the mean
+// signal is outside the range of the curve.
+func (s *StandardCurve) BackCalculate(signals []float64, level float64) (*InversePrediction, error) {
+	if !s.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	if s.linear != nil {
+		return s.linear.InversePredict(signals, level)
+	}
+	if !(level > 0 && level < 1) {
+		return nil, fmt.Errorf("%w: confidence level %v", ErrSignificance, level)
+	}
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("%w: no signals to back-calculate", ErrNotEnoughData)
+	}
+	mean := stat.Mean(signals, nil)
+	x := s.concentration(mean)
+	if math.IsNaN(x) {
+		return nil, fmt.Errorf("%w: signal %v is outside the range of the curve", ErrTransform, mean)
+	}
+	h := 1e-6 * math.Max(x, 1e-9)
+	slope := (s.signal(x+h) - s.signal(math.Max(x-h, 0))) / (x + h - math.Max(x-h, 0))
+	variance := s.nonlinear.Sigma()*s.nonlinear.Sigma()/float64(len(signals)) + s.nonlinear.predictionVariance([]float64{x})
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(s.nonlinear.ResidualDF())}.Quantile((1 + level) / 2)
+	half := t * math.Sqrt(variance) / math.Abs(slope)
+	return &InversePrediction{Value: x, Lower: x - half, Upper: x + half}, nil
+}
+
+// curveReportRow is a back-calculated unknown in a calibration report.
+type curveReportRow struct {
+	Name, Signal, Value, Lower, Upper, Note string
+}
+
+// Report writes a Markdown calibration report: the fitted curve and its parameters, the limits of
+// detection and quantification, and the concentration of each named unknown back-calculated from its
+// replicate signals with confidence intervals at the given level. Unknowns below the LOQ are flagged.
+func (s *StandardCurve) Report(w io.Writer, unknowns map[string][]float64, level float64) error {
+	if !s.hasRun {
+		return ErrRegressionNotRun
+	}
+	format := func(f float64) string {
+		return fmt.Sprintf("%.4g", f)
+	}
+	data := struct {
+		Model      string
+		Standards  int
+		Params     []reportStat
+		Sigma, LOD string
+		LOQ, Level string
+		Unknowns   []curveReportRow
+	}{
+		Model:     s.model.String(),
+		Standards: len(s.Data),
+		Sigma:     format(s.sigma()),
+		LOD:       format(s.lod),
+		LOQ:       format(s.loq),
+		Level:     fmt.Sprintf("%g%%", level*100),
+	}
+	if s.linear != nil {
+		data.Params = []reportStat{{"Intercept", format(s.linear.Coeff(0))}, {"Slope", format(s.linear.Coeff(1))}}
+	} else {
+		for i, name := range s.nonlinear.Model().Params {
+			data.Params = append(data.Params, reportStat{name, format(s.nonlinear.Param(i))})
+		}
+	}
+
+	names := make([]string, 0, len(unknowns))
+	for name := range unknowns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		row := curveReportRow{Name: name, Signal: format(stat.Mean(unknowns[name], nil))}
+		p, err := s.BackCalculate(unknowns[name], level)
+		switch {
+		case err != nil:
+			row.Note = err.Error()
+		case p.Value < s.loq:
+			row.Note = "below LOQ"
+		}
+		if err == nil {
+			row.Value, row.Lower, row.Upper = format(p.Value), format(p.Lower), format(p.Upper)
+		}
+		data.Unknowns = append(data.Unknowns, row)
+	}
+	return curveReport.Execute(w, data)
+}
+
+var curveReport = template.Must(template.New("curve").Funcs(template.FuncMap{"escape": markdownEscaper.Replace}).Parse(
+	`# Calibration report
+
+{{.Model}} standard curve fitted to {{.Standards}} standards.
+
+## Curve
+
+| Parameter | Estimate |
+|-----------|---------:|
+{{range .Params}}| {{.Name}} | {{.Value}} |
+{{end}}| Residual standard error | {{.Sigma}} |
+| LOD | {{.LOD}} |
+| LOQ | {{.LOQ}} |
+{{if .Unknowns}}
+## Unknowns
+
+| Sample | Mean signal | Concentration | {{.Level}} lower | {{.Level}} upper | Note |
+|--------|------------:|--------------:|------:|------:|------|
+{{range .Unknowns}}| {{escape .Name}} | {{.Signal}} | {{.Value}} | {{.Lower}} | {{.Upper}} | {{escape .Note}} |
+{{end}}{{end}}`))
@@ -0,0 +1,75 @@
+package regression
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestStandardCurve(t *testing.T) {
+	linear := NewStandardCurve(LinearCurve)
+	for _, x := range []float64{0, 1, 2, 5, 10, 20} {
+		linear.Train(DataPoint(0.05+0.2*x+0.01*math.Sin(x), []float64{x}))
+	}
+	linear.SetBlanks(0.04, 0.06, 0.05, 0.05)
+	if err := linear.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// 3.3 and 10 standard deviations of the blanks above their mean, divided by the slope
+	sd := math.Sqrt(0.0002 / 3)
+	slope := linear.Linear().Coeff(1)
+	x0 := (0.05 - linear.Linear().Coeff(0)) / slope
+	if math.Abs(linear.LOD()-(x0+3.3*sd/slope)) > 1e-9 || math.Abs(linear.LOQ()-(x0+10*sd/slope)) > 1e-9 {
+		t.Errorf("Expected LOD %v and LOQ %v, got %v and %v", x0+3.3*sd/slope, x0+10*sd/slope, linear.LOD(), linear.LOQ())
+	}
+	p, err := linear.BackCalculate([]float64{1.05}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(p.Value-5) > 0.05 || !(p.Lower < p.Value && p.Value < p.Upper) {
+		t.Errorf("Expected a concentration near 5, got %+v", p)
+	}
+
+	logistic := NewStandardCurve(FourPLCurve)
+	logistic.Train(logisticData(0.02, rand.New(rand.NewSource(2)))...)
+	if err := logistic.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// The signal of 20 on the true curve
+	signal := 2.5 + (0.1-2.5)/(1+math.Pow(20.0/50, 1.2))
+	p, err = logistic.BackCalculate([]float64{signal, signal}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(p.Lower < 20 && 20 < p.Upper) {
+		t.Errorf("Expected the interval to contain 20, got %+v", p)
+	}
+	if !(logistic.LOD() > 0 && logistic.LOD() < logistic.LOQ()) {
+		t.Errorf("Expected 0 < LOD < LOQ, got %v and %v", logistic.LOD(), logistic.LOQ())
+	}
+	if _, err := logistic.BackCalculate([]float64{3}, 0.95); !errors.Is(err, ErrTransform) {
+		t.Errorf("Expected ErrTransform above the top of the curve, got %v", err)
+	}
+
+	asymmetric := NewStandardCurve(FivePLCurve)
+	asymmetric.Train(logisticData(0.02, rand.New(rand.NewSource(2)))...)
+	if err := asymmetric.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := asymmetric.BackCalculate([]float64{signal, signal}, 0.95); err != nil || !(p.Lower < 20 && 20 < p.Upper) {
+		t.Errorf("Expected the 5PL interval to contain 20, got %+v, %v", p, err)
+	}
+
+	var buf bytes.Buffer
+	if err := logistic.Report(&buf, map[string][]float64{"S1": {signal}, "S2": {3}}, 0.95); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"4PL standard curve fitted to 16 standards", "| c |", "| LOQ |", "| S1 |", "outside the range of the curve"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected the report to contain %q, got\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package datasets embeds a few classic regression datasets for examples, tests and tutorials.
+package datasets
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Synthace/regression"
+)
+
+//go:embed data/*.csv
+var files embed.FS
+
+// ErrUnknownDataset signals that a requested dataset does not exist.
+var ErrUnknownDataset = errors.New("unknown dataset")
+
+// Dataset is a set of data points with the names of the observed value and variables.
+type Dataset struct {
+	Name      string
+	Observed  string
+	Variables []string
+	Points    regression.DataPoints
+}
+
+// Train names the observed value and variables of r and adds the data points to it. The points
+// are shared, so each Dataset should only be used to train one regression.
+func (d *Dataset) Train(r *regression.Regression) {
+	r.SetObserved(d.Observed)
+	for i, name := range d.Variables {
+		r.SetVar(i, name)
+	}
+	r.Train(d.Points...)
+}
+
+// Longley returns Longley's (1967) macroeconomic data for 1947 to 1962, a famously ill-conditioned
+// regression of employment on six economic series.
+func Longley() *Dataset {
+	return load("Longley", "longley.csv")
+}
+
+// Filip returns Filip's data from the NIST Statistical Reference Datasets, 82 observations fitted
+// by a tenth degree polynomial in x that is so ill-conditioned that many solvers fail to fit it.
+func Filip() *Dataset {
+	return load("Filip", "filip.csv")
+}
+
+// Anscombe returns one of the four datasets of Anscombe's (1973) quartet, numbered 1 to 4. They
+// have nearly identical means, variances and fitted lines but look very different when plotted.
+func Anscombe(i int) (*Dataset, error) {
+	if i < 1 || i > 4 {
+		return nil, fmt.Errorf("%w: Anscombe %d", ErrUnknownDataset, i)
+	}
+	return load(fmt.Sprintf("Anscombe %d", i), fmt.Sprintf("anscombe%d.csv", i)), nil
+}
+
+// Housing returns 200 synthetic neighbourhoods in the style of the Boston housing data, with the
+// median home value in $1000s against six neighbourhood characteristics. The values were simulated
+// from a linear model with normal noise and censored to lie between 5 and 50.
+func Housing() *Dataset {
+	return load("Housing", "housing.csv")
+}
+
+// load parses an embedded CSV file whose first column is the observed value. The files are fixed,
+// so a malformed file is a programming error.
+func load(name, file string) *Dataset {
+	f, err := files.Open("data/" + file)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		panic(err)
+	}
+
+	d := &Dataset{Name: name, Observed: records[0][0], Variables: records[0][1:]}
+	for _, record := range records[1:] {
+		values := make([]float64, len(record))
+		for j, v := range record {
+			if values[j], err = strconv.ParseFloat(v, 64); err != nil {
+				panic(fmt.Sprintf("datasets: %s: %v", file, err))
+			}
+		}
+		d.Points = append(d.Points, regression.DataPoint(values[0], values[1:]))
+	}
+	return d
+}
@@ -0,0 +1,56 @@
+package datasets
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Synthace/regression"
+)
+
+func TestDatasets(t *testing.T) {
+	all := []*Dataset{Longley(), Filip(), Housing()}
+	for i := 1; i <= 4; i++ {
+		d, err := Anscombe(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, d)
+	}
+	for _, d := range all {
+		r := new(regression.Regression)
+		d.Train(r)
+		if err := r.Run(); err != nil {
+			t.Errorf("%s: %v", d.Name, err)
+			continue
+		}
+		if r.NumVars() != len(d.Variables) || r.GetVar(0) != d.Variables[0] {
+			t.Errorf("%s: expected variables %v, got %v", d.Name, d.Variables, r.Variables())
+		}
+	}
+
+	if n := len(Longley().Points); n != 16 {
+		t.Errorf("Expected 16 Longley observations, got %d", n)
+	}
+	if n := len(Filip().Points); n != 82 {
+		t.Errorf("Expected 82 Filip observations, got %d", n)
+	}
+	if n := len(Housing().Points); n != 200 {
+		t.Errorf("Expected 200 housing observations, got %d", n)
+	}
+
+	// The quartet shares a fitted line of about y = 3 + 0.5x
+	for i := 1; i <= 4; i++ {
+		d, _ := Anscombe(i)
+		r := new(regression.Regression)
+		d.Train(r)
+		r.Run()
+		if math.Abs(r.Coeff(0)-3) > 0.01 || math.Abs(r.Coeff(1)-0.5) > 0.01 {
+			t.Errorf("%s: expected y = 3 + 0.5x, got %s", d.Name, r.Formula)
+		}
+	}
+
+	if _, err := Anscombe(5); !errors.Is(err, ErrUnknownDataset) {
+		t.Errorf("Expected ErrUnknownDataset, got %v", err)
+	}
+}
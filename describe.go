@@ -0,0 +1,67 @@
+package regression
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// VariableSummary holds descriptive statistics for a single column of the data.
+// The quartiles are empirical quantiles of the data.
+type VariableSummary struct {
+	Name   string
+	N      int
+	Min    float64
+	Q1     float64
+	Median float64
+	Q3     float64
+	Max    float64
+	Mean   float64
+	StdDev float64
+}
+
+// Description holds descriptive statistics for the observed value and each variable.
+type Description struct {
+	Observed  VariableSummary
+	Variables []VariableSummary
+}
+
+// Describe summarises the observed value and each variable of the training data, so that basic
+// quality checks can be made before fitting. The variables include any feature crosses.
+func (r *Regression) Describe() (*Description, error) {
+	if len(r.Data) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	col := make([]float64, len(r.Data))
+	for i, d := range r.Data {
+		col[i] = d.Observed
+	}
+	desc := &Description{
+		Observed:  summarise(r.GetObserved(), col),
+		Variables: make([]VariableSummary, len(r.features(r.Data[0].Variables))),
+	}
+	for j := range desc.Variables {
+		for i, d := range r.Data {
+			col[i] = r.features(d.Variables)[j]
+		}
+		desc.Variables[j] = summarise(r.GetVar(j), col)
+	}
+	return desc, nil
+}
+
+// summarise computes the summary of x, reordering x in the process.
+func summarise(name string, x []float64) VariableSummary {
+	sort.Float64s(x)
+	mean, std := stat.MeanStdDev(x, nil)
+	return VariableSummary{
+		Name:   name,
+		N:      len(x),
+		Min:    x[0],
+		Q1:     stat.Quantile(0.25, stat.Empirical, x, nil),
+		Median: stat.Quantile(0.5, stat.Empirical, x, nil),
+		Q3:     stat.Quantile(0.75, stat.Empirical, x, nil),
+		Max:    x[len(x)-1],
+		Mean:   mean,
+		StdDev: std,
+	}
+}
@@ -0,0 +1,44 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	r := new(Regression)
+	if _, err := r.Describe(); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+	r.SetObserved("Y")
+	r.SetVar(0, "X")
+	for _, x := range []float64{4, 1, 3, 2, 5, 8, 7, 6} {
+		r.Train(DataPoint(10*x, []float64{x, 1}))
+	}
+
+	desc, err := r.Describe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Observed.Name != "Y" || desc.Observed.Max != 80 || desc.Observed.Mean != 45 {
+		t.Errorf("Unexpected observed summary %+v", desc.Observed)
+	}
+	if len(desc.Variables) != 2 {
+		t.Fatalf("Expected 2 variable summaries, got %v", len(desc.Variables))
+	}
+
+	x := desc.Variables[0]
+	if x.Name != "X" || x.N != 8 || x.Min != 1 || x.Max != 8 || x.Q1 != 2 || x.Median != 4 || x.Q3 != 6 || x.Mean != 4.5 {
+		t.Errorf("Unexpected variable summary %+v", x)
+	}
+	if math.Abs(x.StdDev-math.Sqrt(6)) > 1e-12 {
+		t.Errorf("Expected a standard deviation of sqrt(6), got %v", x.StdDev)
+	}
+	if c := desc.Variables[1]; c.Name != "X1" || c.StdDev != 0 {
+		t.Errorf("Expected the constant column to have no spread, got %+v", c)
+	}
+	if r.Data[0].Variables[0] != 4 {
+		t.Error("Expected Describe to leave the data in training order")
+	}
+}
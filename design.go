@@ -0,0 +1,254 @@
+package regression
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Contrast selects how a categorical variable is encoded in a design matrix.
+type Contrast int
+
+const (
+	// TreatmentContrast encodes a categorical variable as an indicator column for each level
+	// except the first, which is the reference level.
+	TreatmentContrast Contrast = iota
+	// SumContrast encodes a categorical variable as a column for each level except the last,
+	// which is coded -1 in every column, so that the effects sum to zero.
+	SumContrast
+)
+
+// interceptName is the name of the column of ones in a design matrix.
+const interceptName = "(Intercept)"
+
+// Design describes how to build a design matrix from named numeric and categorical columns,
+// independently of any solver. Categorical levels are fixed the first time they are seen, so
+// the same Design encodes later data, such as data to predict, consistently.
+type Design struct {
+	intercept bool
+	terms     [][]string
+	kinds     map[string]*categoricalTerm
+}
+
+type categoricalTerm struct {
+	contrast Contrast
+	levels   []string
+}
+
+// DesignMatrix is a built design matrix with a name for each column.
+type DesignMatrix struct {
+	Names  []string
+	Matrix *mat.Dense
+}
+
+// NewDesign creates a Design with an intercept column and no terms.
+func NewDesign() *Design {
+	return &Design{intercept: true, kinds: make(map[string]*categoricalTerm)}
+}
+
+// Intercept sets whether the design matrix starts with a column of ones.
+func (d *Design) Intercept(include bool) *Design {
+	d.intercept = include
+	return d
+}
+
+// Numeric adds a numeric column as a term.
+func (d *Design) Numeric(name string) *Design {
+	d.terms = append(d.terms, []string{name})
+	return d
+}
+
+// Categorical adds a categorical column as a term, encoded using the contrast. If no levels are
+// given they are the sorted distinct values seen when the design is first built.
+func (d *Design) Categorical(name string, contrast Contrast, levels ...string) *Design {
+	d.kinds[name] = &categoricalTerm{contrast: contrast, levels: levels}
+	d.terms = append(d.terms, []string{name})
+	return d
+}
+
+// Interaction adds the interaction of the named columns as a term, that is the product of every
+// combination of their encoded columns. The columns' main effects are not added.
+func (d *Design) Interaction(names ...string) *Design {
+	d.terms = append(d.terms, names)
+	return d
+}
+
+// Build constructs the design matrix from the named columns, which must all be the same length.
+// Numeric columns are looked up in numeric, and columns declared as categorical in categorical.
+func (d *Design) Build(numeric map[string][]float64, categorical map[string][]string) (*DesignMatrix, error) {
+	rows := -1
+	checkLen := func(name string, n int) error {
+		if n == 0 {
+			return fmt.Errorf("%w: column %q is empty", ErrDesign, name)
+		}
+		if rows >= 0 && n != rows {
+			return fmt.Errorf("%w: column %q has %d rows, expected %d", ErrDesign, name, n, rows)
+		}
+		rows = n
+		return nil
+	}
+
+	// Encode each column used by the terms
+	encoded := make(map[string]*DesignMatrix)
+	for _, term := range d.terms {
+		if len(term) == 0 {
+			return nil, fmt.Errorf("%w: interaction of no columns", ErrDesign)
+		}
+		for _, name := range term {
+			if encoded[name] != nil {
+				continue
+			}
+			var enc *DesignMatrix
+			var err error
+			if kind, ok := d.kinds[name]; ok {
+				values, ok := categorical[name]
+				if !ok {
+					return nil, fmt.Errorf("%w: missing categorical column %q", ErrDesign, name)
+				}
+				if err := checkLen(name, len(values)); err != nil {
+					return nil, err
+				}
+				enc, err = kind.encode(name, values)
+			} else {
+				values, ok := numeric[name]
+				if !ok {
+					return nil, fmt.Errorf("%w: missing numeric column %q", ErrDesign, name)
+				}
+				if err := checkLen(name, len(values)); err != nil {
+					return nil, err
+				}
+				enc = &DesignMatrix{Names: []string{name}, Matrix: mat.NewDense(len(values), 1, append([]float64(nil), values...))}
+			}
+			if err != nil {
+				return nil, err
+			}
+			encoded[name] = enc
+		}
+	}
+	if rows < 0 {
+		return nil, fmt.Errorf("%w: no terms", ErrDesign)
+	}
+
+	// Expand the terms, including interactions, into columns
+	var names []string
+	var cols [][]float64
+	if d.intercept {
+		ones := make([]float64, rows)
+		for i := range ones {
+			ones[i] = 1
+		}
+		names = append(names, interceptName)
+		cols = append(cols, ones)
+	}
+	for _, term := range d.terms {
+		termNames := []string{""}
+		termCols := [][]float64{nil}
+		for _, name := range term {
+			enc := encoded[name]
+			var nextNames []string
+			var nextCols [][]float64
+			for k, prefix := range termNames {
+				for j, encName := range enc.Names {
+					col := mat.Col(nil, j, enc.Matrix)
+					if termCols[k] != nil {
+						for i := range col {
+							col[i] *= termCols[k][i]
+						}
+						encName = prefix + ":" + encName
+					}
+					nextNames = append(nextNames, encName)
+					nextCols = append(nextCols, col)
+				}
+			}
+			termNames, termCols = nextNames, nextCols
+		}
+		names = append(names, termNames...)
+		cols = append(cols, termCols...)
+	}
+
+	m := mat.NewDense(rows, len(cols), nil)
+	for j, col := range cols {
+		m.SetCol(j, col)
+	}
+	return &DesignMatrix{Names: names, Matrix: m}, nil
+}
+
+// encode applies the contrast to a categorical column, fixing its levels if this is the first time it is seen.
+func (c *categoricalTerm) encode(name string, values []string) (*DesignMatrix, error) {
+	if len(c.levels) == 0 {
+		seen := make(map[string]bool)
+		for _, v := range values {
+			if !seen[v] {
+				seen[v] = true
+				c.levels = append(c.levels, v)
+			}
+		}
+		sort.Strings(c.levels)
+	}
+	if len(c.levels) < 2 {
+		return nil, fmt.Errorf("%w: categorical column %q needs at least 2 levels", ErrDesign, name)
+	}
+	index := make(map[string]int, len(c.levels))
+	for i, level := range c.levels {
+		index[level] = i
+	}
+
+	var names []string
+	switch c.contrast {
+	case TreatmentContrast:
+		for _, level := range c.levels[1:] {
+			names = append(names, name+"["+level+"]")
+		}
+	case SumContrast:
+		for _, level := range c.levels[:len(c.levels)-1] {
+			names = append(names, name+"[S."+level+"]")
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown contrast for %q", ErrDesign, name)
+	}
+
+	m := mat.NewDense(len(values), len(names), nil)
+	for i, v := range values {
+		level, ok := index[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown level %q of %q", ErrDesign, v, name)
+		}
+		switch {
+		case c.contrast == TreatmentContrast && level > 0:
+			m.Set(i, level-1, 1)
+		case c.contrast == SumContrast && level < len(c.levels)-1:
+			m.Set(i, level, 1)
+		case c.contrast == SumContrast:
+			for j := range names {
+				m.Set(i, j, -1)
+			}
+		}
+	}
+	return &DesignMatrix{Names: names, Matrix: m}, nil
+}
+
+// Train adds each row of the design matrix to the regression as a data point with the corresponding
+// observed value, and names the variables after the columns. The intercept column is left out, as the
+// regression fits its own offset.
+func (m *DesignMatrix) Train(r *Regression, observed []float64) error {
+	rows, _ := m.Matrix.Dims()
+	if len(observed) != rows {
+		return fmt.Errorf("%w: %d observed values for %d rows", ErrDesign, len(observed), rows)
+	}
+	var keep []int
+	for j, name := range m.Names {
+		if name != interceptName {
+			r.SetVar(len(keep), name)
+			keep = append(keep, j)
+		}
+	}
+	for i := 0; i < rows; i++ {
+		vars := make([]float64, len(keep))
+		for k, j := range keep {
+			vars[k] = m.Matrix.At(i, j)
+		}
+		r.Train(DataPoint(observed[i], vars))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDesignTreatment(t *testing.T) {
+	d := NewDesign().Numeric("dose").Categorical("site", TreatmentContrast).Interaction("dose", "site")
+	m, err := d.Build(
+		map[string][]float64{"dose": {1, 2, 3, 4}},
+		map[string][]string{"site": {"b", "a", "c", "b"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{"(Intercept)", "dose", "site[b]", "site[c]", "dose:site[b]", "dose:site[c]"}
+	if !reflect.DeepEqual(m.Names, names) {
+		t.Errorf("Expected columns %v, got %v", names, m.Names)
+	}
+	expected := mat.NewDense(4, 6, []float64{
+		1, 1, 1, 0, 1, 0,
+		1, 2, 0, 0, 0, 0,
+		1, 3, 0, 1, 0, 3,
+		1, 4, 1, 0, 4, 0,
+	})
+	if !mat.Equal(m.Matrix, expected) {
+		t.Errorf("Unexpected design matrix\n%v", mat.Formatted(m.Matrix))
+	}
+
+	// The levels are fixed, so new data is encoded the same way
+	m, err = d.Build(map[string][]float64{"dose": {5}}, map[string][]string{"site": {"c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mat.Equal(m.Matrix, mat.NewDense(1, 6, []float64{1, 5, 0, 1, 0, 5})) {
+		t.Errorf("Unexpected design matrix\n%v", mat.Formatted(m.Matrix))
+	}
+	if _, err := d.Build(map[string][]float64{"dose": {5}}, map[string][]string{"site": {"z"}}); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for an unknown level, got %v", err)
+	}
+}
+
+func TestDesignSum(t *testing.T) {
+	d := NewDesign().Intercept(false).Categorical("site", SumContrast, "a", "b", "c")
+	m, err := d.Build(nil, map[string][]string{"site": {"a", "b", "c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Names, []string{"site[S.a]", "site[S.b]"}) {
+		t.Errorf("Unexpected columns %v", m.Names)
+	}
+	if !mat.Equal(m.Matrix, mat.NewDense(3, 2, []float64{1, 0, 0, 1, -1, -1})) {
+		t.Errorf("Unexpected design matrix\n%v", mat.Formatted(m.Matrix))
+	}
+}
+
+func TestDesignErrors(t *testing.T) {
+	_, err := NewDesign().Numeric("a").Numeric("b").Build(map[string][]float64{"a": {1, 2}, "b": {1}}, nil)
+	if !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for mismatched lengths, got %v", err)
+	}
+	_, err = NewDesign().Numeric("a").Build(nil, nil)
+	if !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for a missing column, got %v", err)
+	}
+	_, err = NewDesign().Numeric("a").Build(map[string][]float64{"a": {}}, nil)
+	if !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for an empty column, got %v", err)
+	}
+	_, err = NewDesign().Numeric("a").Interaction().Build(map[string][]float64{"a": {1, 2}}, nil)
+	if !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for an empty interaction, got %v", err)
+	}
+	_, err = NewDesign().Build(nil, nil)
+	if !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for a design without columns, got %v", err)
+	}
+}
+
+func TestDesignMatrixTrain(t *testing.T) {
+	sites := []string{"a", "b", "a", "b", "a", "b"}
+	dose := []float64{1, 1, 2, 2, 3, 3}
+	observed := make([]float64, len(dose))
+	for i := range dose {
+		observed[i] = 1 + 2*dose[i]
+		if sites[i] == "b" {
+			observed[i] += 5
+		}
+	}
+
+	m, err := NewDesign().Numeric("dose").Categorical("site", TreatmentContrast).Build(
+		map[string][]float64{"dose": dose}, map[string][]string{"site": sites})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := new(Regression)
+	if err := m.Train(r, observed); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if r.GetVar(1) != "site[b]" {
+		t.Errorf("Expected the variables to be named after the columns, got %v", r.GetVar(1))
+	}
+	for i, c := range []float64{1, 2, 5} {
+		if math.Abs(r.Coeff(i)-c) > 1e-9 {
+			t.Errorf("Expected coefficient %v to be %v, got %v", i, c, r.Coeff(i))
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package regression
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Leverage returns the leverage of each data point, the diagonal of the hat matrix X(XᵀX)⁻¹Xᵀ of the
+// design matrix without any dropped variables. Points with leverage well above the average, k/n,
+// have unusual values of the variables.
+func (r *Regression) Leverage() ([]float64, error) {
+	x, _, inv, err := r.hat()
+	if err != nil {
+		return nil, err
+	}
+	rows, _ := x.Dims()
+	leverage := make([]float64, rows)
+	for i := range leverage {
+		row := x.RowView(i)
+		leverage[i] = mat.Inner(row, inv, row)
+	}
+	return leverage, nil
+}
+
+// hat returns the design matrix without any dropped variables along with the indices of its columns
+// in the full design matrix, and (XᵀX)⁻¹.
+func (r *Regression) hat() (*mat.Dense, []int, *mat.SymDense, error) {
+	if err := r.requireData(); err != nil {
+		return nil, nil, nil, err
+	}
+	_, variables := r.designMatrix()
+	_, cols := variables.Dims()
+	active := r.activeColumns(cols)
+	x := columns(variables, active)
+	inv, err := crossProductInverse(x)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return x, active, inv, nil
+}
+
+// requireHat returns ErrIncompatibleOptions if the regression was fitted with fixed effects,
+// instruments or maximum likelihood, whose fitted values do not come from the hat matrix of the design.
+func (r *Regression) requireHat(method string) error {
+	if r.fixedEffects || len(r.endogenous) > 0 || len(r.instruments) > 0 || r.maximumLikelihood() {
+		return fmt.Errorf("%w: %s need a least squares fit without fixed effects, instruments or censored data", ErrIncompatibleOptions, method)
+	}
+	return nil
+}
+
+// activeColumns returns the indices of the columns of a design matrix with cols columns that are
+// not dropped variables.
+func (r *Regression) activeColumns(cols int) []int {
+	dropped := make(map[int]bool, len(r.dropped))
+	for _, d := range r.dropped {
+		dropped[d.Index+1] = true
+	}
+	var active []int
+	for j := 0; j < cols; j++ {
+		if !dropped[j] {
+			active = append(active, j)
+		}
+	}
+	return active
+}
+
+// CooksDistance returns Cook's distance for each data point, measuring how much the fitted values
+// would change if the point were left out. Values above about 4/n are worth investigating.
+func (r *Regression) CooksDistance() ([]float64, error) {
+	leverage, err := r.Leverage()
+	if err != nil {
+		return nil, err
+	}
+	return r.cooksDistance(leverage), nil
+}
+
+// cooksDistance returns Cook's distance for each data point given its leverage.
+func (r *Regression) cooksDistance(leverage []float64) []float64 {
+	k := len(r.coeff) - len(r.dropped)
+	s2 := r.residualStdErr * r.residualStdErr
+	cooks := make([]float64, len(leverage))
+	for i, h := range leverage {
+		e := r.Data[i].Observed - r.Data[i].Predicted
+		cooks[i] = e * e / (float64(k) * s2) * h / ((1 - h) * (1 - h))
+	}
+	return cooks
+}
+
+// StandardizedResiduals returns the residual of each data point divided by its estimated standard
+// deviation, s√(1 - h), where s is the residual standard error and h the point's leverage.
+func (r *Regression) StandardizedResiduals() ([]float64, error) {
+	leverage, err := r.Leverage()
+	if err != nil {
+		return nil, err
+	}
+	return r.standardizedResiduals(leverage), nil
+}
+
+// standardizedResiduals returns the standardized residual of each data point given its leverage.
+func (r *Regression) standardizedResiduals(leverage []float64) []float64 {
+	standardized := make([]float64, len(leverage))
+	for i, h := range leverage {
+		standardized[i] = (r.Data[i].Observed - r.Data[i].Predicted) / (r.residualStdErr * math.Sqrt(1-h))
+	}
+	return standardized
+}
+
+// StudentizedResiduals returns the externally studentized residual of each data point, its residual
+// divided by an estimate of its standard deviation from a fit without the point. Under normal errors
+// they follow a t distribution with n - k - 1 degrees of freedom.
+func (r *Regression) StudentizedResiduals() ([]float64, error) {
+	leverage, err := r.Leverage()
+	if err != nil {
+		return nil, err
+	}
+	return r.studentizedResiduals(leverage), nil
+}
+
+// studentizedResiduals returns the externally studentized residual of each data point given its leverage.
+func (r *Regression) studentizedResiduals(leverage []float64) []float64 {
+	studentized := r.standardizedResiduals(leverage)
+	df := float64(r.residualDF)
+	for i, e := range studentized {
+		studentized[i] = e * math.Sqrt((df-1)/(df-e*e))
+	}
+	return studentized
+}
+
+// InfluentialPoint is a data point flagged by Influence.
+type InfluentialPoint struct {
+	Index               int
+	Label               string
+	Leverage            float64
+	CooksDistance       float64
+	StudentizedResidual float64
+	// HighLeverage, HighCooksDistance and Outlier record which of the cutoffs the point exceeds.
+	HighLeverage      bool
+	HighCooksDistance bool
+	Outlier           bool
+}
+
+// InfluenceReport lists the data points that exceed any of the influence cutoffs.
+type InfluenceReport struct {
+	// LeverageCutoff is twice the average leverage, 2k/n.
+	LeverageCutoff float64
+	// CooksDistanceCutoff is 4/n.
+	CooksDistanceCutoff float64
+	// StudentizedCutoff bounds the absolute studentized residual, and is 2.
+	StudentizedCutoff float64
+	Points            []InfluentialPoint
+}
+
+// Influence combines the leverage, Cook's distance and studentized residual of each data point, and
+// reports the points exceeding the standard cutoff for any of them, in training order. Fits with fixed
+// effects, instruments or censored data return ErrIncompatibleOptions.
+func (r *Regression) Influence() (*InfluenceReport, error) {
+	if err := r.requireHat("influence diagnostics"); err != nil {
+		return nil, err
+	}
+	leverage, err := r.Leverage()
+	if err != nil {
+		return nil, err
+	}
+	return r.influence(leverage), nil
+}
+
+// influence returns the influence report given the leverage of each data point.
+func (r *Regression) influence(leverage []float64) *InfluenceReport {
+	cooks := r.cooksDistance(leverage)
+	studentized := r.studentizedResiduals(leverage)
+
+	n := float64(len(leverage))
+	var k float64
+	for _, h := range leverage {
+		k += h
+	}
+	report := &InfluenceReport{
+		LeverageCutoff:      2 * k / n,
+		CooksDistanceCutoff: 4 / n,
+		StudentizedCutoff:   2,
+	}
+	for i, d := range r.Data {
+		p := InfluentialPoint{
+			Index:               i,
+			Label:               d.Label,
+			Leverage:            leverage[i],
+			CooksDistance:       cooks[i],
+			StudentizedResidual: studentized[i],
+			HighLeverage:        leverage[i] > report.LeverageCutoff,
+			HighCooksDistance:   cooks[i] > report.CooksDistanceCutoff,
+			Outlier:             math.Abs(studentized[i]) > report.StudentizedCutoff,
+		}
+		if p.HighLeverage || p.HighCooksDistance || p.Outlier {
+			report.Points = append(report.Points, p)
+		}
+	}
+	return report
+}
+
+// ResidualsVsFitted returns the fitted value and residual of each data point, in training order.
+func (r *Regression) ResidualsVsFitted() (fitted, residuals []float64, err error) {
+	if err := r.requireData(); err != nil {
+		return nil, nil, err
+	}
+	fitted = make([]float64, len(r.Data))
+	residuals = make([]float64, len(r.Data))
+	for i, d := range r.Data {
+		fitted[i] = d.Predicted
+		residuals[i] = d.Observed - d.Predicted
+	}
+	return fitted, residuals, nil
+}
+
+// AddedVariable returns the points of the added-variable, or partial regression, plot of variable i:
+// the residuals of the variable and of the observed value, each regressed on the other variables and
+// the offset, in training order. By the Frisch-Waugh-Lovell theorem the slope of y on x through the
+// origin is the coefficient of the variable, so the plot shows the unique contribution of the variable
+// and the points that drive it.
+func (r *Regression) AddedVariable(i int) (x, y []float64, err error) {
+	if err := r.requireData(); err != nil {
+		return nil, nil, err
+	}
+	observed, variables := r.designMatrix()
+	_, cols := variables.Dims()
+	if i < 0 || i >= cols-1 {
+		return nil, nil, fmt.Errorf("%w: %d of %d", ErrVariableIndex, i, cols-1)
+	}
+	others := columns(variables, withoutColumn(cols, i+1))
+	residuals := func(target mat.Matrix) ([]float64, error) {
+		b, err := leastSquares(others, target)
+		if err != nil {
+			return nil, err
+		}
+		var fitted mat.VecDense
+		fitted.MulVec(others, mat.NewVecDense(len(b), b))
+		e := mat.Col(nil, 0, target)
+		for k := range e {
+			e[k] -= fitted.AtVec(k)
+		}
+		return e, nil
+	}
+	if x, err = residuals(columns(variables, []int{i + 1})); err != nil {
+		return nil, nil, err
+	}
+	if y, err = residuals(observed); err != nil {
+		return nil, nil, err
+	}
+	return x, y, nil
+}
+
+// QQ returns the points of a normal quantile-quantile plot: the quantiles of the standard normal
+// distribution and the sorted standardized residuals. The residuals are close to normal if the points
+// lie close to the line y = x. The probabilities follow R's ppoints.
+func (r *Regression) QQ() (theoretical, sample []float64, err error) {
+	sample, err = r.StandardizedResiduals()
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Float64s(sample)
+	n := float64(len(sample))
+	a := 0.5
+	if n <= 10 {
+		a = 3.0 / 8
+	}
+	theoretical = make([]float64, len(sample))
+	for i := range theoretical {
+		theoretical[i] = distuv.UnitNormal.Quantile((float64(i+1) - a) / (n + 1 - 2*a))
+	}
+	return theoretical, sample, nil
+}
+
+// WriteResultsCSV writes a row for each data point with its variables, observed and predicted values,
+// residual, leverage and Cook's distance, preceded by a header of the names. If any data points are
+// labeled the rows start with the label.
+func (r *Regression) WriteResultsCSV(w io.Writer) error {
+	leverage, err := r.Leverage()
+	if err != nil {
+		return err
+	}
+	cooks := r.cooksDistance(leverage)
+
+	labeled := labels(r.Data) != nil
+	numOfvars := len(r.coeff) - 1
+	header := make([]string, 0, numOfvars+6)
+	if labeled {
+		header = append(header, "Label")
+	}
+	for i := 0; i < numOfvars; i++ {
+		header = append(header, r.GetVar(i))
+	}
+	observed := r.GetObserved()
+	if observed == "" {
+		observed = "Observed"
+	}
+	header = append(header, observed, "Predicted", "Residual", "Leverage", "CooksDistance")
+
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i, d := range r.Data {
+		record := make([]string, 0, len(header))
+		if labeled {
+			record = append(record, d.Label)
+		}
+		for _, v := range r.features(d.Variables) {
+			record = append(record, format(v))
+		}
+		record = append(record, format(d.Observed), format(d.Predicted), format(d.Observed-d.Predicted), format(leverage[i]), format(cooks[i]))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
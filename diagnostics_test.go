@@ -0,0 +1,225 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestLeverageAndCooksDistance(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if _, err := r.Leverage(); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Run()
+
+	leverage, err := r.Leverage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cooks, err := r.CooksDistance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reference values from R's hatvalues() and cooks.distance() for the first point, x = 10
+	if math.Abs(leverage[0]-0.1) > 1e-6 {
+		t.Errorf("Expected leverage 0.1, got %v", leverage[0])
+	}
+	if math.Abs(cooks[0]-0.0000615) > 1e-6 {
+		t.Errorf("Expected Cook's distance 0.0000615, got %v", cooks[0])
+	}
+	var total float64
+	for _, h := range leverage {
+		total += h
+	}
+	if math.Abs(total-2) > 1e-9 {
+		t.Errorf("Expected leverages to sum to the number of coefficients, got %v", total)
+	}
+}
+
+func TestWriteResultsCSV(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("Y")
+	r.SetVar(0, "X")
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Run()
+
+	var buf bytes.Buffer
+	if err := r.WriteResultsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(anscombe)+1 {
+		t.Fatalf("Expected a header and %d rows, got %d", len(anscombe), len(records))
+	}
+	header := []string{"X", "Y", "Predicted", "Residual", "Leverage", "CooksDistance"}
+	for i, name := range header {
+		if records[0][i] != name {
+			t.Errorf("Expected column %d to be %q, got %q", i, name, records[0][i])
+		}
+	}
+	if records[1][0] != "10" || records[1][1] != "8.04" {
+		t.Errorf("Expected the first row to start 10, 8.04, got %v", records[1])
+	}
+}
+
+func TestQQAndResidualsVsFitted(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if _, _, err := r.QQ(); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Run()
+
+	fitted, residuals, err := r.ResidualsVsFitted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(fitted[0]-8.001) > 1e-3 || math.Abs(residuals[0]-0.039) > 1e-3 {
+		t.Errorf("Expected fitted 8.001 and residual 0.039, got %v and %v", fitted[0], residuals[0])
+	}
+
+	theoretical, sample, err := r.QQ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reference values from R's qqnorm(rstandard(lm(y1 ~ x1)))
+	if math.Abs(theoretical[0]+1.6906) > 1e-4 || math.Abs(theoretical[5]) > 1e-12 {
+		t.Errorf("Expected theoretical quantiles -1.6906 and 0, got %v and %v", theoretical[0], theoretical[5])
+	}
+	for i := 1; i < len(sample); i++ {
+		if sample[i] < sample[i-1] {
+			t.Fatalf("Expected sorted sample quantiles, got %v", sample)
+		}
+	}
+	if math.Abs(sample[0]+1.7779) > 1e-3 {
+		t.Errorf("Expected smallest standardized residual -1.7779, got %v", sample[0])
+	}
+}
+
+func TestWriteResultsCSVLabels(t *testing.T) {
+	r := new(Regression)
+	for i, row := range anscombe {
+		r.Train(LabeledDataPoint(fmt.Sprintf("S%02d", i), row[0], row[1:]))
+	}
+	r.Run()
+
+	var buf bytes.Buffer
+	if err := r.WriteResultsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0][0] != "Label" || records[1][0] != "S00" || records[11][0] != "S10" {
+		t.Errorf("Expected a leading label column, got %v, %v", records[0], records[1])
+	}
+}
+
+func TestInfluence(t *testing.T) {
+	r := new(Regression)
+	for i, row := range anscombe {
+		r.Train(LabeledDataPoint(fmt.Sprintf("S%02d", i), row[0], row[1:]))
+	}
+	r.Train(LabeledDataPoint("bad", 30, []float64{30}))
+	r.Run()
+
+	studentized, err := r.StudentizedResiduals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := r.Influence()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(report.CooksDistanceCutoff-4.0/12) > 1e-12 || math.Abs(report.LeverageCutoff-4.0/12) > 1e-9 {
+		t.Errorf("Expected cutoffs of 4/n and 2k/n, got %v and %v", report.CooksDistanceCutoff, report.LeverageCutoff)
+	}
+	var bad *InfluentialPoint
+	for i, p := range report.Points {
+		if p.Label == "bad" {
+			bad = &report.Points[i]
+		}
+	}
+	if bad == nil || bad.Index != 11 || !bad.HighLeverage || !bad.HighCooksDistance {
+		t.Fatalf("Expected the bad point to be flagged for leverage and Cook's distance, got %+v", report.Points)
+	}
+	if bad.StudentizedResidual != studentized[11] {
+		t.Errorf("Expected the studentized residual %v, got %v", studentized[11], bad.StudentizedResidual)
+	}
+
+	// The hat matrix of the design does not describe a fixed effects fit
+	panel := new(Regression)
+	panel.SetFixedEffects(true)
+	for i, row := range anscombe {
+		panel.Train(PanelDataPoint(fmt.Sprint(i%2), row[0], row[1:]))
+	}
+	if err := panel.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := panel.Influence(); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions for fixed effects, got %v", err)
+	}
+	if _, err := panel.ApproxLeverage(0); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions for fixed effects, got %v", err)
+	}
+}
+
+func TestStudentizedResiduals(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Run()
+	studentized, err := r.StudentizedResiduals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reference value from R's rstudent(lm(y1 ~ x1)) for the point at x = 13
+	if math.Abs(studentized[2]+2.081) > 1e-3 {
+		t.Errorf("Expected studentized residual -2.081, got %v", studentized[2])
+	}
+}
+
+func TestAddedVariable(t *testing.T) {
+	r := new(Regression)
+	for i := 0; i < 20; i++ {
+		x1 := float64(i)
+		x2 := x1 + 3*math.Sin(float64(i))
+		r.Train(DataPoint(1+2*x1-x2+math.Cos(float64(5*i)), []float64{x1, x2}))
+	}
+	if _, _, err := r.AddedVariable(0); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		x, y, err := r.AddedVariable(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sxy, sxx, sy float64
+		for k := range x {
+			sxy += x[k] * y[k]
+			sxx += x[k] * x[k]
+			sy += y[k]
+		}
+		if slope := sxy / sxx; math.Abs(slope-r.Coeff(i+1)) > 1e-9 {
+			t.Errorf("Expected the slope of variable %d to be its coefficient %v, got %v", i, r.Coeff(i+1), slope)
+		}
+		if math.Abs(sy) > 1e-9 {
+			t.Errorf("Expected the residuals of y to have zero mean, got a sum of %v", sy)
+		}
+	}
+	if _, _, err := r.AddedVariable(2); !errors.Is(err, ErrVariableIndex) {
+		t.Errorf("Expected ErrVariableIndex, got %v", err)
+	}
+}
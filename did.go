@@ -0,0 +1,101 @@
+package regression
+
+import (
+	"fmt"
+)
+
+// DiDSpec describes a difference-in-differences design on labeled data points, where the label of a
+// data point identifies the unit it was observed on.
+type DiDSpec struct {
+	// Treated holds the labels of the units that received the treatment.
+	Treated []string
+	// TimeVar is the index of the variable holding the time of each observation. Observations at or
+	// after TreatmentStart are in the post treatment period.
+	TimeVar        int
+	TreatmentStart float64
+	// Covariance is the estimator of the standard errors. ClusterRobust, which clusters by unit, is
+	// recommended since the errors of a unit are usually correlated over time.
+	Covariance CovarianceType
+}
+
+// DiDResult is the fit of a difference-in-differences design.
+type DiDResult struct {
+	// Model is the fitted regression on Treated, Post, Treated:Post and any other variables.
+	Model *Regression
+	// Effect is the coefficient of Treated:Post, the estimated average effect of the treatment on the
+	// treated, with its standard error, t statistic, two-sided p-value and 95% confidence interval.
+	Effect   float64
+	StdErr   float64
+	TValue   float64
+	PValue   float64
+	ConfLow  float64
+	ConfHigh float64
+}
+
+// DiffInDiff fits the difference-in-differences regression
+//
+//	y = b0 + b1·Treated + b2·Post + b3·Treated·Post + ... + e
+//
+// where Treated indicates the units in spec.Treated and Post the observations from the start of the
+// treatment. Any variables other than the time are included as covariates, named var0, var1 and so on
+// by their index in the data points. The data points are copied, with their labels as the entities so
+// that ClusterRobust errors cluster by unit.
+func DiffInDiff(data DataPoints, spec DiDSpec) (*DiDResult, error) {
+	treated := make(map[string]bool, len(spec.Treated))
+	for _, label := range spec.Treated {
+		treated[label] = true
+	}
+
+	r := new(Regression)
+	r.SetCovarianceType(spec.Covariance)
+	r.SetVar(0, "Treated")
+	r.SetVar(1, "Post")
+	r.SetVar(2, "Treated:Post")
+	var anyTreated, anyControl bool
+	for i, d := range data {
+		if spec.TimeVar < 0 || spec.TimeVar >= len(d.Variables) {
+			return nil, &DataPointError{Index: i, Err: fmt.Errorf("%w: no time variable %d", ErrDesign, spec.TimeVar)}
+		}
+		var t, post float64
+		if treated[d.Label] {
+			t = 1
+			anyTreated = true
+		} else {
+			anyControl = true
+		}
+		if d.Variables[spec.TimeVar] >= spec.TreatmentStart {
+			post = 1
+		}
+		vars := []float64{t, post, t * post}
+		for j, v := range d.Variables {
+			if j != spec.TimeVar {
+				if i == 0 {
+					r.SetVar(len(vars), fmt.Sprintf("var%d", j))
+				}
+				vars = append(vars, v)
+			}
+		}
+		r.Train(&dataPoint{Observed: d.Observed, Variables: vars, Entity: d.Label, Label: d.Label})
+	}
+	if !anyTreated || !anyControl {
+		return nil, fmt.Errorf("%w: a difference-in-differences design needs both treated and control units", ErrDesign)
+	}
+	if err := r.Run(); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Tidy()
+	if err != nil {
+		return nil, err
+	}
+	effect := rows[3]
+	return &DiDResult{
+		Model:    r,
+		Effect:   effect.Estimate,
+		StdErr:   effect.StdErr,
+		TValue:   effect.TValue,
+		PValue:   effect.PValue,
+		ConfLow:  effect.ConfLow,
+		ConfHigh: effect.ConfHigh,
+	}, nil
+}
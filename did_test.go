@@ -0,0 +1,54 @@
+package regression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDiffInDiff(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var data DataPoints
+	var treated []string
+	for u := 0; u < 20; u++ {
+		label := fmt.Sprintf("unit%d", u)
+		level := rng.NormFloat64()
+		isTreated := u%2 == 0
+		if isTreated {
+			treated = append(treated, label)
+		}
+		for period := 0; period < 6; period++ {
+			y := 1 + level + 0.5*float64(period) + 0.1*rng.NormFloat64()
+			if isTreated && period >= 3 {
+				y += 2
+			}
+			data = append(data, LabeledDataPoint(label, y, []float64{float64(period)}))
+		}
+	}
+
+	res, err := DiffInDiff(data, DiDSpec{Treated: treated, TimeVar: 0, TreatmentStart: 3, Covariance: ClusterRobust})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(res.Effect-2) > 3*res.StdErr || res.ConfLow > 2 || res.ConfHigh < 2 {
+		t.Errorf("Expected an effect of 2, got %v ± %v", res.Effect, res.StdErr)
+	}
+	if res.PValue > 1e-6 {
+		t.Errorf("Expected a significant effect, got p = %v", res.PValue)
+	}
+	if got := res.Model.GetVar(2); got != "Treated:Post" {
+		t.Errorf("Expected the interaction to be named Treated:Post, got %v", got)
+	}
+	if data[0].Variables[0] != 0 || len(data[0].Variables) != 1 {
+		t.Errorf("Expected the data points to be unchanged, got %v", data[0].Variables)
+	}
+
+	if _, err := DiffInDiff(data, DiDSpec{TimeVar: 0, TreatmentStart: 3}); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign without treated units, got %v", err)
+	}
+	if _, err := DiffInDiff(data, DiDSpec{Treated: treated, TimeVar: 1}); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for a missing time variable, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+// Package doe generates the data points of common designed experiments, ready to be run and then
+// fitted with the regression package. Designs are generated in coded units, with each factor between
+// -1 and 1, and can be mapped to the natural units of the factors with Decode. The Observed value of
+// each generated point is NaN until it is set to the result of the run.
+package doe
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Synthace/regression"
+)
+
+// FullFactorial returns every combination of the levels of the factors, with levels[j] ≥ 2 levels of
+// factor j evenly spaced from -1 to 1. The first factor varies slowest.
+func FullFactorial(levels ...int) (regression.DataPoints, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%w: no factors", regression.ErrDesign)
+	}
+	runs := 1
+	for j, l := range levels {
+		if l < 2 {
+			return nil, fmt.Errorf("%w: factor %d has %d levels, need at least 2", regression.ErrDesign, j, l)
+		}
+		runs *= l
+	}
+	points := make(regression.DataPoints, runs)
+	for i := range points {
+		vars := make([]float64, len(levels))
+		rest := i
+		for j := len(levels) - 1; j >= 0; j-- {
+			vars[j] = -1 + 2*float64(rest%levels[j])/float64(levels[j]-1)
+			rest /= levels[j]
+		}
+		points[i] = regression.DataPoint(math.NaN(), vars)
+	}
+	return points, nil
+}
+
+// FractionalFactorial returns a two level fractional factorial design: a full factorial in the first
+// base factors, followed by a factor for each generator set to the product of the base factors it
+// lists. For example FractionalFactorial(3, []int{0, 1, 2}) is the 2⁴⁻¹ design with D = ABC, of
+// resolution IV, in 8 runs.
+func FractionalFactorial(base int, generators ...[]int) (regression.DataPoints, error) {
+	levels := make([]int, base)
+	for j := range levels {
+		levels[j] = 2
+	}
+	for g, generator := range generators {
+		if len(generator) < 2 {
+			return nil, fmt.Errorf("%w: generator %d is the product of %d factors, need at least 2", regression.ErrDesign, g, len(generator))
+		}
+		for _, j := range generator {
+			if j < 0 || j >= base {
+				return nil, fmt.Errorf("%w: generator %d uses factor %d of %d base factors", regression.ErrDesign, g, j, base)
+			}
+		}
+	}
+	points, err := FullFactorial(levels...)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range points {
+		for _, generator := range generators {
+			v := 1.0
+			for _, j := range generator {
+				v *= d.Variables[j]
+			}
+			d.Variables = append(d.Variables, v)
+		}
+	}
+	return points, nil
+}
+
+// CentralComposite returns a central composite design in k factors for fitting a quadratic response
+// surface: the 2ᵏ factorial points, two axial points at ±alpha on each factor's axis and the given
+// number of center points, which estimate pure error. An alpha of 0 chooses the rotatable design,
+// alpha = 2^(k/4), whose prediction variance depends only on the distance from the center, and an
+// alpha of 1 gives the face centered design, which keeps every factor within its coded range.
+func CentralComposite(k int, alpha float64, center int) (regression.DataPoints, error) {
+	if alpha == 0 {
+		alpha = math.Pow(2, float64(k)/4)
+	}
+	if !(alpha > 0) || center < 0 {
+		return nil, fmt.Errorf("%w: axial distance %v with %d center points", regression.ErrDesign, alpha, center)
+	}
+	levels := make([]int, k)
+	for j := range levels {
+		levels[j] = 2
+	}
+	points, err := FullFactorial(levels...)
+	if err != nil {
+		return nil, err
+	}
+	for j := 0; j < k; j++ {
+		for _, sign := range []float64{-1, 1} {
+			vars := make([]float64, k)
+			vars[j] = sign * alpha
+			points = append(points, regression.DataPoint(math.NaN(), vars))
+		}
+	}
+	for i := 0; i < center; i++ {
+		points = append(points, regression.DataPoint(math.NaN(), make([]float64, k)))
+	}
+	return points, nil
+}
+
+// Decode maps the points from coded units to the natural units of the factors, where -1 is low[j]
+// and 1 is high[j] for factor j, returning new points so the coded design is kept.
+func Decode(points regression.DataPoints, low, high []float64) (regression.DataPoints, error) {
+	if len(low) != len(high) {
+		return nil, fmt.Errorf("%w: %d low and %d high values", regression.ErrDesign, len(low), len(high))
+	}
+	decoded := make(regression.DataPoints, len(points))
+	for i, d := range points {
+		if len(d.Variables) != len(low) {
+			return nil, &regression.DataPointError{Index: i, Err: fmt.Errorf("%w: got %d, expected %d", regression.ErrVariableCount, len(d.Variables), len(low))}
+		}
+		vars := make([]float64, len(d.Variables))
+		for j, v := range d.Variables {
+			vars[j] = (low[j]+high[j])/2 + v*(high[j]-low[j])/2
+		}
+		decoded[i] = regression.DataPoint(d.Observed, vars)
+	}
+	return decoded, nil
+}
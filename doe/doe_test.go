@@ -0,0 +1,96 @@
+package doe
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Synthace/regression"
+)
+
+func TestFactorial(t *testing.T) {
+	full, err := FullFactorial(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]float64{{-1, -1}, {-1, 0}, {-1, 1}, {1, -1}, {1, 0}, {1, 1}}
+	if len(full) != len(want) {
+		t.Fatalf("Expected %d runs, got %d", len(want), len(full))
+	}
+	for i, d := range full {
+		if d.Variables[0] != want[i][0] || d.Variables[1] != want[i][1] || !math.IsNaN(d.Observed) {
+			t.Errorf("Expected run %d at %v awaiting observation, got %v with %v", i, want[i], d.Variables, d.Observed)
+		}
+	}
+
+	half, err := FractionalFactorial(3, []int{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(half) != 8 {
+		t.Fatalf("Expected 8 runs, got %d", len(half))
+	}
+	// The columns of a regular fraction are balanced and orthogonal, with D = ABC
+	for a := 0; a < 4; a++ {
+		for b := a; b < 4; b++ {
+			var dot float64
+			for _, d := range half {
+				dot += d.Variables[a] * d.Variables[b]
+			}
+			if a == b && dot != 8 || a != b && dot != 0 {
+				t.Errorf("Expected columns %d and %d to be orthogonal, got inner product %v", a, b, dot)
+			}
+		}
+	}
+	for _, d := range half {
+		if d.Variables[3] != d.Variables[0]*d.Variables[1]*d.Variables[2] {
+			t.Errorf("Expected D = ABC, got %v", d.Variables)
+		}
+	}
+
+	if _, err := FullFactorial(2, 1); !errors.Is(err, regression.ErrDesign) {
+		t.Errorf("Expected ErrDesign for a factor with one level, got %v", err)
+	}
+	if _, err := FractionalFactorial(3, []int{0, 3}); !errors.Is(err, regression.ErrDesign) {
+		t.Errorf("Expected ErrDesign for a generator using an added factor, got %v", err)
+	}
+}
+
+func TestCentralComposite(t *testing.T) {
+	points, err := CentralComposite(2, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 11 {
+		t.Fatalf("Expected 4 factorial, 4 axial and 3 center runs, got %d", len(points))
+	}
+	if math.Abs(points[4].Variables[0]+math.Sqrt2) > 1e-12 {
+		t.Errorf("Expected the rotatable axial distance √2, got %v", points[4].Variables)
+	}
+
+	// Run the experiment in natural units and fit the full quadratic response surface
+	natural, err := Decode(points, []float64{20, 1}, []float64{40, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if natural[0].Variables[0] != 20 || natural[0].Variables[1] != 1 || natural[10].Variables[0] != 30 {
+		t.Errorf("Expected runs at (20, 1) and centered on 30, got %v and %v", natural[0].Variables, natural[10].Variables)
+	}
+	r := new(regression.Regression)
+	r.AddCross(regression.PowCross(0, 2))
+	r.AddCross(regression.PowCross(1, 2))
+	r.AddCross(regression.MultiplierCross(0, 1))
+	for _, d := range natural {
+		x, z := d.Variables[0], d.Variables[1]
+		d.Observed = 5 + 0.2*x - 3*z - 0.01*x*x + 0.5*z*z + 0.1*x*z
+	}
+	r.Train(natural...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{5, 0.2, -3, -0.01, 0.5, 0.1} {
+		if math.Abs(r.Coeff(i)-want) > 1e-6 {
+			t.Errorf("Expected coefficient %d to be %v, got %v", i, want, r.Coeff(i))
+		}
+	}
+}
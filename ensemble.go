@@ -0,0 +1,109 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Ensemble is a bagged ensemble of regressions, each fitted to a bootstrap resample of the training data.
+type Ensemble struct {
+	Models []*Regression
+	// OOBRMSE is the out-of-bag error: the root mean squared error of predicting each data point from the
+	// models whose resample left it out.
+	OOBRMSE float64
+}
+
+// Bag fits b models configured like r, each to a bootstrap resample of its training data, in parallel.
+// Resamples for which the model cannot be fitted, for example because they have too few distinct points,
+// are drawn again.
+func (r *Regression) Bag(b int, opts ...Option) (*Ensemble, error) {
+	o := newOptions(opts)
+	points := r.rawData()
+	n := len(points)
+	if b < 1 || n < 3 {
+		return nil, fmt.Errorf("%w: %d models from %d data points", ErrNotEnoughData, b, n)
+	}
+
+	// Seed each model's resampling up front so the result does not depend on scheduling
+	seeds := make([]int64, b)
+	for m := range seeds {
+		seeds[m] = o.rand.Int63()
+	}
+
+	const maxDraws = 10
+	models := make([]*Regression, b)
+	inBag := make([][]bool, b)
+	errs := make([]error, b)
+	parallelFor(o.concurrency, b, o.track("bag", b, func(m int) {
+		rng := rand.New(rand.NewSource(seeds[m]))
+		for draw := 0; draw < maxDraws; draw++ {
+			inBag[m] = make([]bool, n)
+			resample := make(DataPoints, n)
+			for i := range resample {
+				j := rng.Intn(n)
+				resample[i] = points[j]
+				inBag[m][j] = true
+			}
+			models[m], errs[m] = r.fitLike(resample)
+			if errs[m] == nil {
+				return
+			}
+		}
+	}))
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	e := &Ensemble{Models: models}
+	var sse float64
+	var count int
+	for i, d := range points {
+		var sum float64
+		var k int
+		for m := range models {
+			if inBag[m][i] {
+				continue
+			}
+			p, err := models[m].predict(d.Variables)
+			if err != nil {
+				return nil, err
+			}
+			sum += p
+			k++
+		}
+		if k > 0 {
+			sse += math.Pow(d.Observed-sum/float64(k), 2)
+			count++
+		}
+	}
+	e.OOBRMSE = math.NaN()
+	if count > 0 {
+		e.OOBRMSE = math.Sqrt(sse / float64(count))
+	}
+	return e, nil
+}
+
+// Predict returns the average of the predictions of the models.
+func (e *Ensemble) Predict(vars []float64) (float64, error) {
+	var sum float64
+	for _, m := range e.Models {
+		p, err := m.Predict(vars)
+		if err != nil {
+			return 0, err
+		}
+		sum += p
+	}
+	return sum / float64(len(e.Models)), nil
+}
+
+// Coeffs returns the coefficients of each model, with the offset at index 0.
+func (e *Ensemble) Coeffs() [][]float64 {
+	coeffs := make([][]float64, len(e.Models))
+	for i, m := range e.Models {
+		coeffs[i] = m.GetCoeffs()
+	}
+	return coeffs
+}
@@ -0,0 +1,41 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBag(t *testing.T) {
+	r := new(Regression)
+	for i := 0; i < 40; i++ {
+		x := float64(i % 10)
+		r.Train(DataPoint(2+3*x+0.5*math.Sin(float64(i*i)), []float64{x}))
+	}
+	r.Run()
+
+	e, err := r.Bag(25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Models) != 25 || len(e.Coeffs()) != 25 {
+		t.Fatalf("Expected 25 models, got %d", len(e.Models))
+	}
+	var mean float64
+	for _, c := range e.Coeffs() {
+		mean += c[1] / 25
+	}
+	if math.Abs(mean-r.Coeff(1)) > 0.1 {
+		t.Errorf("Expected the bagged slopes to average close to %v, got %v", r.Coeff(1), mean)
+	}
+
+	p, err := e.Predict([]float64{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(p-14) > 0.5 {
+		t.Errorf("Expected a prediction close to 14, got %v", p)
+	}
+	if math.IsNaN(e.OOBRMSE) || e.OOBRMSE < 0.2 || e.OOBRMSE > 1 {
+		t.Errorf("Expected an out-of-bag error comparable to the noise, got %v", e.OOBRMSE)
+	}
+}
@@ -0,0 +1,63 @@
+package regression
+
+import (
+	"math"
+)
+
+// Metrics summarises how well a fitted model predicts a set of data points.
+type Metrics struct {
+	N int
+	// R2 is 1 - SSE/SST about the mean of the evaluated observed values, so it can be negative for
+	// a model that predicts worse than that mean.
+	R2   float64
+	RMSE float64
+	MAE  float64
+	// Residuals holds the observed minus the predicted value of each data point.
+	Residuals []float64
+}
+
+// Evaluate measures the accuracy of the fitted model on held-out data points, without changing the
+// model, its training data or the evaluated points.
+func (r *Regression) Evaluate(test DataPoints) (Metrics, error) {
+	if !r.hasRun {
+		return Metrics{}, ErrRegressionNotRun
+	}
+	return EvaluatePredictor(predictorFunc(r.predict), test)
+}
+
+// predictorFunc adapts a prediction function to a Predictor, so that evaluation within the package
+// can predict without instrumenting each prediction.
+type predictorFunc func(vars []float64) (float64, error)
+
+func (f predictorFunc) Predict(vars []float64) (float64, error) {
+	return f(vars)
+}
+
+// EvaluatePredictor measures the accuracy of any fitted model on held-out data points, so that a
+// linear model can be compared with a baseline such as NearestNeighbors on the same data.
+func EvaluatePredictor(p Predictor, test DataPoints) (Metrics, error) {
+	if len(test) == 0 {
+		return Metrics{}, ErrNotEnoughData
+	}
+
+	m := Metrics{N: len(test), Residuals: make([]float64, len(test))}
+	var mean, sse, sst, sae float64
+	for i, d := range test {
+		predicted, err := p.Predict(d.Variables)
+		if err != nil {
+			return Metrics{}, &DataPointError{Index: i, Err: err}
+		}
+		e := d.Observed - predicted
+		m.Residuals[i] = e
+		mean += d.Observed / float64(len(test))
+		sse += e * e
+		sae += math.Abs(e)
+	}
+	for _, d := range test {
+		sst += (d.Observed - mean) * (d.Observed - mean)
+	}
+	m.R2 = 1 - sse/sst
+	m.RMSE = math.Sqrt(sse / float64(len(test)))
+	m.MAE = sae / float64(len(test))
+	return m, nil
+}
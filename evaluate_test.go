@@ -0,0 +1,53 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if _, err := r.Evaluate(MakeDataPoints(anscombe, 0)); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Run()
+
+	// Evaluating on the training data reproduces the in-sample statistics
+	m, err := r.Evaluate(MakeDataPoints(anscombe, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.N != 11 || math.Abs(m.R2-r.R2) > 1e-12 {
+		t.Errorf("Expected R2 %v on 11 points, got %v on %d", r.R2, m.R2, m.N)
+	}
+
+	test := DataPoints{DataPoint(9, []float64{10}), DataPoint(6, []float64{4}), DataPoint(7, []float64{6})}
+	coeffs := r.GetCoeffs()
+	m, err = r.Evaluate(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sse, sae float64
+	for i, d := range test {
+		e := d.Observed - (coeffs[0] + coeffs[1]*d.Variables[0])
+		if math.Abs(m.Residuals[i]-e) > 1e-12 {
+			t.Errorf("Expected residual %v, got %v", e, m.Residuals[i])
+		}
+		sse += e * e
+		sae += math.Abs(e)
+	}
+	if math.Abs(m.RMSE-math.Sqrt(sse/3)) > 1e-12 || math.Abs(m.MAE-sae/3) > 1e-12 {
+		t.Errorf("Expected RMSE %v and MAE %v, got %v and %v", math.Sqrt(sse/3), sae/3, m.RMSE, m.MAE)
+	}
+	if test[0].Predicted != 0 || len(r.Data) != 11 {
+		t.Errorf("Expected the test points and training data to be unchanged")
+	}
+
+	_, err = r.Evaluate(DataPoints{DataPoint(1, []float64{1, 2})})
+	var pointErr *DataPointError
+	if !errors.As(err, &pointErr) || !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected a DataPointError wrapping ErrVariableCount, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package regression
+
+import (
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CheckedPrediction is a prediction along with checks of whether it extrapolates beyond the training data.
+type CheckedPrediction struct {
+	Value float64
+	// OutOfRange holds the indices of the variables outside the range seen in training.
+	OutOfRange []int
+	// Leverage is the leverage the point would have in the training design. A value above MaxLeverage,
+	// the largest leverage of the training data, means the point lies outside the ellipsoid containing
+	// the training data, a proxy for its convex hull, even if each variable is within its range.
+	Leverage    float64
+	MaxLeverage float64
+	// Extrapolated is true if any variable is out of range or the leverage exceeds MaxLeverage.
+	Extrapolated bool
+}
+
+// PredictChecked predicts the observed value like Predict, and also flags whether the variables lie
+// outside the range of the training data or outside the region spanned by it.
+func (r *Regression) PredictChecked(vars []float64) (*CheckedPrediction, error) {
+	value, err := r.Predict(vars)
+	if err != nil {
+		return nil, err
+	}
+	return r.checkExtrapolation(vars, value)
+}
+
+// extrapolationBounds holds what checkExtrapolation needs from the training data, computed the first
+// time it is needed after a fit rather than for every prediction.
+type extrapolationBounds struct {
+	once        sync.Once
+	min, max    []float64
+	active      []int
+	inv         *mat.SymDense
+	maxLeverage float64
+	err         error
+}
+
+// compute sets the range of each variable and the largest leverage in the training data.
+func (b *extrapolationBounds) compute(r *Regression) {
+	x, active, inv, err := r.hat()
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.active, b.inv = active, inv
+	b.min = make([]float64, len(r.Data[0].Variables))
+	b.max = make([]float64, len(b.min))
+	for j := range b.min {
+		b.min[j], b.max[j] = math.Inf(1), math.Inf(-1)
+		for _, d := range r.Data {
+			b.min[j] = math.Min(b.min[j], d.Variables[j])
+			b.max[j] = math.Max(b.max[j], d.Variables[j])
+		}
+	}
+	rows, _ := x.Dims()
+	for i := 0; i < rows; i++ {
+		training := x.RowView(i)
+		b.maxLeverage = math.Max(b.maxLeverage, mat.Inner(training, inv, training))
+	}
+}
+
+// checkExtrapolation returns the checks of whether the prediction value for vars extrapolates.
+func (r *Regression) checkExtrapolation(vars []float64, value float64) (*CheckedPrediction, error) {
+	b := r.extrapolation
+	if b == nil {
+		b = new(extrapolationBounds)
+	}
+	b.once.Do(func() { b.compute(r) })
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	p := &CheckedPrediction{Value: value, MaxLeverage: b.maxLeverage}
+	for j, v := range vars {
+		if v < b.min[j] || v > b.max[j] {
+			p.OutOfRange = append(p.OutOfRange, j)
+		}
+	}
+
+	// Leverage of the point, with any feature crosses applied
+	crossed := append([]float64{1}, r.features(vars)...)
+	row := mat.NewVecDense(len(b.active), nil)
+	for k, j := range b.active {
+		row.SetVec(k, crossed[j])
+	}
+	p.Leverage = mat.Inner(row, b.inv, row)
+	p.Extrapolated = len(p.OutOfRange) > 0 || p.Leverage > p.MaxLeverage
+	return p, nil
+}
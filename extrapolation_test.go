@@ -0,0 +1,48 @@
+package regression
+
+import (
+	"testing"
+)
+
+func TestPredictChecked(t *testing.T) {
+	r := new(Regression)
+	// x1 and x2 move together, so (1, 9) is within both ranges but far from the data
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		r.Train(DataPoint(1+x, []float64{x, x + float64(i%2)}))
+	}
+	r.Run()
+
+	for _, c := range []struct {
+		vars         []float64
+		outOfRange   int
+		extrapolated bool
+	}{
+		{[]float64{4, 4.5}, 0, false},
+		{[]float64{12, 4}, 1, true},
+		{[]float64{1, 9}, 0, true},
+	} {
+		p, err := r.PredictChecked(c.vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, _ := r.Predict(c.vars); p.Value != expected {
+			t.Errorf("%v: expected the prediction %v, got %v", c.vars, expected, p.Value)
+		}
+		if len(p.OutOfRange) != c.outOfRange || p.Extrapolated != c.extrapolated {
+			t.Errorf("%v: expected %d variables out of range and extrapolated %v, got %+v", c.vars, c.outOfRange, c.extrapolated, p)
+		}
+	}
+
+	// The training ranges are computed once, and again after the data is updated
+	if err := r.Update(DataPoint(13, []float64{12, 12})); err != nil {
+		t.Fatal(err)
+	}
+	p, err := r.PredictChecked([]float64{11, 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Extrapolated {
+		t.Errorf("Expected a point within the updated data not to be extrapolated, got %+v", p)
+	}
+}
@@ -0,0 +1,145 @@
+package regression
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// formulaTerm is a variable of the fitted equation with its coefficient.
+type formulaTerm struct {
+	name  string
+	coeff float64
+}
+
+// formulaTerms returns the variables of the fitted equation in order, leaving out any with a zero
+// coefficient, such as dropped variables and instruments.
+func (r *Regression) formulaTerms() []formulaTerm {
+	var terms []formulaTerm
+	for i := 1; i < len(r.coeff); i++ {
+		if c := r.Coeff(i); c != 0 {
+			terms = append(terms, formulaTerm{name: r.GetVar(i - 1), coeff: c})
+		}
+	}
+	return terms
+}
+
+// formatCoeff formats the magnitude of a coefficient to the given number of decimal places,
+// or the fewest digits needed to represent it exactly if precision is negative.
+func formatCoeff(c float64, precision int) string {
+	if precision < 0 {
+		return strconv.FormatFloat(math.Abs(c), 'g', -1, 64)
+	}
+	return strconv.FormatFloat(math.Abs(c), 'f', precision, 64)
+}
+
+// formatCoeffScientific formats the magnitude of a coefficient like formatCoeff, but switches to
+// scientific notation with precision significant decimals for coefficients of a million or more, and
+// for those too small to show at the given precision.
+func formatCoeffScientific(c float64, precision int) string {
+	abs := math.Abs(c)
+	if precision >= 0 && (abs >= 1e6 || (abs != 0 && abs < 0.5*math.Pow10(-precision))) {
+		return strconv.FormatFloat(abs, 'e', precision, 64)
+	}
+	return formatCoeff(c, precision)
+}
+
+// renderFormula renders the fitted equation, with a minus sign for negative coefficients rather than
+// adding a negative number. Each coefficient magnitude is rendered by the format function and each
+// variable name by the term function.
+func (r *Regression) renderFormula(lhs, times, minus string, format func(float64) string, term func(string) string) string {
+	var b strings.Builder
+	b.WriteString(lhs)
+	b.WriteString(" = ")
+	if r.Coeff(0) < 0 {
+		b.WriteString(minus)
+	}
+	b.WriteString(format(r.Coeff(0)))
+	for _, t := range r.formulaTerms() {
+		if t.coeff < 0 {
+			b.WriteString(" " + minus + " ")
+		} else {
+			b.WriteString(" + ")
+		}
+		b.WriteString(format(t.coeff))
+		b.WriteString(times)
+		b.WriteString(term(t.name))
+	}
+	return b.String()
+}
+
+// decimals returns a format function for formatCoeff with the given precision.
+func decimals(precision int) func(float64) string {
+	return func(c float64) string {
+		return formatCoeff(c, precision)
+	}
+}
+
+// FormatFormula renders the fitted equation as plain text, such as "Predicted = 1.5000 - 0.2500*X0",
+// with coefficients to the given number of decimal places. Coefficients of a million or more, and
+// those too small to show at the precision, use scientific notation. A negative precision gives the
+// fewest digits needed to represent each coefficient exactly.
+func (r *Regression) FormatFormula(precision int) string {
+	return r.renderFormula("Predicted", "*", "-", func(c float64) string {
+		return formatCoeffScientific(c, precision)
+	}, func(name string) string {
+		return name
+	})
+}
+
+// SetFormulaPrecision sets the number of decimal places of the coefficients in Formula, which is 4 by
+// default. A negative precision gives the fewest digits needed to represent each coefficient exactly.
+func (r *Regression) SetFormulaPrecision(precision int) {
+	r.formulaPrecision = &precision
+}
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`^`, `\^{}`,
+	`~`, `\~{}`,
+)
+
+// FormulaLaTeX renders the fitted equation as LaTeX math, for inclusion in reports, with
+// coefficients to the given number of decimal places. A negative precision gives the fewest
+// digits needed to represent each coefficient exactly.
+func (r *Regression) FormulaLaTeX(precision int) string {
+	lhs := `\hat{y}`
+	if obs := r.GetObserved(); obs != "" {
+		lhs = `\widehat{\text{` + latexEscaper.Replace(obs) + `}}`
+	}
+	return r.renderFormula(lhs, ` \cdot `, "-", decimals(precision), func(name string) string {
+		return `\text{` + latexEscaper.Replace(name) + `}`
+	})
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`|`, `\|`,
+)
+
+// FormulaMarkdown renders the fitted equation as Markdown, for inclusion in notebooks, with
+// coefficients to the given number of decimal places. A negative precision gives the fewest
+// digits needed to represent each coefficient exactly.
+func (r *Regression) FormulaMarkdown(precision int) string {
+	lhs := "Predicted"
+	if obs := r.GetObserved(); obs != "" {
+		lhs = "Predicted " + markdownEscaper.Replace(obs)
+	}
+	return r.renderFormula("**"+lhs+"**", " × ", "−", decimals(precision), func(name string) string {
+		return "*" + markdownEscaper.Replace(name) + "*"
+	})
+}
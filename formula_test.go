@@ -0,0 +1,67 @@
+package regression
+
+import (
+	"testing"
+)
+
+func formulaModel() *Regression {
+	r := new(Regression)
+	r.SetObserved("Yield_%")
+	r.SetVar(0, "Temp")
+	r.SetVar(1, "pH*")
+	r.coeff = []float64{-1.5, 2.25, -0.125}
+	return r
+}
+
+func TestFormulaLaTeX(t *testing.T) {
+	r := formulaModel()
+	expected := `\widehat{\text{Yield\_\%}} = -1.50 + 2.25 \cdot \text{Temp} - 0.12 \cdot \text{pH*}`
+	if f := r.FormulaLaTeX(2); f != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, f)
+	}
+	expected = `\widehat{\text{Yield\_\%}} = -1.5 + 2.25 \cdot \text{Temp} - 0.125 \cdot \text{pH*}`
+	if f := r.FormulaLaTeX(-1); f != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, f)
+	}
+
+	r.SetObserved("")
+	r.coeff[2] = 0
+	expected = `\hat{y} = -1.5 + 2.2 \cdot \text{Temp}`
+	if f := r.FormulaLaTeX(1); f != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, f)
+	}
+}
+
+func TestFormulaMarkdown(t *testing.T) {
+	r := formulaModel()
+	expected := `**Predicted Yield\_%** = −1.500 + 2.250 × *Temp* − 0.125 × *pH\**`
+	if f := r.FormulaMarkdown(3); f != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, f)
+	}
+}
+
+func TestFormatFormula(t *testing.T) {
+	r := formulaModel()
+	expected := "Predicted = -1.50 + 2.25*Temp - 0.12*pH*"
+	if f := r.FormatFormula(2); f != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, f)
+	}
+
+	r.coeff[1] = 2.5e7
+	r.coeff[2] = -3e-6
+	expected = "Predicted = -1.500 + 2.500e+07*Temp - 3.000e-06*pH*"
+	if f := r.FormatFormula(3); f != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, f)
+	}
+}
+
+func TestSetFormulaPrecision(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "x")
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.SetFormulaPrecision(1)
+	r.Run()
+	if expected := "Predicted = 3.0 + 0.5*x"; r.Formula != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, r.Formula)
+	}
+}
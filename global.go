@@ -0,0 +1,190 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+)
+
+// GlobalFit fits a nonlinear model to several datasets at once, such as replicate titration curves,
+// with some parameters shared by every dataset and the others estimated for each. Sharing parameters
+// that should not differ between datasets, such as the slope of replicate dose-response curves, makes
+// their estimates and those of the remaining parameters more precise.
+type GlobalFit struct {
+	model     NonlinearModel
+	shared    map[string]bool
+	datasets  []DataPoints
+	weighting Weighting
+	fit       *Nonlinear
+	// index maps dataset and parameter to the position of the parameter in the combined fit.
+	index [][]int
+}
+
+// NewGlobalFit creates a global fit of the model with the named parameters shared by every dataset.
+func NewGlobalFit(model NonlinearModel, shared ...string) *GlobalFit {
+	g := &GlobalFit{model: model, shared: make(map[string]bool, len(shared))}
+	for _, name := range shared {
+		g.shared[name] = true
+	}
+	return g
+}
+
+// Add adds a dataset to the fit, returning its index.
+func (g *GlobalFit) Add(data DataPoints) int {
+	g.datasets = append(g.datasets, data)
+	return len(g.datasets) - 1
+}
+
+// SetWeighting sets the weighting of the data points from their observed values, as for Nonlinear.
+func (g *GlobalFit) SetWeighting(w Weighting) {
+	g.weighting = w
+}
+
+// Run fits the model to every dataset at once. The shared parameters start from the mean of their
+// starting values for each dataset.
+func (g *GlobalFit) Run() error {
+	if g.fit != nil {
+		return ErrRegressionRun
+	}
+	if len(g.datasets) == 0 {
+		return fmt.Errorf("%w: no datasets to fit", ErrNotEnoughData)
+	}
+	k := len(g.model.Params)
+	known := make(map[string]bool, k)
+	for _, name := range g.model.Params {
+		known[name] = true
+	}
+	for name := range g.shared {
+		if !known[name] {
+			return fmt.Errorf("%w: the %s model has no parameter %q", ErrDesign, g.model.Name, name)
+		}
+	}
+
+	// Lay out the shared parameters first, then the others of each dataset in turn
+	var names []string
+	g.index = make([][]int, len(g.datasets))
+	for s := range g.index {
+		g.index[s] = make([]int, k)
+	}
+	for j, name := range g.model.Params {
+		if g.shared[name] {
+			for s := range g.datasets {
+				g.index[s][j] = len(names)
+			}
+			names = append(names, name)
+		}
+	}
+	for s := range g.datasets {
+		for j, name := range g.model.Params {
+			if !g.shared[name] {
+				g.index[s][j] = len(names)
+				names = append(names, fmt.Sprintf("%s[%d]", name, s))
+			}
+		}
+	}
+
+	if g.model.Start == nil {
+		return fmt.Errorf("%w: the %s model has no starting values for a global fit", ErrDesign, g.model.Name)
+	}
+	start := make([]float64, len(names))
+	for s, data := range g.datasets {
+		theta := g.model.Start(data)
+		for j, v := range theta {
+			if g.shared[g.model.Params[j]] {
+				start[g.index[s][j]] += v / float64(len(g.datasets))
+			} else {
+				start[g.index[s][j]] = v
+			}
+		}
+	}
+
+	// The combined model reads the dataset of each point from an extra trailing variable
+	combined := NonlinearModel{
+		Name:   "global " + g.model.Name,
+		Params: names,
+		Func: func(x, theta []float64) float64 {
+			return g.model.Func(x[:len(x)-1], g.datasetParams(int(x[len(x)-1]), theta))
+		},
+	}
+	g.fit = NewNonlinear(combined)
+	g.fit.SetStart(start)
+	g.fit.SetWeighting(g.weighting)
+	for s, data := range g.datasets {
+		for _, d := range data {
+			vars := append(append([]float64(nil), d.Variables...), float64(s))
+			g.fit.Train(&dataPoint{Observed: d.Observed, Variables: vars, Label: d.Label})
+		}
+	}
+	if err := g.fit.Run(); err != nil {
+		g.fit = nil
+		return err
+	}
+	return nil
+}
+
+// datasetParams returns the parameters of the model for a dataset from those of the combined fit.
+func (g *GlobalFit) datasetParams(dataset int, theta []float64) []float64 {
+	params := make([]float64, len(g.model.Params))
+	for j := range params {
+		params[j] = theta[g.index[dataset][j]]
+	}
+	return params
+}
+
+// Nonlinear returns the combined fit, whose parameters are the shared parameters followed by those of
+// each dataset, named as "c[1]" for parameter c of dataset 1, and whose data points have the index of
+// their dataset as an extra trailing variable.
+func (g *GlobalFit) Nonlinear() *Nonlinear {
+	return g.fit
+}
+
+// Params returns the fitted parameters of the model for a dataset, shared and not.
+func (g *GlobalFit) Params(dataset int) ([]float64, error) {
+	if err := g.checkDataset(dataset); err != nil {
+		return nil, err
+	}
+	return g.datasetParams(dataset, g.fit.params), nil
+}
+
+// Param returns the fitted value of the named parameter for a dataset, with its standard error.
+func (g *GlobalFit) Param(dataset int, name string) (value, stdErr float64, err error) {
+	if err := g.checkDataset(dataset); err != nil {
+		return 0, 0, err
+	}
+	for j, p := range g.model.Params {
+		if p == name {
+			i := g.index[dataset][j]
+			return g.fit.Param(i), g.fit.StdErr(i), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: the %s model has no parameter %q", ErrDesign, g.model.Name, name)
+}
+
+// Predict returns the prediction of the model fitted to a dataset for vars.
+func (g *GlobalFit) Predict(dataset int, vars []float64) (float64, error) {
+	if err := g.checkDataset(dataset); err != nil {
+		return 0, err
+	}
+	if want := len(g.fit.Data[0].Variables) - 1; len(vars) != want {
+		return 0, fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), want)
+	}
+	return g.model.Func(vars, g.datasetParams(dataset, g.fit.params)), nil
+}
+
+// checkDataset checks that the fit has been run and the dataset exists.
+func (g *GlobalFit) checkDataset(dataset int) error {
+	if g.fit == nil {
+		return ErrRegressionNotRun
+	}
+	if dataset < 0 || dataset >= len(g.datasets) {
+		return fmt.Errorf("%w: dataset %d of %d", ErrDataIndex, dataset, len(g.datasets))
+	}
+	return nil
+}
+
+// Sigma returns the residual standard error of the combined fit.
+func (g *GlobalFit) Sigma() float64 {
+	if g.fit == nil {
+		return math.NaN()
+	}
+	return g.fit.Sigma()
+}
@@ -0,0 +1,63 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGlobalFit(t *testing.T) {
+	// Three replicate titrations sharing the slope and asymptotes, with different EC50s
+	rng := rand.New(rand.NewSource(1))
+	model := FourParameterLogistic()
+	ec50s := []float64{10, 30, 100}
+	g := NewGlobalFit(model, "a", "b", "d")
+	for _, c := range ec50s {
+		var data DataPoints
+		for _, x := range []float64{0, 1, 3, 10, 30, 100, 300, 1000} {
+			y := model.Func([]float64{x}, []float64{0.2, 1.5, c, 3})
+			data = append(data, DataPoint(y+0.03*rng.NormFloat64(), []float64{x}))
+		}
+		g.Add(data)
+	}
+	if _, _, err := g.Param(0, "c"); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := g.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 3 shared parameters and an EC50 for each dataset
+	if n := len(g.Nonlinear().Params()); n != 6 {
+		t.Errorf("Expected 6 parameters in the combined fit, got %d", n)
+	}
+	for s, want := range ec50s {
+		c, se, err := g.Param(s, "c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(c-want) > 3*se {
+			t.Errorf("Expected the EC50 of dataset %d near %v, got %v ± %v", s, want, c, se)
+		}
+	}
+	b0, se0, _ := g.Param(0, "b")
+	b2, se2, _ := g.Param(2, "b")
+	if b0 != b2 || se0 != se2 || math.Abs(b0-1.5) > 3*se0 {
+		t.Errorf("Expected a shared slope near 1.5, got %v ± %v and %v ± %v", b0, se0, b2, se2)
+	}
+	params, _ := g.Params(1)
+	y, err := g.Predict(1, []float64{params[2]})
+	if err != nil || math.Abs(y-(params[0]+params[3])/2) > 1e-9 {
+		t.Errorf("Expected the prediction at the EC50 of dataset 1 to be halfway, got %v, %v", y, err)
+	}
+
+	if _, err := g.Predict(3, []float64{1}); !errors.Is(err, ErrDataIndex) {
+		t.Errorf("Expected ErrDataIndex for an unknown dataset, got %v", err)
+	}
+	bad := NewGlobalFit(model, "slope")
+	bad.Add(DataPoints{DataPoint(1, []float64{1})})
+	if err := bad.Run(); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for an unknown shared parameter, got %v", err)
+	}
+}
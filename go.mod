@@ -1,5 +1,10 @@
 module github.com/Synthace/regression
 
-go 1.13
+go 1.21
 
-require gonum.org/v1/gonum v0.6.2
+require gonum.org/v1/gonum v0.14.0
+
+require (
+	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
+	golang.org/x/tools v0.14.0 // indirect
+)
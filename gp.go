@@ -0,0 +1,266 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat"
+)
+
+// GaussianProcess is a Gaussian process regression with a squared exponential (RBF) kernel, for
+// small datasets with a smooth nonlinear relationship that a linear model cannot capture. It is
+// trained with the same data points as Regression and implements Predictor. The variables are
+// standardized before fitting, so a single length scale applies to all of them.
+type GaussianProcess struct {
+	Data []*dataPoint
+
+	lengthScale    float64
+	signalVariance float64
+	noiseVariance  float64
+	optimize       bool
+	convergence    Convergence
+	progress       func(Progress)
+
+	hasRun     bool
+	iterations int
+	converged  bool
+	means      []float64
+	scales     []float64
+	offset     float64
+	x          [][]float64
+	chol       mat.Cholesky
+	alpha      *mat.VecDense
+	logLik     float64
+}
+
+// SetLengthScale sets the length scale of the kernel, in standard deviations of the variables. It is 1 by default.
+func (g *GaussianProcess) SetLengthScale(l float64) {
+	g.lengthScale = l
+}
+
+// SetSignalVariance sets the variance of the process about the mean. It is the variance of the observed values by default.
+func (g *GaussianProcess) SetSignalVariance(v float64) {
+	g.signalVariance = v
+}
+
+// SetNoiseVariance sets the variance of the noise in the observed values. It is a tenth of the
+// variance of the observed values by default.
+func (g *GaussianProcess) SetNoiseVariance(v float64) {
+	g.noiseVariance = v
+}
+
+// SetOptimize sets whether Run chooses the length scale, signal and noise variance by maximizing the
+// log marginal likelihood, starting from the values set or their defaults.
+func (g *GaussianProcess) SetOptimize(enabled bool) {
+	g.optimize = enabled
+}
+
+// SetConvergence sets when the search for the hyperparameters enabled by SetOptimize stops.
+func (g *GaussianProcess) SetConvergence(c Convergence) {
+	g.convergence = c
+}
+
+// Iterations returns the number of iterations of the hyperparameter search, or 0 if it was not enabled.
+func (g *GaussianProcess) Iterations() int {
+	return g.iterations
+}
+
+// Converged reports whether the hyperparameter search converged, or true if it was not enabled. A search
+// that stops at its iteration limit keeps the best hyperparameters found.
+func (g *GaussianProcess) Converged() bool {
+	return g.hasRun && g.converged
+}
+
+// LengthScale returns the length scale of the kernel, as chosen by Run.
+func (g *GaussianProcess) LengthScale() float64 {
+	return g.lengthScale
+}
+
+// SignalVariance returns the variance of the process, as chosen by Run.
+func (g *GaussianProcess) SignalVariance() float64 {
+	return g.signalVariance
+}
+
+// NoiseVariance returns the variance of the noise, as chosen by Run.
+func (g *GaussianProcess) NoiseVariance() float64 {
+	return g.noiseVariance
+}
+
+// LogMarginalLikelihood returns the log marginal likelihood of the training data under the fitted process.
+func (g *GaussianProcess) LogMarginalLikelihood() float64 {
+	return g.logLik
+}
+
+// Train adds data points to the training set.
+func (g *GaussianProcess) Train(d ...*dataPoint) {
+	g.Data = append(g.Data, d...)
+}
+
+// Run fits the process to the training data.
+func (g *GaussianProcess) Run() (err error) {
+	defer func(start time.Time) {
+		instrumentFit("gaussian process", start, err)
+	}(time.Now())
+	if g.hasRun {
+		return ErrRegressionRun
+	}
+	n := len(g.Data)
+	if n < 3 {
+		return fmt.Errorf("%w: %d data points trained, need at least 3", ErrNotEnoughData, n)
+	}
+	k := len(g.Data[0].Variables)
+	for i, d := range g.Data {
+		if len(d.Variables) != k {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: has %d, expected %d as in data point 0", ErrVariableCount, len(d.Variables), k)}
+		}
+		if !d.finite() {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: NaN or infinite value", ErrDesign)}
+		}
+	}
+
+	// Standardize the variables and center the observed values
+	g.means, g.scales = make([]float64, k), make([]float64, k)
+	col := make([]float64, n)
+	for j := 0; j < k; j++ {
+		for i, d := range g.Data {
+			col[i] = d.Variables[j]
+		}
+		g.means[j], g.scales[j] = stat.MeanStdDev(col, nil)
+		if g.scales[j] == 0 {
+			g.scales[j] = 1
+		}
+	}
+	g.x = make([][]float64, n)
+	y := make([]float64, n)
+	for i, d := range g.Data {
+		g.x[i] = g.standardize(d.Variables)
+		y[i] = d.Observed
+	}
+	var variance float64
+	g.offset, variance = stat.MeanVariance(y, nil)
+	if variance == 0 {
+		variance = 1
+	}
+	for i := range y {
+		y[i] -= g.offset
+	}
+	if g.lengthScale <= 0 {
+		g.lengthScale = 1
+	}
+	if g.signalVariance <= 0 {
+		g.signalVariance = variance
+	}
+	if g.noiseVariance <= 0 {
+		g.noiseVariance = variance / 10
+	}
+
+	g.iterations, g.converged = 0, true
+	if g.optimize {
+		// Minimize the negative log marginal likelihood over the log hyperparameters
+		problem := optimize.Problem{Func: func(theta []float64) float64 {
+			var chol mat.Cholesky
+			_, logLik, ok := g.factorize(&chol, y, math.Exp(theta[0]), math.Exp(theta[1]), math.Exp(theta[2]))
+			if !ok {
+				return math.Inf(1)
+			}
+			return -logLik
+		}}
+		start := []float64{math.Log(g.lengthScale), math.Log(g.signalVariance), math.Log(g.noiseVariance)}
+		result, err := optimize.Minimize(problem, start, g.convergence.settings(g.progress, n), &optimize.NelderMead{SimplexSize: g.convergence.StepSize})
+		if err != nil && result == nil {
+			return err
+		}
+		g.iterations, g.converged = result.Stats.MajorIterations, converged(result.Status)
+		g.lengthScale = math.Exp(result.X[0])
+		g.signalVariance = math.Exp(result.X[1])
+		g.noiseVariance = math.Exp(result.X[2])
+	}
+
+	alpha, logLik, ok := g.factorize(&g.chol, y, g.lengthScale, g.signalVariance, g.noiseVariance)
+	if !ok {
+		return fmt.Errorf("%w: kernel matrix is not positive definite", ErrSingular)
+	}
+	g.alpha, g.logLik = alpha, logLik
+	g.hasRun = true
+	for _, d := range g.Data {
+		d.Predicted, _ = g.Predict(d.Variables)
+		d.Error = d.Predicted - d.Observed
+	}
+	return nil
+}
+
+// factorize computes the Cholesky factorization of the kernel matrix plus noise, returning K⁻¹y and
+// the log marginal likelihood of y.
+func (g *GaussianProcess) factorize(chol *mat.Cholesky, y []float64, lengthScale, signal, noise float64) (*mat.VecDense, float64, bool) {
+	n := len(g.x)
+	kernel := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			v := g.kernel(g.x[i], g.x[j], lengthScale, signal)
+			if i == j {
+				v += noise
+			}
+			kernel.SetSym(i, j, v)
+		}
+	}
+	if ok := chol.Factorize(kernel); !ok {
+		return nil, 0, false
+	}
+	yv := mat.NewVecDense(n, y)
+	alpha := new(mat.VecDense)
+	if err := chol.SolveVecTo(alpha, yv); err != nil {
+		return nil, 0, false
+	}
+	logLik := -0.5*mat.Dot(yv, alpha) - 0.5*chol.LogDet() - float64(n)/2*math.Log(2*math.Pi)
+	return alpha, logLik, true
+}
+
+// kernel is the squared exponential covariance of two standardized points.
+func (g *GaussianProcess) kernel(a, b []float64, lengthScale, signal float64) float64 {
+	var d2 float64
+	for j := range a {
+		d2 += (a[j] - b[j]) * (a[j] - b[j])
+	}
+	return signal * math.Exp(-d2/(2*lengthScale*lengthScale))
+}
+
+func (g *GaussianProcess) standardize(vars []float64) []float64 {
+	z := make([]float64, len(vars))
+	for j, v := range vars {
+		z[j] = (v - g.means[j]) / g.scales[j]
+	}
+	return z
+}
+
+// Predict returns the posterior mean of the observed value for vars.
+func (g *GaussianProcess) Predict(vars []float64) (float64, error) {
+	mean, _, err := g.PredictStdDev(vars)
+	return mean, err
+}
+
+// PredictStdDev returns the posterior mean of the observed value for vars along with the standard
+// deviation of a new observation, including the noise.
+func (g *GaussianProcess) PredictStdDev(vars []float64) (mean, stdDev float64, err error) {
+	if !g.hasRun {
+		return 0, 0, ErrRegressionNotRun
+	}
+	if len(vars) != len(g.means) {
+		return 0, 0, fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), len(g.means))
+	}
+	z := g.standardize(vars)
+	kstar := mat.NewVecDense(len(g.x), nil)
+	for i, x := range g.x {
+		kstar.SetVec(i, g.kernel(z, x, g.lengthScale, g.signalVariance))
+	}
+	mean = g.offset + mat.Dot(kstar, g.alpha)
+
+	var v mat.VecDense
+	if err := g.chol.SolveVecTo(&v, kstar); err != nil {
+		return 0, 0, err
+	}
+	variance := g.signalVariance - mat.Dot(kstar, &v) + g.noiseVariance
+	return mean, math.Sqrt(math.Max(variance, 0)), nil
+}
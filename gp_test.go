@@ -0,0 +1,67 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestGaussianProcess(t *testing.T) {
+	var rows [][]float64
+	for i := 0; i < 30; i++ {
+		x := float64(i) / 5
+		rows = append(rows, []float64{math.Sin(x) + 0.05*math.Cos(float64(i*7)), x})
+	}
+
+	g := new(GaussianProcess)
+	if _, err := g.Predict([]float64{1}); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	g.SetNoiseVariance(0.01)
+	g.Train(MakeDataPoints(rows, 0)...)
+	if err := g.Run(); err != nil {
+		t.Fatal(err)
+	}
+	before := g.LogMarginalLikelihood()
+
+	// A GP follows the curve where a straight line cannot
+	var gpErr, linErr float64
+	r := new(Regression)
+	r.Train(MakeDataPoints(rows, 0)...)
+	r.Run()
+	for x := 0.1; x < 5.8; x += 0.4 {
+		p, sd, err := g.PredictStdDev([]float64{x})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sd <= 0 || sd > 0.5 {
+			t.Errorf("Expected a small positive standard deviation at %v, got %v", x, sd)
+		}
+		l, _ := r.Predict([]float64{x})
+		gpErr += math.Abs(p - math.Sin(x))
+		linErr += math.Abs(l - math.Sin(x))
+	}
+	if gpErr > linErr/5 {
+		t.Errorf("Expected the GP to fit much better than a line, got errors %v and %v", gpErr, linErr)
+	}
+
+	// Optimizing the hyperparameters increases the marginal likelihood
+	o := new(GaussianProcess)
+	o.SetNoiseVariance(0.01)
+	o.SetOptimize(true)
+	o.Train(MakeDataPoints(rows, 0)...)
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if o.LogMarginalLikelihood() < before {
+		t.Errorf("Expected optimization to increase the log marginal likelihood from %v, got %v", before, o.LogMarginalLikelihood())
+	}
+	if o.NoiseVariance() > 0.01 {
+		t.Errorf("Expected a noise variance below 0.01, got %v", o.NoiseVariance())
+	}
+
+	var _ Predictor = o
+	if _, err := o.Predict([]float64{1, 2}); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// GridPoint is the cross-validated error of one combination of parameters in a grid search.
+type GridPoint struct {
+	Params map[string]float64
+	// RMSE is the root mean squared error over all the held-out folds.
+	RMSE float64
+	// Err is set if a model could not be fitted with these parameters, in which case RMSE is NaN.
+	Err error
+}
+
+// GridSearchResult holds the best parameters found by GridSearch along with every combination tried.
+type GridSearchResult struct {
+	Best     map[string]float64
+	BestRMSE float64
+	Results  []GridPoint
+}
+
+// GridSearch scores every combination of the parameter values in grid by k-fold cross-validation. For each
+// combination and fold, build is called to create an unfitted regression configured from the parameters,
+// such as a solver penalty, a robust tuning constant or a polynomial degree via feature crosses, which is
+// trained on copies of the data outside the fold and evaluated on the fold. The folds are chosen at random,
+// the same for every combination, and the fits run in parallel. Combinations are tried in order of the
+// sorted parameter names, varying the last name fastest.
+func GridSearch(data DataPoints, grid map[string][]float64, build func(params map[string]float64) *Regression, k int, opts ...Option) (*GridSearchResult, error) {
+	o := newOptions(opts)
+	if k < 2 || k > len(data) {
+		return nil, fmt.Errorf("%w: %d folds for %d data points", ErrNotEnoughData, k, len(data))
+	}
+	names := make([]string, 0, len(grid))
+	for name := range grid {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]float64{{}}
+	for _, name := range names {
+		var next []map[string]float64
+		for _, c := range combinations {
+			for _, v := range grid[name] {
+				params := make(map[string]float64, len(c)+1)
+				for n, x := range c {
+					params[n] = x
+				}
+				params[name] = v
+				next = append(next, params)
+			}
+		}
+		combinations = next
+	}
+
+	folds := folds(len(data), k, o.rand)
+	sse := make([]float64, len(combinations)*k)
+	errs := make([]error, len(combinations)*k)
+	parallelFor(o.concurrency, len(sse), o.track("grid search", len(sse), func(job int) {
+		params, fold := combinations[job/k], job%k
+		var train, holdout DataPoints
+		for f, rows := range folds {
+			for _, i := range rows {
+				if f == fold {
+					holdout = append(holdout, data[i])
+				} else {
+					train = append(train, data[i])
+				}
+			}
+		}
+		fit := build(params)
+		if errs[job] = fit.fitCopies(train); errs[job] != nil {
+			return
+		}
+		m, err := fit.Evaluate(holdout)
+		sse[job], errs[job] = m.RMSE*m.RMSE*float64(m.N), err
+	}))
+
+	result := &GridSearchResult{BestRMSE: math.Inf(1), Results: make([]GridPoint, len(combinations))}
+	for c, params := range combinations {
+		point := GridPoint{Params: params}
+		var total float64
+		for fold := 0; fold < k; fold++ {
+			total += sse[c*k+fold]
+			if err := errs[c*k+fold]; err != nil && point.Err == nil {
+				point.Err = err
+			}
+		}
+		point.RMSE = math.Sqrt(total / float64(len(data)))
+		if point.Err != nil {
+			point.RMSE = math.NaN()
+		} else if point.RMSE < result.BestRMSE {
+			result.Best, result.BestRMSE = params, point.RMSE
+		}
+		result.Results[c] = point
+	}
+	if result.Best == nil {
+		return nil, fmt.Errorf("no combination of parameters could be fitted: %w", result.Results[0].Err)
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestGridSearch(t *testing.T) {
+	var data DataPoints
+	for i := 0; i < 50; i++ {
+		x := float64(i%25) / 5
+		data = append(data, DataPoint(1+2*x-0.8*x*x+0.3*math.Sin(float64(i*i)), []float64{x}))
+	}
+	build := func(params map[string]float64) *Regression {
+		r := new(Regression)
+		for d := 2; d <= int(params["degree"]); d++ {
+			r.AddCross(PowCross(0, float64(d)))
+		}
+		r.SetCollinearityTolerance(params["tol"])
+		return r
+	}
+
+	result, err := GridSearch(data, map[string][]float64{"degree": {1, 2, 3}, "tol": {0, 1e-12}}, build, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Results) != 6 {
+		t.Fatalf("Expected 6 combinations, got %d", len(result.Results))
+	}
+	if p := result.Results[1].Params; p["degree"] != 1 || p["tol"] != 1e-12 {
+		t.Errorf("Expected the second combination to vary tol fastest, got %v", p)
+	}
+	if result.Best["degree"] == 1 {
+		t.Errorf("Expected a quadratic or higher degree to fit best, got %v", result.Best)
+	}
+	if result.Results[0].RMSE <= result.BestRMSE {
+		t.Errorf("Expected the linear model to score worse than the best, got %+v", result.Results)
+	}
+	if len(data[0].Variables) != 1 {
+		t.Errorf("Expected the data not to be changed by feature crosses")
+	}
+
+	_, err = GridSearch(data[:2], map[string][]float64{"degree": {1}}, build, 2)
+	if !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package regression
+
+import (
+	"sort"
+)
+
+// GroupSummary is a row of the comparison table returned by FitByGroup.
+type GroupSummary struct {
+	Group  string
+	N      int
+	R2     float64
+	Coeffs []float64
+	// Err is set if the group's model could not be fitted.
+	Err error
+}
+
+// FitByGroup fits an independent regression to each group of data points, as determined by groupKey.
+// The groups are fitted in parallel. It returns the fitted models by group along with a comparison
+// table sorted by group. Groups that fail to fit are reported in the table but left out of the map.
+func FitByGroup(data DataPoints, groupKey func(*dataPoint) string, opts ...Option) (map[string]*Regression, []GroupSummary) {
+	o := newOptions(opts)
+	groups := make(map[string][]*dataPoint)
+	for _, d := range data {
+		key := groupKey(d)
+		groups[key] = append(groups[key], d)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	models := make([]*Regression, len(keys))
+	summaries := make([]GroupSummary, len(keys))
+	parallelFor(o.concurrency, len(keys), o.track("fit by group", len(keys), func(i int) {
+		r := new(Regression)
+		r.Train(groups[keys[i]]...)
+		err := r.Run()
+		models[i] = r
+		summaries[i] = GroupSummary{Group: keys[i], N: len(groups[keys[i]]), R2: r.R2, Coeffs: r.GetCoeffs(), Err: err}
+	}))
+
+	fitted := make(map[string]*Regression, len(keys))
+	for i, key := range keys {
+		if summaries[i].Err == nil {
+			fitted[key] = models[i]
+		}
+	}
+	return fitted, summaries
+}
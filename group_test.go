@@ -0,0 +1,52 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFitByGroup(t *testing.T) {
+	var data DataPoints
+	for i := 0; i < 20; i++ {
+		x := float64(i)
+		data = append(data,
+			DataPoint(1+2*x, []float64{x}),
+			DataPoint(5-x, []float64{x}),
+		)
+	}
+	data = append(data, DataPoint(7.5, []float64{1}))
+
+	models, table := FitByGroup(data, func(d *dataPoint) string {
+		switch {
+		case d.Observed == 7.5:
+			return "c"
+		case d.Observed == 1+2*d.Variables[0]:
+			return "a"
+		default:
+			return "b"
+		}
+	})
+
+	if len(table) != 3 || table[0].Group != "a" || table[1].Group != "b" || table[2].Group != "c" {
+		t.Fatalf("Expected a table sorted by group, got %v", table)
+	}
+	if !errors.Is(table[2].Err, ErrNotEnoughData) {
+		t.Errorf("Expected group c to fail with ErrNotEnoughData, got %v", table[2].Err)
+	}
+	if len(models) != 2 {
+		t.Errorf("Expected 2 fitted models, got %v", len(models))
+	}
+
+	expected := map[string][]float64{"a": {1, 2}, "b": {5, -1}}
+	for i, row := range table[:2] {
+		if row.N != 20 {
+			t.Errorf("Expected 20 points in group %v, got %v", row.Group, row.N)
+		}
+		for j, c := range expected[row.Group] {
+			if math.Abs(row.Coeffs[j]-c) > 1e-9 || math.Abs(models[row.Group].Coeff(j)-c) > 1e-9 {
+				t.Errorf("Expected coefficient %v of group %v to be %v, got %v", j, row.Group, c, table[i].Coeffs[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// WaldTest is the result of a Wald test of linear restrictions on the coefficients.
+type WaldTest struct {
+	// Chi2 is the Wald statistic, asymptotically χ² with DF1 degrees of freedom, and ChiSquaredP its p-value.
+	Chi2        float64
+	ChiSquaredP float64
+	// F is the Wald statistic divided by the number of restrictions, which follows an F distribution
+	// with DF1 and DF2 degrees of freedom under normal errors, and P its p-value.
+	F   float64
+	DF1 int
+	DF2 int
+	P   float64
+}
+
+// LinearHypothesis tests the restrictions Rβ = q on the coefficients β using the coefficient covariance.
+// Each row of restrictions has an element for each coefficient, starting with the offset, and q has an
+// element for each row. For example the row {0, 1, -1} with q = {0} tests whether the coefficients of
+// variables 0 and 1 are equal, and {0, 1, 0, 1} with q = {0} whether those of variables 0 and 2 sum to zero.
+func (r *Regression) LinearHypothesis(restrictions [][]float64, q []float64) (*WaldTest, error) {
+	if !r.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	if r.cov == nil {
+		return nil, fmt.Errorf("%w: the coefficient covariance is unavailable", ErrSingular)
+	}
+	k := len(r.coeff)
+	m := len(restrictions)
+	if m == 0 || len(q) != m {
+		return nil, fmt.Errorf("%w: %d restrictions with %d values", ErrHypothesis, m, len(q))
+	}
+	rm := mat.NewDense(m, k, nil)
+	for i, row := range restrictions {
+		if len(row) != k {
+			return nil, fmt.Errorf("%w: restriction %d has %d elements, expected %d", ErrHypothesis, i, len(row), k)
+		}
+		rm.SetRow(i, row)
+	}
+
+	// Discrepancy Rβ - q and its covariance RVRᵀ
+	d := mat.NewVecDense(m, nil)
+	d.MulVec(rm, mat.NewVecDense(k, r.GetCoeffs()))
+	d.SubVec(d, mat.NewVecDense(m, q))
+	var rv, v mat.Dense
+	rv.Mul(rm, r.cov)
+	v.Mul(&rv, rm.T())
+
+	var x mat.VecDense
+	if err := x.SolveVec(&v, d); err != nil {
+		return nil, fmt.Errorf("%w: the restrictions are not linearly independent or involve dropped variables", ErrHypothesis)
+	}
+	w := mat.Dot(d, &x)
+	if math.IsNaN(w) {
+		return nil, fmt.Errorf("%w: the restrictions involve dropped variables", ErrHypothesis)
+	}
+
+	test := &WaldTest{Chi2: w, F: w / float64(m), DF1: m, DF2: r.residualDF}
+	test.ChiSquaredP = distuv.ChiSquared{K: float64(m)}.Survival(w)
+	test.P = distuv.F{D1: float64(m), D2: float64(r.residualDF)}.Survival(test.F)
+	return test, nil
+}
+
+// LackOfFitTest is the result of a lack of fit F test, which splits the residual sum of squares into
+// pure error, the scatter of replicated observations about their mean, and lack of fit, the scatter of
+// those means about the fitted model.
+type LackOfFitTest struct {
+	// Groups is the number of distinct points in the variables.
+	Groups      int
+	PureErrorSS float64
+	PureErrorDF int
+	LackOfFitSS float64
+	LackOfFitDF int
+	// F is the ratio of the lack of fit to the pure error mean square, and P its p-value. A small P
+	// suggests the model misses structure in the mean, such as curvature or an interaction.
+	F float64
+	P float64
+}
+
+// LackOfFit tests whether the model fits the means of replicated data points, those with identical
+// variables, as is usual in designed experiments. Without replicates there is no estimate of pure
+// error and ErrNotEnoughData is returned; a model with as many coefficients as distinct points has no
+// degrees of freedom for lack of fit, and replicates that agree exactly have no pure error, so
+// ErrHypothesis is returned. Fits with fixed effects or instruments return ErrIncompatibleOptions.
+func (r *Regression) LackOfFit() (*LackOfFitTest, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	switch {
+	case r.maximumLikelihood():
+		return nil, fmt.Errorf("%w: censored or truncated data have no pure error sum of squares", ErrIncompatibleOptions)
+	case r.fixedEffects:
+		return nil, fmt.Errorf("%w: the residuals of a fixed effects fit are not comparable with the pure error of replicates", ErrIncompatibleOptions)
+	case len(r.endogenous) > 0 || len(r.instruments) > 0:
+		return nil, fmt.Errorf("%w: the residuals of an instrumented fit are not least squares residuals", ErrIncompatibleOptions)
+	}
+	groups := make(map[string][]float64)
+	for _, d := range r.Data {
+		key := fmt.Sprint(d.Variables)
+		groups[key] = append(groups[key], d.Observed)
+	}
+	test := &LackOfFitTest{Groups: len(groups), PureErrorDF: len(r.Data) - len(groups)}
+	if test.PureErrorDF == 0 {
+		return nil, fmt.Errorf("%w: no replicated data points to estimate pure error", ErrNotEnoughData)
+	}
+	test.LackOfFitDF = r.residualDF - test.PureErrorDF
+	if test.LackOfFitDF <= 0 {
+		return nil, fmt.Errorf("%w: %d coefficients leave no degrees of freedom for lack of fit at %d distinct points", ErrHypothesis, len(r.Data)-r.residualDF, len(groups))
+	}
+	for _, ys := range groups {
+		var mean float64
+		for _, y := range ys {
+			mean += y / float64(len(ys))
+		}
+		for _, y := range ys {
+			test.PureErrorSS += (y - mean) * (y - mean)
+		}
+	}
+	if test.PureErrorSS == 0 {
+		return nil, fmt.Errorf("%w: the replicated data points agree exactly, leaving no pure error to test against", ErrHypothesis)
+	}
+	test.LackOfFitSS = r.residualSS - test.PureErrorSS
+	test.F = (test.LackOfFitSS / float64(test.LackOfFitDF)) / (test.PureErrorSS / float64(test.PureErrorDF))
+	test.P = distuv.F{D1: float64(test.LackOfFitDF), D2: float64(test.PureErrorDF)}.Survival(test.F)
+	return test, nil
+}
@@ -0,0 +1,130 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLinearHypothesis(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if _, err := r.LinearHypothesis([][]float64{{0, 1}}, []float64{0}); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Run()
+
+	// A single restriction that a coefficient is zero gives the square of its t statistic
+	test, err := r.LinearHypothesis([][]float64{{0, 1}}, []float64{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tValue := r.Coeff(1) / r.StdErr(1)
+	if math.Abs(test.F-tValue*tValue) > 1e-9 || test.DF1 != 1 || test.DF2 != 9 {
+		t.Errorf("Expected F = t² = %v on 1 and 9 degrees of freedom, got %+v", tValue*tValue, test)
+	}
+	if math.Abs(test.P-0.00217) > 1e-5 {
+		t.Errorf("Expected p-value 0.00217, got %v", test.P)
+	}
+
+	if _, err := r.LinearHypothesis([][]float64{{0, 1, 0}}, []float64{0}); !errors.Is(err, ErrHypothesis) {
+		t.Errorf("Expected ErrHypothesis, got %v", err)
+	}
+	if _, err := r.LinearHypothesis([][]float64{{0, 1}, {0, 2}}, []float64{0, 0}); !errors.Is(err, ErrHypothesis) {
+		t.Errorf("Expected ErrHypothesis for dependent restrictions, got %v", err)
+	}
+}
+
+func TestLinearHypothesisEqualCoeffs(t *testing.T) {
+	// For least squares the Wald F test of β1 = β2 equals the F test comparing against the
+	// restricted model, fitted on x1 + x2
+	r := new(Regression)
+	restricted := new(Regression)
+	for i := 0; i < 30; i++ {
+		x1, x2 := float64(i%7), math.Sin(float64(i))*3
+		y := 1 + 2*x1 + 2.5*x2 + math.Cos(float64(i*i))
+		r.Train(DataPoint(y, []float64{x1, x2}))
+		restricted.Train(DataPoint(y, []float64{x1 + x2}))
+	}
+	r.Run()
+	restricted.Run()
+
+	test, err := r.LinearHypothesis([][]float64{{0, 1, -1}}, []float64{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sse := func(r *Regression) float64 {
+		var s float64
+		for _, d := range r.Data {
+			s += d.Error * d.Error
+		}
+		return s
+	}
+	f := (sse(restricted) - sse(r)) / (sse(r) / 27)
+	if math.Abs(test.F-f) > 1e-8*f {
+		t.Errorf("Expected F %v, got %v", f, test.F)
+	}
+}
+
+func TestLackOfFit(t *testing.T) {
+	// Duplicate runs at five levels of a quadratic response, with replicate differences of ±0.1
+	fit := func(f func(x float64) float64) (*LackOfFitTest, *Regression) {
+		r := new(Regression)
+		for x := 1.0; x <= 5; x++ {
+			r.Train(DataPoint(f(x)+0.1, []float64{x}), DataPoint(f(x)-0.1, []float64{x}))
+		}
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		test, err := r.LackOfFit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return test, r
+	}
+	curved, r := fit(func(x float64) float64 { return x * x })
+	if curved.Groups != 5 || curved.PureErrorDF != 5 || curved.LackOfFitDF != 3 {
+		t.Errorf("Expected 5 groups with 5 and 3 degrees of freedom, got %+v", curved)
+	}
+	if math.Abs(curved.PureErrorSS-0.1) > 1e-12 || math.Abs(curved.PureErrorSS+curved.LackOfFitSS-r.ResidualSS()) > 1e-9 {
+		t.Errorf("Expected pure error 0.1 and the sums of squares to add to %v, got %+v", r.ResidualSS(), curved)
+	}
+	if curved.P > 1e-4 {
+		t.Errorf("Expected significant lack of fit of a line to a quadratic, got p = %v", curved.P)
+	}
+	if straight, _ := fit(func(x float64) float64 { return 2 * x }); straight.LackOfFitSS > 1e-9 || straight.P < 0.99 {
+		t.Errorf("Expected no lack of fit of a line to a line, got %+v", straight)
+	}
+
+	unreplicated := new(Regression)
+	unreplicated.Train(MakeDataPoints(anscombe, 0)...)
+	if err := unreplicated.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unreplicated.LackOfFit(); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData without replicates, got %v", err)
+	}
+
+	exact := new(Regression)
+	for x := 1.0; x <= 5; x++ {
+		exact.Train(DataPoint(x*x, []float64{x}), DataPoint(x*x, []float64{x}))
+	}
+	if err := exact.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exact.LackOfFit(); !errors.Is(err, ErrHypothesis) {
+		t.Errorf("Expected ErrHypothesis for replicates without pure error, got %v", err)
+	}
+
+	panel := new(Regression)
+	panel.SetFixedEffects(true)
+	for x := 1.0; x <= 5; x++ {
+		panel.Train(PanelDataPoint("a", x+0.1, []float64{x}), PanelDataPoint("b", x+2, []float64{x}))
+	}
+	if err := panel.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := panel.LackOfFit(); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions for fixed effects, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// StandardizedCoeffs returns the coefficients rescaled to standard deviation units, that is the change
+// in standard deviations of the observed value per standard deviation of each variable. This allows the
+// relative importance of variables measured on different scales to be compared. The element at index 0
+// is for the offset, which is always zero once standardized.
+func (r *Regression) StandardizedCoeffs() []float64 {
+	if r.requireData() != nil {
+		return nil
+	}
+	observed, variables := r.designMatrix()
+	_, cols := variables.Dims()
+	sy := stat.StdDev(mat.Col(nil, 0, observed), nil)
+
+	coeffs := make([]float64, cols)
+	for j := 1; j < cols; j++ {
+		coeffs[j] = r.Coeff(j) * stat.StdDev(mat.Col(nil, j, variables), nil) / sy
+	}
+	return coeffs
+}
+
+// ImportanceCriterion selects how variables are ranked by Importance.
+type ImportanceCriterion int
+
+const (
+	// ByTValue ranks variables by the absolute t statistic of their coefficient.
+	ByTValue ImportanceCriterion = iota
+	// ByStandardizedCoeff ranks variables by the absolute value of their standardized coefficient.
+	ByStandardizedCoeff
+	// ByDropOneR2 ranks variables by how much R² falls when the model is refitted without them.
+	ByDropOneR2
+	// ByLMG ranks variables by their share of R² averaged over all orderings, as in RelativeImportance.
+	ByLMG
+)
+
+// VariableImportance is the importance score of a single variable.
+type VariableImportance struct {
+	Index int
+	Name  string
+	Score float64
+}
+
+// Importance ranks the variables of a fitted model by the chosen criterion, most important first.
+// Refits for ByDropOneR2 use ordinary least squares.
+func (r *Regression) Importance(criterion ImportanceCriterion) ([]VariableImportance, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	cols := len(r.coeff)
+	scores := make([]float64, cols)
+	switch criterion {
+	case ByTValue:
+		for j := 1; j < cols; j++ {
+			scores[j] = math.Abs(r.Coeff(j) / r.StdErr(j))
+		}
+	case ByStandardizedCoeff:
+		for j, c := range r.StandardizedCoeffs() {
+			scores[j] = math.Abs(c)
+		}
+	case ByDropOneR2:
+		loco, err := r.LOCO()
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range loco {
+			scores[l.Index+1] = l.DeltaR2
+		}
+	case ByLMG:
+		shares, err := r.RelativeImportance()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range shares {
+			scores[s.Index+1] = s.LMG
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrCriterion, criterion)
+	}
+
+	ranking := make([]VariableImportance, 0, cols-1)
+	for j := 1; j < cols; j++ {
+		ranking = append(ranking, VariableImportance{Index: j - 1, Name: r.GetVar(j - 1), Score: scores[j]})
+	}
+	sort.SliceStable(ranking, func(a, b int) bool {
+		return ranking[a].Score > ranking[b].Score
+	})
+	return ranking, nil
+}
+
+// LOCOResult is the leave-one-covariate-out importance of a single variable.
+type LOCOResult struct {
+	Index int
+	Name  string
+	// R2 and RMSE are for the model refitted without the variable.
+	R2   float64
+	RMSE float64
+	// DeltaR2 is the fall in R² and DeltaRMSE the rise in RMSE from leaving the variable out.
+	DeltaR2   float64
+	DeltaRMSE float64
+}
+
+// LOCO computes leave-one-covariate-out importance by refitting the model without each variable in turn,
+// in parallel, and comparing the in-sample R² and RMSE against the full model. Refits use ordinary least squares.
+// The results are in variable order.
+func (r *Regression) LOCO(opts ...Option) ([]LOCOResult, error) {
+	o := newOptions(opts)
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	observed, variables := r.designMatrix()
+	_, cols := variables.Dims()
+	fullR2, fullRMSE, err := goodnessOfFit(variables, observed)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]LOCOResult, cols-1)
+	errs := make([]error, cols-1)
+	parallelFor(o.concurrency, cols-1, o.track("LOCO", cols-1, func(i int) {
+		r2, rmse, err := goodnessOfFit(columns(variables, withoutColumn(cols, i+1)), observed)
+		results[i] = LOCOResult{
+			Index:     i,
+			Name:      r.GetVar(i),
+			R2:        r2,
+			RMSE:      rmse,
+			DeltaR2:   fullR2 - r2,
+			DeltaRMSE: rmse - fullRMSE,
+		}
+		errs[i] = err
+	}))
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// maxLMGVars is the largest number of variables for which RelativeImportance fits every subset.
+const maxLMGVars = 16
+
+// RelativeShare apportions the R² of a model to one of its variables.
+type RelativeShare struct {
+	Index int
+	Name  string
+	// LMG is the increase in R² from adding the variable averaged over every order in which the
+	// variables can be added, its Shapley value. The LMG shares of all the variables sum to R².
+	LMG float64
+	// Sequential is the increase in R² from adding the variable after those before it in the model,
+	// the sequential sum of squares as a fraction of the total.
+	Sequential float64
+}
+
+// RelativeImportance decomposes the R² of the model among its variables by the LMG method of
+// Lindeman, Merenda and Gold, which averages the increase in R² from each variable over all the
+// orders in which it could be added, so that correlated variables share the variance they explain.
+// The sequential increases in R² in the order of the variables are also reported. Every subset of the
+// variables is fitted by ordinary least squares, in parallel, so models with more than 16 variables
+// return ErrTooManyVars. The results are in variable order.
+func (r *Regression) RelativeImportance(opts ...Option) ([]RelativeShare, error) {
+	o := newOptions(opts)
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	observed, variables := r.designMatrix()
+	_, cols := variables.Dims()
+	p := cols - 1
+	if p > maxLMGVars {
+		return nil, fmt.Errorf("%w: relative importance fits every subset of at most %d variables, got %d", ErrTooManyVars, maxLMGVars, p)
+	}
+
+	// r2[s] is the R² of the model with the variables in the bitmask s
+	r2 := make([]float64, 1<<p)
+	errs := make([]error, 1<<p)
+	parallelFor(o.concurrency, len(r2)-1, o.track("relative importance", len(r2)-1, func(k int) {
+		s := k + 1
+		keep := []int{0}
+		for j := 0; j < p; j++ {
+			if s&(1<<j) != 0 {
+				keep = append(keep, j+1)
+			}
+		}
+		r2[s], _, errs[s] = goodnessOfFit(columns(variables, keep), observed)
+	}))
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A subset of size k without j precedes j in k!(p-k-1)! of the p! orderings
+	weights := make([]float64, p)
+	for k := range weights {
+		lk, _ := math.Lgamma(float64(k + 1))
+		lrest, _ := math.Lgamma(float64(p - k))
+		lp, _ := math.Lgamma(float64(p + 1))
+		weights[k] = math.Exp(lk + lrest - lp)
+	}
+	shares := make([]RelativeShare, p)
+	for j := range shares {
+		shares[j] = RelativeShare{Index: j, Name: r.GetVar(j)}
+		bit := 1 << j
+		for s := range r2 {
+			if s&bit == 0 {
+				shares[j].LMG += weights[bits.OnesCount(uint(s))] * (r2[s|bit] - r2[s])
+			}
+		}
+		shares[j].Sequential = r2[1<<(j+1)-1] - r2[1<<j-1]
+	}
+	return shares, nil
+}
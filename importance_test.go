@@ -0,0 +1,153 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestStandardizedCoeffs(t *testing.T) {
+	r := new(Regression)
+	if r.StandardizedCoeffs() != nil {
+		t.Error("Expected no standardized coefficients before running")
+	}
+	for i := 0; i < 20; i++ {
+		x1 := float64(i)
+		x2 := 1000 * math.Cos(float64(i))
+		r.Train(DataPoint(3+2*x1+0.01*x2, []float64{x1, x2}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	std := r.StandardizedCoeffs()
+	if len(std) != 3 || std[0] != 0 {
+		t.Fatalf("Expected 3 standardized coefficients with a zero offset, got %v", std)
+	}
+
+	// Rescaling a variable must not change its standardized coefficient
+	scaled := new(Regression)
+	for _, d := range r.Data {
+		scaled.Train(DataPoint(d.Observed, []float64{d.Variables[0], d.Variables[1] / 1000}))
+	}
+	if err := scaled.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range scaled.StandardizedCoeffs() {
+		if math.Abs(c-std[i]) > 1e-9 {
+			t.Errorf("Expected standardized coefficient %v to be %v, got %v", i, std[i], c)
+		}
+	}
+
+	// The first variable varies more relative to its effect than the second
+	if std[1] < std[2] {
+		t.Errorf("Expected the first variable to be more important, got %v", std)
+	}
+}
+
+func TestImportance(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "Weak")
+	r.SetVar(1, "Strong")
+	r.SetVar(2, "Noise")
+	for i := 0; i < 30; i++ {
+		x := []float64{math.Sin(float64(i)), 100 * math.Cos(float64(i)*0.7), math.Sin(float64(i) * 2.3)}
+		r.Train(DataPoint(1+0.5*x[0]+0.05*x[1]+0.01*math.Cos(float64(i)*5), x))
+	}
+	if _, err := r.Importance(ByTValue); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Importance(ImportanceCriterion(42)); !errors.Is(err, ErrCriterion) {
+		t.Errorf("Expected ErrCriterion, got %v", err)
+	}
+
+	for _, criterion := range []ImportanceCriterion{ByTValue, ByStandardizedCoeff, ByDropOneR2} {
+		ranking, err := r.Importance(criterion)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ranking) != 3 {
+			t.Fatalf("Expected 3 ranked variables, got %v", ranking)
+		}
+		if ranking[0].Name != "Strong" || ranking[1].Name != "Weak" || ranking[2].Name != "Noise" || ranking[2].Index != 2 {
+			t.Errorf("Expected Strong, Weak then Noise for criterion %v, got %v", criterion, ranking)
+		}
+	}
+}
+
+func TestLOCO(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "Signal")
+	for i := 0; i < 30; i++ {
+		x := []float64{float64(i), math.Sin(float64(i) * 2.3)}
+		r.Train(DataPoint(1+2*x[0]+0.1*math.Cos(float64(i)*5), x))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	loco, err := r.LOCO()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loco) != 2 || loco[0].Name != "Signal" || loco[1].Index != 1 {
+		t.Fatalf("Expected results for both variables in order, got %v", loco)
+	}
+	if loco[0].DeltaR2 < 0.9 || loco[0].DeltaRMSE < 10 {
+		t.Errorf("Expected dropping the signal to hurt the fit, got %+v", loco[0])
+	}
+	if loco[1].DeltaR2 < 0 || loco[1].DeltaR2 > 0.001 || loco[1].DeltaRMSE < 0 {
+		t.Errorf("Expected dropping the noise to barely change the fit, got %+v", loco[1])
+	}
+	if math.Abs(loco[1].R2+loco[1].DeltaR2-r.R2) > 1e-9 {
+		t.Errorf("Expected the reduced and delta R² to add up to the full R² %v, got %+v", r.R2, loco[1])
+	}
+}
+
+func TestRelativeImportance(t *testing.T) {
+	r := new(Regression)
+	for i := 0; i < 30; i++ {
+		x1 := float64(i)
+		x2 := x1 + 5*math.Sin(float64(i))
+		x3 := math.Cos(float64(3 * i))
+		r.Train(DataPoint(1+x1+0.5*x2+2*x3+math.Sin(float64(7*i)), []float64{x1, x2, x3}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	shares, err := r.RelativeImportance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lmg, seq float64
+	for _, s := range shares {
+		lmg += s.LMG
+		seq += s.Sequential
+	}
+	if math.Abs(lmg-r.R2) > 1e-9 || math.Abs(seq-r.R2) > 1e-9 {
+		t.Errorf("Expected the LMG and sequential shares to sum to R² %v, got %v and %v", r.R2, lmg, seq)
+	}
+
+	// For the first variable, LMG averages its R² alone with its increase over the others
+	r2 := func(cols ...int) float64 {
+		observed, variables := r.designMatrix()
+		v, _, _ := goodnessOfFit(columns(variables, append([]int{0}, cols...)), observed)
+		return v
+	}
+	want := (r2(1)+r2(1, 2, 3)-r2(2, 3))/3 + (r2(1, 2)-r2(2)+r2(1, 3)-r2(3))/6
+	if math.Abs(shares[0].LMG-want) > 1e-9 {
+		t.Errorf("Expected LMG %v, got %v", want, shares[0].LMG)
+	}
+	if math.Abs(shares[0].Sequential-r2(1)) > 1e-9 {
+		t.Errorf("Expected a sequential share of %v, got %v", r2(1), shares[0].Sequential)
+	}
+
+	ranking, err := r.Importance(ByLMG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ranking[0].Score < ranking[1].Score {
+		t.Errorf("Expected the ranking to be ordered, got %v", ranking)
+	}
+}
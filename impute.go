@@ -0,0 +1,207 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// imputationCycles is the number of rounds of chained regressions run to draw each imputation.
+const imputationCycles = 10
+
+// ImputationFit is the fit of a model to several imputations of data with missing values, pooled by
+// Rubin's rules.
+type ImputationFit struct {
+	// Models holds the model fitted to each completed data set.
+	Models []*Regression
+	// Coeffs holds the mean of the coefficients over the imputations and StdErrs their standard
+	// errors, combining the variance within and between imputations, starting with the offset.
+	Coeffs  []float64
+	StdErrs []float64
+	// DF holds the degrees of freedom of the t reference distribution of each coefficient.
+	DF []float64
+	// MissingInfo holds the fraction of the variance of each coefficient due to the missing values.
+	MissingInfo []float64
+	// Imputed is the number of missing values imputed in each completed data set.
+	Imputed int
+}
+
+// MultipleImputation fits m models configured like r, each to a copy of its training data with the
+// missing values, NaN observed values or variables, imputed by chained regressions: each incomplete
+// column is regressed on the others, and its missing values drawn from the fitted model with
+// coefficients and errors drawn from their posterior, cycling over the columns until the imputations
+// settle. The coefficients of the fits are pooled by Rubin's rules. Since Run drops the data points
+// with missing values, call it on a regression that has been trained but not run. The imputations
+// are drawn in turn and the models fitted in parallel.
+func (r *Regression) MultipleImputation(m int, opts ...Option) (*ImputationFit, error) {
+	o := newOptions(opts)
+	points := r.rawData()
+	if m < 2 || len(points) < 3 {
+		return nil, fmt.Errorf("%w: %d imputations of %d data points", ErrNotEnoughData, m, len(points))
+	}
+
+	// The observed value is column 0 and variable j column j+1
+	n, cols := len(points), len(points[0].Variables)+1
+	data := mat.NewDense(n, cols, nil)
+	missing := make([][]int, cols)
+	for i, d := range points {
+		data.Set(i, 0, d.Observed)
+		for j, v := range d.Variables {
+			data.Set(i, j+1, v)
+		}
+		for j := 0; j < cols; j++ {
+			if math.IsNaN(data.At(i, j)) {
+				missing[j] = append(missing[j], i)
+			}
+		}
+	}
+	res := &ImputationFit{}
+	for j, rows := range missing {
+		if n-len(rows) <= cols {
+			return nil, fmt.Errorf("%w: column %d has %d missing values of %d, too many to impute from %d columns", ErrNotEnoughData, j, len(rows), n, cols)
+		}
+		res.Imputed += len(rows)
+	}
+
+	// Start from the column means, then refine the imputations by chained regressions, carrying each
+	// completed data set on to the next
+	for j, rows := range missing {
+		var sum float64
+		for i := 0; i < n; i++ {
+			if v := data.At(i, j); !math.IsNaN(v) {
+				sum += v
+			}
+		}
+		for _, i := range rows {
+			data.Set(i, j, sum/float64(n-len(rows)))
+		}
+	}
+	completed := make([]DataPoints, m)
+	for k := range completed {
+		for cycle := 0; cycle < imputationCycles; cycle++ {
+			for j, rows := range missing {
+				if len(rows) > 0 {
+					if err := imputeColumn(data, j, rows, o.rand); err != nil {
+						return nil, fmt.Errorf("imputing column %d: %w", j, err)
+					}
+				}
+			}
+		}
+		completed[k] = make(DataPoints, n)
+		for i, d := range points {
+			row := mat.Row(nil, i, data)
+			completed[k][i] = &dataPoint{Observed: row[0], Variables: row[1:], Entity: d.Entity, Label: d.Label}
+		}
+	}
+
+	res.Models = make([]*Regression, m)
+	errs := make([]error, m)
+	parallelFor(o.concurrency, m, o.track("multiple imputation", m, func(k int) {
+		res.Models[k], errs[k] = r.fitLike(completed[k])
+	}))
+	for k, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("imputation %d: %w", k, err)
+		}
+	}
+	res.pool()
+	return res, nil
+}
+
+// imputeColumn draws new values for the missing rows of column j from the regression of the column on
+// the others over the rows where it was observed. The coefficients and error variance are drawn from
+// their posterior under a flat prior, so the imputations reflect the uncertainty in the model.
+func imputeColumn(data *mat.Dense, j int, rows []int, rng *rand.Rand) error {
+	n, cols := data.Dims()
+	isMissing := make(map[int]bool, len(rows))
+	for _, i := range rows {
+		isMissing[i] = true
+	}
+	design := func(i int) []float64 {
+		x := []float64{1}
+		for c := 0; c < cols; c++ {
+			if c != j {
+				x = append(x, data.At(i, c))
+			}
+		}
+		return x
+	}
+	x := mat.NewDense(n-len(rows), cols, nil)
+	y := mat.NewDense(n-len(rows), 1, nil)
+	for i, k := 0, 0; i < n; i++ {
+		if !isMissing[i] {
+			x.SetRow(k, design(i))
+			y.Set(k, 0, data.At(i, j))
+			k++
+		}
+	}
+	b, err := leastSquares(x, y)
+	if err != nil {
+		return err
+	}
+	var chol mat.Cholesky
+	xtx := mat.NewSymDense(cols, nil)
+	xtx.SymOuterK(1, x.T())
+	if ok := chol.Factorize(xtx); !ok {
+		return ErrSingular
+	}
+
+	// σ² ~ SSE/χ²(ν) and b ~ N(b̂, σ²(XᵀX)⁻¹), drawn as b̂ + σU⁻¹z where XᵀX = UᵀU
+	var chiSquared float64
+	for k := 0; k < n-len(rows)-cols; k++ {
+		z := rng.NormFloat64()
+		chiSquared += z * z
+	}
+	sigma := math.Sqrt(sumOfSquaredResiduals(x, y, b) / chiSquared)
+	z := mat.NewVecDense(cols, nil)
+	for c := 0; c < cols; c++ {
+		z.SetVec(c, rng.NormFloat64())
+	}
+	var u mat.TriDense
+	chol.UTo(&u)
+	var draw mat.VecDense
+	if err := draw.SolveVec(&u, z); err != nil {
+		return err
+	}
+	for c := range b {
+		b[c] += sigma * draw.AtVec(c)
+	}
+	for _, i := range rows {
+		x := design(i)
+		var value float64
+		for c := range b {
+			value += x[c] * b[c]
+		}
+		data.Set(i, j, value+sigma*rng.NormFloat64())
+	}
+	return nil
+}
+
+// pool combines the coefficients of the models by Rubin's rules: the total variance of a coefficient
+// is T = Ū + (1 + 1/m)B, for the mean variance within imputations Ū and the variance between them B,
+// with the degrees of freedom ν = (m - 1)(1 + Ū/((1 + 1/m)B))².
+func (f *ImputationFit) pool() {
+	m := float64(len(f.Models))
+	k := len(f.Models[0].GetCoeffs())
+	f.Coeffs = make([]float64, k)
+	f.StdErrs = make([]float64, k)
+	f.DF = make([]float64, k)
+	f.MissingInfo = make([]float64, k)
+	for c := 0; c < k; c++ {
+		var mean, within, between float64
+		for _, model := range f.Models {
+			mean += model.Coeff(c) / m
+			within += model.StdErr(c) * model.StdErr(c) / m
+		}
+		for _, model := range f.Models {
+			between += (model.Coeff(c) - mean) * (model.Coeff(c) - mean) / (m - 1)
+		}
+		extra := (1 + 1/m) * between
+		f.Coeffs[c] = mean
+		f.StdErrs[c] = math.Sqrt(within + extra)
+		f.DF[c] = (m - 1) * (1 + within/extra) * (1 + within/extra)
+		f.MissingInfo[c] = extra / (within + extra)
+	}
+}
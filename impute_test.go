@@ -0,0 +1,62 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMultipleImputation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := new(Regression)
+	var missing int
+	for i := 0; i < 300; i++ {
+		x1 := rng.NormFloat64()
+		x2 := 0.8*x1 + 0.6*rng.NormFloat64()
+		y := 1 + 2*x1 - x2 + 0.5*rng.NormFloat64()
+		if rng.Float64() < 0.2 {
+			x1 = math.NaN()
+			missing++
+		}
+		r.Train(DataPoint(y, []float64{x1, x2}))
+	}
+
+	fit, err := r.MultipleImputation(10, WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fit.Imputed != missing {
+		t.Errorf("Expected %d imputed values, got %d", missing, fit.Imputed)
+	}
+	for i, want := range []float64{1, 2, -1} {
+		if math.Abs(fit.Coeffs[i]-want) > 3*fit.StdErrs[i] {
+			t.Errorf("Expected coefficient %d near %v, got %v ± %v", i, want, fit.Coeffs[i], fit.StdErrs[i])
+		}
+	}
+	for i, model := range fit.Models {
+		if len(model.Data) != 300 {
+			t.Errorf("Expected model %d to be fitted to all 300 data points, got %d", i, len(model.Data))
+		}
+	}
+	// The between imputation variance inflates the standard errors of the fits
+	if fit.StdErrs[1] <= fit.Models[0].StdErr(1) || fit.MissingInfo[1] <= 0 || fit.MissingInfo[1] >= 1 {
+		t.Errorf("Expected a pooled standard error above %v, got %v with missing information %v", fit.Models[0].StdErr(1), fit.StdErrs[1], fit.MissingInfo[1])
+	}
+	if !math.IsNaN(r.Data[firstMissing(r.Data)].Variables[0]) {
+		t.Errorf("Expected the training data to be unchanged")
+	}
+
+	if _, err := r.MultipleImputation(1); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData for one imputation, got %v", err)
+	}
+}
+
+func firstMissing(data DataPoints) int {
+	for i, d := range data {
+		if math.IsNaN(d.Variables[0]) {
+			return i
+		}
+	}
+	return -1
+}
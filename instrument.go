@@ -0,0 +1,90 @@
+package regression
+
+import (
+	"expvar"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Instrumentation receives events from fits and predictions, so that a service embedding the package
+// can export them as metrics, such as Prometheus counters and histograms, without the package depending
+// on a metrics library. Its methods are called synchronously and from many goroutines, so they must be
+// fast and safe for concurrent use.
+type Instrumentation interface {
+	// Fit is called as Run returns, with the kind of model fitted, "regression", "nonlinear" or
+	// "gaussian process", how long the fit took and its error, nil if it succeeded. The refits of
+	// resampling methods such as Bag and LearningCurve are not reported.
+	Fit(kind string, duration time.Duration, err error)
+	// Predict is called as a prediction of a Regression, from Predict or one of its variants such as
+	// PredictInterval, or a batch from PredictBatch returns, with the kind of model, the number of
+	// predictions requested and the error, nil if they all succeeded. The predictions made to evaluate
+	// a model, as by Evaluate, are not reported.
+	Predict(kind string, n int, err error)
+}
+
+// instrumentation holds the Instrumentation set with SetInstrumentation.
+var instrumentation atomic.Pointer[Instrumentation]
+
+// SetInstrumentation sends the events of every fit and prediction in the process to i, or stops
+// sending them if i is nil. It is usually called once, as a service starts.
+func SetInstrumentation(i Instrumentation) {
+	if i == nil {
+		instrumentation.Store(nil)
+		return
+	}
+	instrumentation.Store(&i)
+}
+
+// instrumentFit reports a fit that started at start and ended with err, if instrumentation is set.
+func instrumentFit(kind string, start time.Time, err error) {
+	if i := instrumentation.Load(); i != nil {
+		(*i).Fit(kind, time.Since(start), err)
+	}
+}
+
+// instrumentPredict reports n predictions that ended with err, if instrumentation is set.
+func instrumentPredict(kind string, n int, err error) {
+	if i := instrumentation.Load(); i != nil {
+		(*i).Predict(kind, n, err)
+	}
+}
+
+// fitDurationBuckets are the upper bounds, in seconds, of the buckets of the fit duration histogram
+// published by ExpvarInstrumentation.
+var fitDurationBuckets = []float64{0.001, 0.01, 0.1, 1, 10, 100}
+
+// expvarInstrumentation publishes events as expvar counters.
+type expvarInstrumentation struct {
+	vars *expvar.Map
+}
+
+// NewExpvarInstrumentation returns Instrumentation that publishes, as the expvar map name, counters of
+// fits, fit errors, predictions and prediction errors for each kind of model, and a cumulative
+// histogram of fit durations in seconds, as Prometheus buckets them. Services that expose /debug/vars
+// can pass it to SetInstrumentation. Like expvar.NewMap it panics if name is already published.
+func NewExpvarInstrumentation(name string) Instrumentation {
+	return expvarInstrumentation{vars: expvar.NewMap(name)}
+}
+
+func (e expvarInstrumentation) Fit(kind string, duration time.Duration, err error) {
+	e.vars.Add(kind+"_fits", 1)
+	if err != nil {
+		e.vars.Add(kind+"_fit_errors", 1)
+	}
+	seconds := duration.Seconds()
+	e.vars.AddFloat(kind+"_fit_seconds_sum", seconds)
+	for _, le := range fitDurationBuckets {
+		if seconds <= le {
+			e.vars.Add(kind+"_fit_seconds_bucket_le_"+strconv.FormatFloat(le, 'g', -1, 64), 1)
+		}
+	}
+	e.vars.Add(kind+"_fit_seconds_bucket_le_+Inf", 1)
+}
+
+func (e expvarInstrumentation) Predict(kind string, n int, err error) {
+	e.vars.Add(kind+"_predictions", int64(n))
+	if err != nil {
+		e.vars.Add(kind+"_prediction_errors", 1)
+	}
+}
@@ -0,0 +1,107 @@
+package regression
+
+import (
+	"errors"
+	"expvar"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingInstrumentation records the events it receives.
+type recordingInstrumentation struct {
+	mu          sync.Mutex
+	fits        map[string]int
+	fitErrors   int
+	predictions int
+	predictErrs int
+}
+
+func (r *recordingInstrumentation) Fit(kind string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fits[kind]++
+	if err != nil {
+		r.fitErrors++
+	}
+}
+
+func (r *recordingInstrumentation) Predict(kind string, n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.predictions += n
+	if err != nil {
+		r.predictErrs++
+	}
+}
+
+func TestInstrumentation(t *testing.T) {
+	rec := &recordingInstrumentation{fits: make(map[string]int)}
+	SetInstrumentation(rec)
+	defer SetInstrumentation(nil)
+
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Run(); !errors.Is(err, ErrRegressionRun) {
+		t.Errorf("Expected ErrRegressionRun, got %v", err)
+	}
+	if _, err := r.Predict([]float64{5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.PredictBatch([][]float64{{1}, {2}, {3}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Predict([]float64{1, 2}); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+	n := NewNonlinear(FourParameterLogistic())
+	n.Train(logisticData(0.02, rand.New(rand.NewSource(1)))...)
+	if err := n.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// Refits for resampling and the predictions of evaluation are internal, so they are not counted
+	if _, err := r.Bag(3, WithSeed(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.LearningCurve([]float64{0.5, 1}, 3, WithSeed(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Evaluate(r.Data); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fitting the regression predicts its training data without counting those predictions
+	if rec.fits["regression"] != 2 || rec.fits["nonlinear"] != 1 || rec.fitErrors != 1 {
+		t.Errorf("Expected 2 regression fits, 1 nonlinear fit and 1 error, got %v and %d", rec.fits, rec.fitErrors)
+	}
+	if rec.predictions != 5 || rec.predictErrs != 1 {
+		t.Errorf("Expected 5 predictions and 1 error, got %d and %d", rec.predictions, rec.predictErrs)
+	}
+
+	SetInstrumentation(nil)
+	if _, err := r.Predict([]float64{5}); err != nil || rec.predictions != 5 {
+		t.Errorf("Expected no events once instrumentation is removed, got %d predictions", rec.predictions)
+	}
+
+	vars := NewExpvarInstrumentation("regression_test")
+	vars.Fit("regression", 50*time.Millisecond, nil)
+	vars.Fit("regression", 2*time.Second, errors.New("failed"))
+	vars.Predict("regression", 4, nil)
+	m := expvar.Get("regression_test").(*expvar.Map)
+	for key, want := range map[string]string{
+		"regression_fits":                       "2",
+		"regression_fit_errors":                 "1",
+		"regression_fit_seconds_bucket_le_0.1":  "1",
+		"regression_fit_seconds_bucket_le_10":   "2",
+		"regression_fit_seconds_bucket_le_+Inf": "2",
+		"regression_predictions":                "4",
+	} {
+		if got := m.Get(key); got == nil || got.String() != want {
+			t.Errorf("Expected %s = %s, got %v", key, want, got)
+		}
+	}
+}
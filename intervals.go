@@ -0,0 +1,261 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// CovarianceType selects the estimator of the covariance of the coefficients, and so of their
+// standard errors and of the uncertainty in predictions.
+type CovarianceType int
+
+const (
+	// Classical assumes homoscedastic errors, estimating the covariance as σ²(XᵀX)⁻¹.
+	Classical CovarianceType = iota
+	// HC0 is White's heteroscedasticity consistent estimator, (XᵀX)⁻¹Xᵀdiag(eᵢ²)X(XᵀX)⁻¹.
+	HC0
+	// HC1 scales HC0 by n/(n-k) to correct its bias in small samples.
+	HC1
+	// HC2 weights each squared residual by 1/(1-hᵢ), where hᵢ is the leverage.
+	HC2
+	// HC3 weights each squared residual by 1/(1-hᵢ)², approximating the jackknife. It is the
+	// recommended choice for small samples.
+	HC3
+	// ClusterRobust allows the errors to be correlated within clusters, the entities of panel data
+	// points, with the usual G/(G-1)·(n-1)/(n-k) correction for G clusters. Data points without an
+	// entity form a cluster of their own.
+	ClusterRobust
+)
+
+// logChiSquaredMean is E[log χ²₁], the bias of the log of a squared normal residual as an estimate
+// of the log variance.
+const logChiSquaredMean = -1.2703628454614782
+
+// SetCovarianceType sets the estimator of the covariance of the coefficients. Heteroscedasticity
+// consistent estimators are only available for least squares fits without constraints, fixed
+// effects or instruments.
+func (r *Regression) SetCovarianceType(t CovarianceType) {
+	r.covType = t
+}
+
+// SetVarianceModel sets whether Run fits a model of the error variance, regressing the log of the
+// squared residuals on the variables, so that prediction intervals widen where the errors are larger.
+func (r *Regression) SetVarianceModel(enabled bool) {
+	r.varianceModel = enabled
+}
+
+// robustCovariance returns the heteroscedasticity or cluster robust covariance of the coefficients c,
+// or nil if the design is singular. Clusters holds the cluster of each row for ClusterRobust.
+func robustCovariance(t CovarianceType, x, y *mat.Dense, c []float64, clusters []string) *mat.SymDense {
+	n, k := x.Dims()
+	inv, err := crossProductInverse(x)
+	if err != nil {
+		return nil
+	}
+	// meat is Xᵀdiag(ωᵢ)X, where ωᵢ is the weighted squared residual
+	b := mat.NewVecDense(k, c)
+	if t == ClusterRobust {
+		return sandwich(inv, clusterMeat(x, y, b, clusters))
+	}
+	meat := mat.NewSymDense(k, nil)
+	for i := 0; i < n; i++ {
+		row := x.RowView(i)
+		e := y.At(i, 0) - mat.Dot(row, b)
+		h := mat.Inner(row, inv, row)
+		w := e * e
+		switch t {
+		case HC1:
+			w *= float64(n) / float64(n-k)
+		case HC2:
+			w /= 1 - h
+		case HC3:
+			w /= (1 - h) * (1 - h)
+		}
+		meat.SymRankOne(meat, w, row)
+	}
+	return sandwich(inv, meat)
+}
+
+// sandwich returns the covariance bread·meat·bread.
+func sandwich(bread, meat mat.Symmetric) *mat.SymDense {
+	k := bread.SymmetricDim()
+	var product mat.Dense
+	product.Product(bread, meat, bread)
+	cov := mat.NewSymDense(k, nil)
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			cov.SetSym(i, j, product.At(i, j))
+		}
+	}
+	return cov
+}
+
+// clusterMeat returns Σ XₘᵀeₘeₘᵀXₘ over the clusters m, with the small sample correction.
+func clusterMeat(x, y *mat.Dense, b *mat.VecDense, clusters []string) *mat.SymDense {
+	n, k := x.Dims()
+	index := make(map[string]int)
+	var scores []*mat.VecDense
+	for i := 0; i < n; i++ {
+		m, ok := index[clusters[i]]
+		if !ok || clusters[i] == "" {
+			m = len(scores)
+			index[clusters[i]] = m
+			scores = append(scores, mat.NewVecDense(k, nil))
+		}
+		row := x.RowView(i)
+		scores[m].AddScaledVec(scores[m], y.At(i, 0)-mat.Dot(row, b), row)
+	}
+	g := float64(len(scores))
+	scale := g / (g - 1) * float64(n-1) / float64(n-k)
+	meat := mat.NewSymDense(k, nil)
+	for _, score := range scores {
+		meat.SymRankOne(meat, scale, score)
+	}
+	return meat
+}
+
+// fitVarianceModel regresses the log of the squared residuals on the design, recording the
+// coefficients of the model for each column of the full design.
+func (r *Regression) fitVarianceModel(x *mat.Dense, active []int, cols int) error {
+	n, _ := x.Dims()
+	logSquares := mat.NewDense(n, 1, nil)
+	for i, d := range r.Data {
+		e := d.Observed - d.Predicted
+		logSquares.Set(i, 0, math.Log(math.Max(e*e, math.SmallestNonzeroFloat64)))
+	}
+	g, err := leastSquares(x, logSquares)
+	if err != nil {
+		return err
+	}
+	r.varianceCoeffs = make([]float64, cols)
+	for k, j := range active {
+		r.varianceCoeffs[j] = g[k]
+	}
+	r.varianceCoeffs[0] -= logChiSquaredMean
+	return nil
+}
+
+// PredictionInterval is a prediction with an interval expected to contain a new observation.
+type PredictionInterval struct {
+	Value float64
+	Lower float64
+	Upper float64
+	// StdErr is the standard error of the predicted mean, from the covariance of the coefficients.
+	StdErr float64
+	// Sigma is the standard deviation of a new observation about the mean, from the variance model
+	// if one was fitted and otherwise the residual standard error.
+	Sigma float64
+}
+
+// PredictInterval predicts the observed value for vars with a prediction interval at the given
+// confidence level, such as 0.95. The uncertainty in the mean uses the covariance set by
+// SetCovarianceType and the spread of new observations the variance model, if enabled, so that
+// heteroscedastic errors are reflected in the interval.
+func (r *Regression) PredictInterval(vars []float64, level float64) (*PredictionInterval, error) {
+	if !(level > 0 && level < 1) {
+		return nil, fmt.Errorf("%w: confidence level %v", ErrSignificance, level)
+	}
+	value, err := r.Predict(vars)
+	if err != nil {
+		return nil, err
+	}
+	return r.predictionInterval(vars, value, level)
+}
+
+// predictionInterval returns the prediction interval about the prediction value for vars.
+func (r *Regression) predictionInterval(vars []float64, value, level float64) (*PredictionInterval, error) {
+	if r.cov == nil || r.residualDF <= 0 {
+		return nil, fmt.Errorf("%w: the covariance of the coefficients is unavailable", ErrSingular)
+	}
+
+	x := append([]float64{1}, r.features(vars)...)
+	meanVar := r.meanVariance(x)
+	var logVar float64
+	for i := range x {
+		if r.varianceCoeffs != nil && !math.IsNaN(r.cov.At(i, i)) {
+			logVar += x[i] * r.varianceCoeffs[i]
+		}
+	}
+
+	p := &PredictionInterval{Value: value, StdErr: math.Sqrt(meanVar), Sigma: r.residualStdErr}
+	if r.varianceCoeffs != nil {
+		p.Sigma = math.Sqrt(math.Exp(logVar))
+	}
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(r.residualDF)}.Quantile((1 + level) / 2)
+	half := t * math.Sqrt(meanVar+p.Sigma*p.Sigma)
+	p.Lower, p.Upper = value-half, value+half
+	return p, nil
+}
+
+// meanVariance returns the variance of the predicted mean for the design row x, which starts with the
+// 1 of the offset, from the covariance of the coefficients.
+func (r *Regression) meanVariance(x []float64) float64 {
+	var v float64
+	for i := range x {
+		if math.IsNaN(r.cov.At(i, i)) {
+			// Dropped variables have no coefficient
+			continue
+		}
+		for j := range x {
+			if !math.IsNaN(r.cov.At(j, j)) {
+				v += x[i] * r.cov.At(i, j) * x[j]
+			}
+		}
+	}
+	return v
+}
+
+// InversePrediction is an estimate of the variable that produced an observed value, with a
+// confidence interval, as when reading an unknown sample off a standard curve.
+type InversePrediction struct {
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// InversePredict estimates the variable x of a model with a single variable from one or more
+// replicate observations y of the same unknown, as (ȳ - a)/b for the fitted line a + bx, with a
+// confidence interval at the given level by Fieller's method. The interval holds the x for which
+// ȳ - a - bx is not significantly different from zero, allowing for the covariance of a and b and
+// the residual variance of the mean of the replicates. When the slope is not significantly different
+// from zero the interval is unbounded and ErrSignificance is returned.
+func (r *Regression) InversePredict(y []float64, level float64) (*InversePrediction, error) {
+	if !r.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	if !(level > 0 && level < 1) {
+		return nil, fmt.Errorf("%w: confidence level %v", ErrSignificance, level)
+	}
+	if r.rawVars != 1 || len(r.coeff) != 2 {
+		return nil, fmt.Errorf("%w: inverse prediction needs a model with a single variable and no feature crosses", ErrIncompatibleOptions)
+	}
+	if len(y) == 0 {
+		return nil, fmt.Errorf("%w: no observed values to invert", ErrNotEnoughData)
+	}
+	if r.cov == nil || r.residualDF <= 0 {
+		return nil, fmt.Errorf("%w: the covariance of the coefficients is unavailable", ErrSingular)
+	}
+	var mean float64
+	for _, v := range y {
+		mean += v / float64(len(y))
+	}
+	a, b := r.Coeff(0), r.Coeff(1)
+	d := mean - a
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(r.residualDF)}.Quantile((1 + level) / 2)
+	t2, s2 := t*t, r.residualStdErr*r.residualStdErr
+
+	// (d - bx)² ≤ t²(s²/m + Var(a) + 2x Cov(a, b) + x² Var(b)), a quadratic Ax² + Bx + C ≤ 0
+	qa := b*b - t2*r.cov.At(1, 1)
+	qb := -2 * (b*d + t2*r.cov.At(0, 1))
+	qc := d*d - t2*(s2/float64(len(y))+r.cov.At(0, 0))
+	p := &InversePrediction{Value: d / b}
+	if !(qa > 0) {
+		return nil, fmt.Errorf("%w: the slope is not significantly different from zero at level %v, so the interval is unbounded", ErrSignificance, level)
+	}
+	root := math.Sqrt(qb*qb - 4*qa*qc)
+	p.Lower, p.Upper = (-qb-root)/(2*qa), (-qb+root)/(2*qa)
+	return p, nil
+}
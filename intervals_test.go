@@ -0,0 +1,186 @@
+package regression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCovarianceType(t *testing.T) {
+	fit := func(ct CovarianceType) *Regression {
+		r := new(Regression)
+		r.SetCovarianceType(ct)
+		r.Train(MakeDataPoints(anscombe, 0)...)
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	// For a single variable, HC0 gives Var(b₁) = Σ(x - x̄)²e² / (Σ(x - x̄)²)²
+	r := fit(HC0)
+	var mean, sxx, meat float64
+	for _, d := range r.Data {
+		mean += d.Variables[0] / float64(len(r.Data))
+	}
+	for _, d := range r.Data {
+		dx := d.Variables[0] - mean
+		e := d.Observed - d.Predicted
+		sxx += dx * dx
+		meat += dx * dx * e * e
+	}
+	if want := math.Sqrt(meat) / sxx; math.Abs(r.StdErr(1)-want) > 1e-12 {
+		t.Errorf("Expected HC0 standard error %v, got %v", want, r.StdErr(1))
+	}
+
+	// The corrections inflate the standard errors in turn
+	hc1, hc3 := fit(HC1), fit(HC3)
+	if want := r.StdErr(1) * math.Sqrt(11.0/9); math.Abs(hc1.StdErr(1)-want) > 1e-12 {
+		t.Errorf("Expected HC1 standard error %v, got %v", want, hc1.StdErr(1))
+	}
+	if hc3.StdErr(1) <= fit(HC2).StdErr(1) || fit(HC2).StdErr(1) <= r.StdErr(1) {
+		t.Errorf("Expected HC0 < HC2 < HC3, got %v, %v and %v", r.StdErr(1), fit(HC2).StdErr(1), hc3.StdErr(1))
+	}
+
+	c := new(Regression)
+	c.SetCovarianceType(HC3)
+	c.AddConstraint([]float64{0, 1}, 0.5)
+	c.Train(MakeDataPoints(anscombe, 0)...)
+	if err := c.Run(); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions, got %v", err)
+	}
+}
+
+func TestPredictInterval(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Run()
+
+	// The classical interval is ŷ ± t·s·√(1 + 1/n + (x - x̄)²/Sxx)
+	p, err := r.PredictInterval([]float64{12}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mean, sxx float64
+	for _, d := range r.Data {
+		mean += d.Variables[0] / 11
+	}
+	for _, d := range r.Data {
+		sxx += (d.Variables[0] - mean) * (d.Variables[0] - mean)
+	}
+	const t9 = 2.2621571627409915
+	half := t9 * r.ResidualStdErr() * math.Sqrt(1+1.0/11+(12-mean)*(12-mean)/sxx)
+	if math.Abs(p.Upper-p.Value-half) > 1e-9 || math.Abs(p.Value-p.Lower-half) > 1e-9 {
+		t.Errorf("Expected an interval of ±%v, got [%v, %v] about %v", half, p.Lower, p.Upper, p.Value)
+	}
+	if _, err := r.PredictInterval([]float64{12}, 95); !errors.Is(err, ErrSignificance) {
+		t.Errorf("Expected ErrSignificance, got %v", err)
+	}
+
+	// With errors proportional to x, the variance model widens the interval where x is large
+	rng := rand.New(rand.NewSource(1))
+	var data [][]float64
+	for i := 0; i < 200; i++ {
+		x := 1 + float64(i%20)
+		data = append(data, []float64{2 + 3*x + x*rng.NormFloat64(), x})
+	}
+	h := new(Regression)
+	h.SetVarianceModel(true)
+	h.SetCovarianceType(HC3)
+	h.Train(MakeDataPoints(data, 0)...)
+	if err := h.Run(); err != nil {
+		t.Fatal(err)
+	}
+	low, err := h.PredictInterval([]float64{2}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	high, err := h.PredictInterval([]float64{19}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if high.Sigma < 4*low.Sigma || high.Upper-high.Lower < 4*(low.Upper-low.Lower) {
+		t.Errorf("Expected a much wider interval at x = 19 than at x = 2, got σ %v and %v", high.Sigma, low.Sigma)
+	}
+}
+
+func TestClusterRobust(t *testing.T) {
+	// With every point in its own cluster the estimator is HC1
+	points := MakeDataPoints(anscombe, 0)
+	r := new(Regression)
+	r.SetCovarianceType(ClusterRobust)
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	hc1 := new(Regression)
+	hc1.SetCovarianceType(HC1)
+	hc1.Train(MakeDataPoints(anscombe, 0)...)
+	hc1.Run()
+	if want := hc1.StdErr(1); math.Abs(r.StdErr(1)-want) > 1e-12*want {
+		t.Errorf("Expected standard error %v as for HC1, got %v", want, r.StdErr(1))
+	}
+
+	// Correlated errors within clusters inflate the standard errors
+	rng := rand.New(rand.NewSource(3))
+	c, ind := new(Regression), new(Regression)
+	c.SetCovarianceType(ClusterRobust)
+	for g := 0; g < 20; g++ {
+		shock, x := rng.NormFloat64(), rng.NormFloat64()
+		for i := 0; i < 10; i++ {
+			y := 1 + x + shock + 0.1*rng.NormFloat64()
+			c.Train(PanelDataPoint(fmt.Sprint(g), y, []float64{x}))
+			ind.Train(DataPoint(y, []float64{x}))
+		}
+	}
+	c.Run()
+	ind.Run()
+	if c.StdErr(1) < 2*ind.StdErr(1) {
+		t.Errorf("Expected clustering to inflate the standard error %v, got %v", ind.StdErr(1), c.StdErr(1))
+	}
+}
+
+func TestInversePredict(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	single, err := r.InversePredict([]float64{8}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := r.Coeff(0), r.Coeff(1)
+	if math.Abs(single.Value-(8-a)/b) > 1e-12 || !(single.Lower < single.Value && single.Value < single.Upper) {
+		t.Errorf("Expected the interval to contain the estimate %v, got %+v", (8-a)/b, single)
+	}
+	// At each end of the interval the prediction interval of a new observation just reaches 8
+	for _, x := range []float64{single.Lower, single.Upper} {
+		p, err := r.PredictInterval([]float64{x}, 0.95)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(p.Lower-8) > 1e-9 && math.Abs(p.Upper-8) > 1e-9 {
+			t.Errorf("Expected the prediction interval at %v to end at 8, got %+v", x, p)
+		}
+	}
+
+	replicated, err := r.InversePredict([]float64{7.5, 8, 8.5}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicated.Value != single.Value || replicated.Upper-replicated.Lower >= single.Upper-single.Lower {
+		t.Errorf("Expected replicates to narrow the interval %+v, got %+v", single, replicated)
+	}
+
+	flat := new(Regression)
+	flat.Train(DataPoint(1, []float64{1}), DataPoint(2, []float64{2}), DataPoint(1, []float64{3}), DataPoint(2, []float64{4}))
+	if err := flat.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := flat.InversePredict([]float64{1.5}, 0.95); !errors.Is(err, ErrSignificance) {
+		t.Errorf("Expected ErrSignificance for an insignificant slope, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// FirstStage describes the first stage regression of an endogenous variable on the exogenous
+// variables and the instruments. F tests whether the instruments are jointly relevant; values
+// below about 10 are the usual sign of weak instruments.
+type FirstStage struct {
+	Variable int
+	F        float64
+	DF1      int
+	DF2      int
+	P        float64
+}
+
+// SetEndogenous marks variables as endogenous. When the regression is run they are replaced
+// by their fitted values from a first stage regression on the instruments.
+func (r *Regression) SetEndogenous(vars ...int) {
+	r.endogenous = append(r.endogenous, vars...)
+}
+
+// SetInstruments marks variables as excluded instruments for the endogenous variables.
+// Instruments are used in the first stage only, so their coefficients are reported as zero.
+func (r *Regression) SetInstruments(vars ...int) {
+	r.instruments = append(r.instruments, vars...)
+}
+
+// FirstStage returns the first stage diagnostics for each endogenous variable after running a
+// two stage least squares regression.
+func (r *Regression) FirstStage() []FirstStage {
+	return r.firstStage
+}
+
+// twoStageLeastSquares fits the coefficients by instrumenting the endogenous variables and
+// records the corrected coefficient covariance.
+func (r *Regression) twoStageLeastSquares(observed, variables *mat.Dense) ([]float64, error) {
+	n, cols := variables.Dims()
+	if len(r.instruments) < len(r.endogenous) {
+		return nil, fmt.Errorf("%w: %d instruments for %d endogenous variables", ErrUnderidentified, len(r.instruments), len(r.endogenous))
+	}
+
+	role := make([]int, cols) // 0 exogenous, 1 endogenous, 2 instrument
+	for _, v := range r.endogenous {
+		if v < 0 || v+1 >= cols || role[v+1] != 0 {
+			return nil, fmt.Errorf("%w: endogenous variable %d", ErrVariableIndex, v)
+		}
+		role[v+1] = 1
+	}
+	for _, v := range r.instruments {
+		if v < 0 || v+1 >= cols || role[v+1] != 0 {
+			return nil, fmt.Errorf("%w: instrument %d", ErrVariableIndex, v)
+		}
+		role[v+1] = 2
+	}
+
+	var structural, exogenous, first []int
+	for j, kind := range role {
+		if kind != 2 {
+			structural = append(structural, j)
+		}
+		if kind == 0 {
+			exogenous = append(exogenous, j)
+		}
+		if kind != 1 {
+			first = append(first, j)
+		}
+	}
+	if n <= len(first) || n <= len(structural) {
+		return nil, fmt.Errorf("%w: %d observations for %d first stage columns", ErrTooManyVars, n, len(first))
+	}
+
+	z := columns(variables, first)
+	restricted := columns(variables, exogenous)
+	x := columns(variables, structural)
+	xhat := mat.DenseCopyOf(x)
+
+	// First stage, replacing each endogenous variable with its fitted values
+	r.firstStage = r.firstStage[:0]
+	for k, j := range structural {
+		if role[j] != 1 {
+			continue
+		}
+		y := columns(variables, []int{j})
+		gamma, err := leastSquares(z, y)
+		if err != nil {
+			return nil, err
+		}
+		unrestricted := sumOfSquaredResiduals(z, y, gamma)
+		delta, err := leastSquares(restricted, y)
+		if err != nil {
+			return nil, err
+		}
+		stage := FirstStage{Variable: j - 1, DF1: len(first) - len(exogenous), DF2: n - len(first)}
+		stage.F = ((sumOfSquaredResiduals(restricted, y, delta) - unrestricted) / float64(stage.DF1)) / (unrestricted / float64(stage.DF2))
+		stage.P = distuv.F{D1: float64(stage.DF1), D2: float64(stage.DF2)}.Survival(stage.F)
+		r.firstStage = append(r.firstStage, stage)
+
+		for i := 0; i < n; i++ {
+			fitted := 0.0
+			for l := range first {
+				fitted += z.At(i, l) * gamma[l]
+			}
+			xhat.Set(i, k, fitted)
+		}
+	}
+
+	// Second stage, with residuals taken against the original endogenous variables
+	b, err := leastSquares(xhat, observed)
+	if err != nil {
+		return nil, err
+	}
+	cov := coefficientCovariance(xhat, x, observed, b)
+	r.residualDF = n - len(structural)
+
+	c := make([]float64, cols)
+	r.cov = mat.NewSymDense(cols, nil)
+	for j := 0; j < cols; j++ {
+		r.cov.SetSym(j, j, math.NaN())
+	}
+	for k, j := range structural {
+		c[j] = b[k]
+		if cov == nil {
+			continue
+		}
+		for l, m := range structural[:k+1] {
+			r.cov.SetSym(j, m, cov.At(k, l))
+		}
+	}
+	if cov == nil {
+		r.cov = nil
+	}
+	return c, nil
+}
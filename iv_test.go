@@ -0,0 +1,73 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTwoStageLeastSquares(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := make([]*dataPoint, 0, 500)
+	for i := 0; i < 500; i++ {
+		z := rnd.NormFloat64()
+		u := rnd.NormFloat64()
+		x := z + u + 0.5*rnd.NormFloat64()
+		y := 1 + 2*x + 3*u
+		points = append(points, DataPoint(y, []float64{x, z}))
+	}
+
+	ols := new(Regression)
+	for _, p := range points {
+		ols.Train(DataPoint(p.Observed, p.Variables[:1:1]))
+	}
+	if err := ols.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(ols.Coeff(1)-2) < 0.5 {
+		t.Errorf("Expected the OLS estimate to be biased, got %.2f", ols.Coeff(1))
+	}
+
+	r := new(Regression)
+	r.Train(points...)
+	r.SetEndogenous(0)
+	r.SetInstruments(1)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Coeff(1)-2) > 0.3 {
+		t.Errorf("Expected the 2SLS estimate to be close to 2, got %.2f", r.Coeff(1))
+	}
+	if r.Coeff(2) != 0 || !math.IsNaN(r.StdErr(2)) {
+		t.Errorf("Expected the instrument to be excluded, got %v with std err %v", r.Coeff(2), r.StdErr(2))
+	}
+	if se := r.StdErr(1); se <= ols.StdErr(1) || se > 0.3 {
+		t.Errorf("Expected a corrected standard error larger than the OLS one, got %.4f", se)
+	}
+
+	stages := r.FirstStage()
+	if len(stages) != 1 || stages[0].Variable != 0 {
+		t.Fatalf("Expected one first stage for variable 0, got %v", stages)
+	}
+	if stages[0].F < 100 || stages[0].DF1 != 1 || stages[0].DF2 != 498 {
+		t.Errorf("Expected a strong instrument, got %+v", stages[0])
+	}
+}
+
+func TestTwoStageLeastSquaresErrors(t *testing.T) {
+	r := new(Regression)
+	r.Train(linearSeries(10, 10, 3)...)
+	r.SetEndogenous(0)
+	if err := r.Run(); !errors.Is(err, ErrUnderidentified) {
+		t.Errorf("Expected ErrUnderidentified, got %v", err)
+	}
+
+	r = new(Regression)
+	r.Train(linearSeries(10, 10, 3)...)
+	r.SetEndogenous(0)
+	r.SetInstruments(3)
+	if err := r.Run(); !errors.Is(err, ErrVariableIndex) {
+		t.Errorf("Expected ErrVariableIndex, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package regression
+
+import (
+	"math"
+)
+
+// MichaelisMenten is the Michaelis-Menten model of the rate of an enzyme reaction at substrate
+// concentration S, Vmax·S/(Km + S).
+func MichaelisMenten() NonlinearModel {
+	return NonlinearModel{
+		Name:   "Michaelis-Menten",
+		Params: []string{"Vmax", "Km"},
+		Func: func(x, theta []float64) float64 {
+			return theta[0] * x[0] / (theta[1] + x[0])
+		},
+		Start: michaelisMentenStart,
+	}
+}
+
+// Hill is the Hill model of cooperative binding, Vmax·Sⁿ/(Kⁿ + Sⁿ), with the substrate concentration
+// giving half the maximal rate K and the Hill coefficient n.
+func Hill() NonlinearModel {
+	return NonlinearModel{
+		Name:   "Hill",
+		Params: []string{"Vmax", "K", "n"},
+		Func: func(x, theta []float64) float64 {
+			s := math.Pow(x[0], theta[2])
+			return theta[0] * s / (math.Pow(theta[1], theta[2]) + s)
+		},
+		Start: func(data DataPoints) []float64 {
+			return append(michaelisMentenStart(data), 1)
+		},
+	}
+}
+
+// SubstrateInhibition is the Michaelis-Menten model with uncompetitive inhibition by the substrate,
+// Vmax·S/(Km + S(1 + S/Ki)), whose rate falls at high substrate concentrations.
+func SubstrateInhibition() NonlinearModel {
+	return NonlinearModel{
+		Name:   "substrate inhibition",
+		Params: []string{"Vmax", "Km", "Ki"},
+		Func: func(x, theta []float64) float64 {
+			s := x[0]
+			return theta[0] * s / (theta[1] + s*(1+s/theta[2]))
+		},
+		Start: func(data DataPoints) []float64 {
+			// Start from weak inhibition, ten times the largest concentration
+			var top float64
+			for _, d := range data {
+				top = math.Max(top, d.Variables[0])
+			}
+			return append(michaelisMentenStart(data), 10*top)
+		},
+	}
+}
+
+// michaelisMentenStart estimates Vmax and Km from the Hanes-Woolf linearization S/v = S/Vmax + Km/Vmax,
+// falling back on the largest rate and the concentration at which the rate is nearest half of it.
+func michaelisMentenStart(data DataPoints) []float64 {
+	var n, sx, sy, sxx, sxy, top float64
+	for _, d := range data {
+		s, v := d.Variables[0], d.Observed
+		top = math.Max(top, v)
+		if s > 0 && v > 0 {
+			n, sx, sy, sxx, sxy = n+1, sx+s, sy+s/v, sxx+s*s, sxy+s*s/v
+		}
+	}
+	if n >= 2 && n*sxx-sx*sx > 0 {
+		slope := (n*sxy - sx*sy) / (n*sxx - sx*sx)
+		intercept := (sy - slope*sx) / n
+		if slope > 0 && intercept > 0 {
+			return []float64{1 / slope, intercept / slope}
+		}
+	}
+	km, best := 1.0, math.Inf(1)
+	for _, d := range data {
+		if diff := math.Abs(d.Observed - top/2); d.Variables[0] > 0 && diff < best {
+			km, best = d.Variables[0], diff
+		}
+	}
+	return []float64{top, km}
+}
+
+// KineticsFit holds the parameters of an enzyme kinetics model fitted to rates measured at several
+// substrate concentrations, with their standard errors. Parameters not in the model are NaN.
+type KineticsFit struct {
+	// Vmax is the maximal rate and Km the Michaelis constant, or for the Hill model the concentration
+	// giving half the maximal rate.
+	Vmax, VmaxStdErr float64
+	Km, KmStdErr     float64
+	// Hill is the Hill coefficient n of the Hill model.
+	Hill, HillStdErr float64
+	// Ki is the inhibition constant of the substrate inhibition model.
+	Ki, KiStdErr float64
+	// Fit is the underlying nonlinear fit.
+	Fit *Nonlinear
+}
+
+// FitMichaelisMenten fits the Michaelis-Menten model to rates, the observed values of the data points,
+// measured at the substrate concentrations given by their single variable.
+func FitMichaelisMenten(data DataPoints) (*KineticsFit, error) {
+	return fitKinetics(MichaelisMenten(), data)
+}
+
+// FitHill fits the Hill model to rates measured at the substrate concentrations given by the single
+// variable of the data points.
+func FitHill(data DataPoints) (*KineticsFit, error) {
+	return fitKinetics(Hill(), data)
+}
+
+// FitSubstrateInhibition fits the substrate inhibition model to rates measured at the substrate
+// concentrations given by the single variable of the data points.
+func FitSubstrateInhibition(data DataPoints) (*KineticsFit, error) {
+	return fitKinetics(SubstrateInhibition(), data)
+}
+
+// fitKinetics fits an enzyme kinetics model to copies of the data points, naming its parameters in
+// the KineticsFit.
+func fitKinetics(model NonlinearModel, data DataPoints) (*KineticsFit, error) {
+	n := NewNonlinear(model)
+	for _, d := range data {
+		n.Train(&dataPoint{Observed: d.Observed, Variables: append([]float64(nil), d.Variables...), Label: d.Label})
+	}
+	if err := n.Run(); err != nil {
+		return nil, err
+	}
+	nan := math.NaN()
+	fit := &KineticsFit{
+		Vmax: n.Param(0), VmaxStdErr: n.StdErr(0),
+		Km: n.Param(1), KmStdErr: n.StdErr(1),
+		Hill: nan, HillStdErr: nan,
+		Ki: nan, KiStdErr: nan,
+		Fit: n,
+	}
+	if len(model.Params) > 2 {
+		switch model.Params[2] {
+		case "n":
+			fit.Hill, fit.HillStdErr = n.Param(2), n.StdErr(2)
+		case "Ki":
+			fit.Ki, fit.KiStdErr = n.Param(2), n.StdErr(2)
+		}
+	}
+	return fit, nil
+}
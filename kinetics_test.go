@@ -0,0 +1,54 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// kineticData samples a kinetics model at a range of substrate concentrations with 2% noise.
+func kineticData(model NonlinearModel, theta []float64, rng *rand.Rand) DataPoints {
+	var data DataPoints
+	for _, s := range []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32, 64} {
+		for rep := 0; rep < 3; rep++ {
+			v := model.Func([]float64{s}, theta)
+			data = append(data, DataPoint(v*(1+0.02*rng.NormFloat64()), []float64{s}))
+		}
+	}
+	return data
+}
+
+func TestKinetics(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	within := func(name string, got, se, want float64) {
+		if math.Abs(got-want) > 3*se || math.IsNaN(se) {
+			t.Errorf("Expected %s near %v, got %v ± %v", name, want, got, se)
+		}
+	}
+
+	mm, err := FitMichaelisMenten(kineticData(MichaelisMenten(), []float64{10, 2}, rng))
+	if err != nil {
+		t.Fatal(err)
+	}
+	within("Vmax", mm.Vmax, mm.VmaxStdErr, 10)
+	within("Km", mm.Km, mm.KmStdErr, 2)
+	if !math.IsNaN(mm.Hill) || !math.IsNaN(mm.Ki) {
+		t.Errorf("Expected no Hill coefficient or Ki for Michaelis-Menten, got %v and %v", mm.Hill, mm.Ki)
+	}
+
+	hill, err := FitHill(kineticData(Hill(), []float64{5, 3, 2.5}, rng))
+	if err != nil {
+		t.Fatal(err)
+	}
+	within("Vmax", hill.Vmax, hill.VmaxStdErr, 5)
+	within("K", hill.Km, hill.KmStdErr, 3)
+	within("n", hill.Hill, hill.HillStdErr, 2.5)
+
+	inhibited, err := FitSubstrateInhibition(kineticData(SubstrateInhibition(), []float64{10, 2, 20}, rng))
+	if err != nil {
+		t.Fatal(err)
+	}
+	within("Vmax", inhibited.Vmax, inhibited.VmaxStdErr, 10)
+	within("Km", inhibited.Km, inhibited.KmStdErr, 2)
+	within("Ki", inhibited.Ki, inhibited.KiStdErr, 20)
+}
@@ -0,0 +1,159 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// defaultNeighbors is the number of neighbours NearestNeighbors uses unless SetK is called.
+const defaultNeighbors = 5
+
+// NearestNeighbors is a nonparametric baseline that predicts from the k training points nearest to
+// the variables, by Euclidean distance after standardizing each variable. Comparing its accuracy on
+// held-out data with that of a linear model shows how much structure the linear model misses. It is
+// trained with the same data points as Regression and implements Predictor.
+type NearestNeighbors struct {
+	Data []*dataPoint
+
+	k           int
+	localLinear bool
+
+	hasRun bool
+	means  []float64
+	scales []float64
+	x      [][]float64
+}
+
+// SetK sets the number of neighbours, 5 by default.
+func (nn *NearestNeighbors) SetK(k int) {
+	nn.k = k
+}
+
+// SetLocalLinear sets whether predictions fit a linear regression to the neighbours, weighted by
+// the tricube of their distance as in LOESS, rather than averaging their observed values. Local
+// linear fits are less biased at the edges of the data but need more neighbours than variables.
+func (nn *NearestNeighbors) SetLocalLinear(enabled bool) {
+	nn.localLinear = enabled
+}
+
+// Train adds data points to the training set.
+func (nn *NearestNeighbors) Train(d ...*dataPoint) {
+	nn.Data = append(nn.Data, d...)
+}
+
+// Run prepares the training data for prediction and records the in-sample prediction of each point.
+func (nn *NearestNeighbors) Run() error {
+	if nn.hasRun {
+		return ErrRegressionRun
+	}
+	if nn.k <= 0 {
+		nn.k = defaultNeighbors
+	}
+	n := len(nn.Data)
+	if n < nn.k {
+		return fmt.Errorf("%w: %d data points trained for %d neighbours", ErrNotEnoughData, n, nn.k)
+	}
+	vars := len(nn.Data[0].Variables)
+	for i, d := range nn.Data {
+		if len(d.Variables) != vars {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: has %d, expected %d as in data point 0", ErrVariableCount, len(d.Variables), vars)}
+		}
+		if !d.finite() {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: NaN or infinite value", ErrDesign)}
+		}
+	}
+
+	nn.means, nn.scales = make([]float64, vars), make([]float64, vars)
+	col := make([]float64, n)
+	for j := 0; j < vars; j++ {
+		for i, d := range nn.Data {
+			col[i] = d.Variables[j]
+		}
+		nn.means[j], nn.scales[j] = stat.MeanStdDev(col, nil)
+		if nn.scales[j] == 0 {
+			nn.scales[j] = 1
+		}
+	}
+	nn.x = make([][]float64, n)
+	for i, d := range nn.Data {
+		nn.x[i] = make([]float64, vars)
+		for j, v := range d.Variables {
+			nn.x[i][j] = (v - nn.means[j]) / nn.scales[j]
+		}
+	}
+	nn.hasRun = true
+
+	for _, d := range nn.Data {
+		d.Predicted, _ = nn.Predict(d.Variables)
+		d.Error = d.Predicted - d.Observed
+	}
+	return nil
+}
+
+// Predict predicts the observed value for vars from its nearest neighbours in the training data.
+func (nn *NearestNeighbors) Predict(vars []float64) (float64, error) {
+	if !nn.hasRun {
+		return 0, ErrRegressionNotRun
+	}
+	if len(vars) != len(nn.means) {
+		return 0, fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), len(nn.means))
+	}
+	z := make([]float64, len(vars))
+	for j, v := range vars {
+		z[j] = (v - nn.means[j]) / nn.scales[j]
+	}
+
+	order := make([]int, len(nn.x))
+	dist := make([]float64, len(nn.x))
+	for i, x := range nn.x {
+		order[i] = i
+		for j := range z {
+			dist[i] += (x[j] - z[j]) * (x[j] - z[j])
+		}
+		dist[i] = math.Sqrt(dist[i])
+	}
+	sort.SliceStable(order, func(a, b int) bool { return dist[order[a]] < dist[order[b]] })
+	neighbors := order[:nn.k]
+
+	if nn.localLinear && nn.k > len(z)+1 {
+		if p, ok := nn.localFit(z, neighbors, dist); ok {
+			return p, nil
+		}
+	}
+	var mean float64
+	for _, i := range neighbors {
+		mean += nn.Data[i].Observed / float64(nn.k)
+	}
+	return mean, nil
+}
+
+// localFit fits a tricube weighted linear regression to the neighbours, centered on z, returning its
+// offset. It returns false if the neighbours do not determine a fit.
+func (nn *NearestNeighbors) localFit(z []float64, neighbors []int, dist []float64) (float64, bool) {
+	// Widen the bandwidth slightly so the farthest neighbour keeps some weight
+	bandwidth := dist[neighbors[len(neighbors)-1]] * 1.0001
+	if bandwidth == 0 {
+		return 0, false
+	}
+	cols := len(z) + 1
+	x := mat.NewDense(len(neighbors), cols, nil)
+	y := mat.NewDense(len(neighbors), 1, nil)
+	for r, i := range neighbors {
+		u := dist[i] / bandwidth
+		w := math.Sqrt(math.Pow(1-u*u*u, 3))
+		x.Set(r, 0, w)
+		for j := range z {
+			x.Set(r, j+1, w*(nn.x[i][j]-z[j]))
+		}
+		y.Set(r, 0, w*nn.Data[i].Observed)
+	}
+	b, err := leastSquares(x, y)
+	if err != nil || math.IsNaN(b[0]) || math.IsInf(b[0], 0) {
+		return 0, false
+	}
+	return b[0], true
+}
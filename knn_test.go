@@ -0,0 +1,67 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNearestNeighbors(t *testing.T) {
+	// A quadratic relationship that a straight line misses
+	var train, test [][]float64
+	for i := 0; i < 60; i++ {
+		x := float64(i)/10 - 3
+		train = append(train, []float64{x * x, x})
+		test = append(test, []float64{(x + 0.05) * (x + 0.05), x + 0.05})
+	}
+
+	nn := new(NearestNeighbors)
+	if _, err := nn.Predict([]float64{0}); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	nn.SetK(3)
+	nn.Train(MakeDataPoints(train, 0)...)
+	if err := nn.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// The mean of the three nearest points to 0: -0.1, 0 and 0.1
+	if p, _ := nn.Predict([]float64{0}); math.Abs(p-0.02/3) > 1e-12 {
+		t.Errorf("Expected %v, got %v", 0.02/3, p)
+	}
+
+	r := new(Regression)
+	r.Train(MakeDataPoints(train, 0)...)
+	r.Run()
+	linear, err := r.Evaluate(MakeDataPoints(test, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := EvaluatePredictor(nn, MakeDataPoints(test, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseline.RMSE > linear.RMSE/10 {
+		t.Errorf("Expected the baseline to beat the line, got RMSE %v and %v", baseline.RMSE, linear.RMSE)
+	}
+
+	// A local linear fit removes the bias of averaging at the edge of the data
+	local := new(NearestNeighbors)
+	local.SetK(8)
+	local.SetLocalLinear(true)
+	local.Train(MakeDataPoints(train, 0)...)
+	local.Run()
+	average := new(NearestNeighbors)
+	average.SetK(8)
+	average.Train(MakeDataPoints(train, 0)...)
+	average.Run()
+	edge := []float64{2.9}
+	pl, _ := local.Predict(edge)
+	pa, _ := average.Predict(edge)
+	if math.Abs(pl-2.9*2.9) >= math.Abs(pa-2.9*2.9) {
+		t.Errorf("Expected the local linear fit to be closer to %v at the edge, got %v and %v", 2.9*2.9, pl, pa)
+	}
+
+	if err := new(NearestNeighbors).Run(); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+)
+
+// LearningCurvePoint is the cross-validated error of models trained on a fraction of the training folds.
+type LearningCurvePoint struct {
+	Fraction float64
+	// N is the average number of data points each model was trained on.
+	N float64
+	// TrainRMSE is the error on the data the models were trained on and ValidationRMSE the error on the
+	// held-out folds, both averaged over the folds.
+	TrainRMSE      float64
+	ValidationRMSE float64
+}
+
+// LearningCurve estimates how the error changes with the amount of training data. The training data is
+// split into k folds, and for each fraction models configured like r are trained on that fraction of the
+// data outside each fold and evaluated on the fold. A validation error that is still falling at a fraction
+// of 1 suggests that more data will help, while training and validation errors that have converged suggest
+// it will not. The folds are chosen at random and the models are fitted in parallel.
+func (r *Regression) LearningCurve(fractions []float64, k int, opts ...Option) ([]LearningCurvePoint, error) {
+	o := newOptions(opts)
+	points := r.rawData()
+	if k < 2 || k > len(points) {
+		return nil, fmt.Errorf("%w: %d folds for %d data points", ErrNotEnoughData, k, len(points))
+	}
+	for _, f := range fractions {
+		if f <= 0 || f > 1 {
+			return nil, fmt.Errorf("fraction %v is outside (0, 1]", f)
+		}
+	}
+	folds := folds(len(points), k, o.rand)
+
+	type result struct {
+		n              int
+		train, holdout float64
+		err            error
+	}
+	results := make([]result, len(fractions)*k)
+	parallelFor(o.concurrency, len(results), o.track("learning curve", len(results), func(job int) {
+		fraction, fold := fractions[job/k], job%k
+		var train, holdout DataPoints
+		for f, rows := range folds {
+			for _, i := range rows {
+				if f == fold {
+					holdout = append(holdout, points[i])
+				} else {
+					train = append(train, points[i])
+				}
+			}
+		}
+		train = train[:int(math.Ceil(fraction*float64(len(train))))]
+
+		fit, err := r.fitLike(train)
+		if err != nil {
+			results[job] = result{err: err}
+			return
+		}
+		trainMetrics, err := fit.Evaluate(train)
+		if err != nil {
+			results[job] = result{err: err}
+			return
+		}
+		holdoutMetrics, err := fit.Evaluate(holdout)
+		results[job] = result{n: len(train), train: trainMetrics.RMSE, holdout: holdoutMetrics.RMSE, err: err}
+	}))
+
+	curve := make([]LearningCurvePoint, len(fractions))
+	for i, fraction := range fractions {
+		curve[i].Fraction = fraction
+		for _, res := range results[i*k : (i+1)*k] {
+			if res.err != nil {
+				return nil, fmt.Errorf("fitting %v of the data: %w", fraction, res.err)
+			}
+			curve[i].N += float64(res.n) / float64(k)
+			curve[i].TrainRMSE += res.train / float64(k)
+			curve[i].ValidationRMSE += res.holdout / float64(k)
+		}
+	}
+	return curve, nil
+}
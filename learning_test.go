@@ -0,0 +1,38 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLearningCurve(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	for i := 0; i < 60; i++ {
+		x := float64(i%20) / 4
+		r.Train(DataPoint(1+x-0.5*x*x+0.2*math.Sin(float64(i*i)), []float64{x}))
+	}
+
+	curve, err := r.LearningCurve([]float64{0.1, 0.5, 1}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curve[2].N != 48 {
+		t.Errorf("Expected models trained on all 48 points outside each fold, got %v", curve[2].N)
+	}
+	if curve[0].ValidationRMSE <= curve[2].ValidationRMSE {
+		t.Errorf("Expected the validation error to fall with more data, got %+v", curve)
+	}
+	if curve[2].TrainRMSE > curve[2].ValidationRMSE {
+		t.Errorf("Expected the training error to be below the validation error, got %+v", curve[2])
+	}
+	// The fitted model itself is untouched
+	if r.hasRun || len(r.Data[0].Variables) != 1 {
+		t.Errorf("Expected the regression not to be run")
+	}
+
+	if _, err := r.LearningCurve([]float64{0.01}, 5); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData for too small a fraction, got %v", err)
+	}
+}
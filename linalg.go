@@ -0,0 +1,133 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// leastSquares solves the ordinary least squares problem x*b = y using QR decomposition.
+// Near-singular designs are tolerated in the same way as Run, so only shape problems are errors.
+func leastSquares(x mat.Matrix, y mat.Matrix) ([]float64, error) {
+	rows, cols := x.Dims()
+	if rows < cols {
+		return nil, fmt.Errorf("%w: %d observations for %d columns", ErrTooManyVars, rows, cols)
+	}
+	qr := new(mat.QR)
+	qr.Factorize(x)
+	b := new(mat.Dense)
+	if err := qr.SolveTo(b, false, y); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return nil, err
+		}
+	}
+	coeffs := make([]float64, cols)
+	for i := range coeffs {
+		coeffs[i] = b.At(i, 0)
+	}
+	return coeffs, nil
+}
+
+// crossProductInverse returns (xᵀx)⁻¹ for the design matrix x. It is formed as R⁻¹R⁻ᵀ from the QR
+// factorization x = QR rather than by inverting xᵀx, which would square the condition number of x.
+func crossProductInverse(x mat.Matrix) (*mat.SymDense, error) {
+	rows, cols := x.Dims()
+	if rows < cols {
+		return nil, ErrSingular
+	}
+	qr := new(mat.QR)
+	qr.Factorize(x)
+	var factor mat.Dense
+	qr.RTo(&factor)
+	r := mat.NewTriDense(cols, mat.Upper, nil)
+	for i := 0; i < cols; i++ {
+		if factor.At(i, i) == 0 {
+			return nil, ErrSingular
+		}
+		for j := i; j < cols; j++ {
+			r.SetTri(i, j, factor.At(i, j))
+		}
+	}
+	rinv := new(mat.TriDense)
+	if err := rinv.InverseTri(r); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return nil, err
+		}
+	}
+	inv := mat.NewSymDense(cols, nil)
+	inv.SymOuterK(1, rinv)
+	return inv, nil
+}
+
+// sumOfSquaredResiduals returns the residual sum of squares of y against x*b.
+func sumOfSquaredResiduals(x mat.Matrix, y mat.Matrix, b []float64) float64 {
+	rows, cols := x.Dims()
+	var sse float64
+	for i := 0; i < rows; i++ {
+		res := y.At(i, 0)
+		for j := 0; j < cols; j++ {
+			res -= x.At(i, j) * b[j]
+		}
+		sse += res * res
+	}
+	return sse
+}
+
+// coefficientCovariance returns σ²(x̂ᵀx̂)⁻¹, the covariance of the coefficients b, where σ² is
+// estimated from the residuals of y against x*b. For ordinary least squares x̂ is the same as x.
+// It returns nil if x̂ is singular.
+func coefficientCovariance(xhat, x, y mat.Matrix, b []float64) *mat.SymDense {
+	rows, cols := x.Dims()
+	inv, err := crossProductInverse(xhat)
+	if err != nil {
+		return nil
+	}
+	sigma2 := math.NaN()
+	if rows > cols {
+		sigma2 = sumOfSquaredResiduals(x, y, b) / float64(rows-cols)
+	}
+	inv.ScaleSym(sigma2, inv)
+	return inv
+}
+
+// columns returns a copy of the given columns of m.
+func columns(m mat.Matrix, cols []int) *mat.Dense {
+	rows, _ := m.Dims()
+	out := mat.NewDense(rows, len(cols), nil)
+	for j, c := range cols {
+		for i := 0; i < rows; i++ {
+			out.Set(i, j, m.At(i, c))
+		}
+	}
+	return out
+}
+
+// goodnessOfFit fits y on x by least squares and returns R², as 1 - SSE/SST, and the root mean squared error.
+func goodnessOfFit(x, y mat.Matrix) (r2, rmse float64, err error) {
+	b, err := leastSquares(x, y)
+	if err != nil {
+		return 0, 0, err
+	}
+	rows, _ := y.Dims()
+	var mean, sst float64
+	for i := 0; i < rows; i++ {
+		mean += y.At(i, 0) / float64(rows)
+	}
+	for i := 0; i < rows; i++ {
+		sst += (y.At(i, 0) - mean) * (y.At(i, 0) - mean)
+	}
+	sse := sumOfSquaredResiduals(x, y, b)
+	return 1 - sse/sst, math.Sqrt(sse / float64(rows)), nil
+}
+
+// withoutColumn returns the indices of the columns of a matrix with cols columns, except skip.
+func withoutColumn(cols, skip int) []int {
+	keep := make([]int, 0, cols-1)
+	for j := 0; j < cols; j++ {
+		if j != skip {
+			keep = append(keep, j)
+		}
+	}
+	return keep
+}
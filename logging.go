@@ -0,0 +1,30 @@
+package regression
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SetLogger attaches a logger for debug and warning events during fitting, such as near-singular
+// designs and dropped variables. By default nothing is logged.
+func (r *Regression) SetLogger(logger *slog.Logger) {
+	r.log = logger
+}
+
+// logger returns the attached logger, or one that discards everything.
+func (r *Regression) logger() *slog.Logger {
+	if r.log == nil {
+		return discardLogger
+	}
+	return r.log
+}
+
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler that discards all records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,32 @@
+package regression
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	r := new(Regression)
+	r.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	r.SetVar(1, "batch")
+	r.SetDropConstant(true)
+	for _, row := range anscombe {
+		r.Train(DataPoint(row[0], []float64{row[1], 1}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	logged := buf.String()
+	for _, expected := range []string{
+		`level=DEBUG msg="fitting least squares regression" observations=11 variables=1`,
+		`level=WARN msg="dropped variable" variable=batch`,
+	} {
+		if !strings.Contains(logged, expected) {
+			t.Errorf("Expected the log to contain %q, got\n%s", expected, logged)
+		}
+	}
+}
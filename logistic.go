@@ -0,0 +1,117 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+)
+
+// FourParameterLogistic is the four parameter logistic (4PL) model of a single variable x ≥ 0, such as
+// a dose or concentration, d + (a - d)/(1 + (x/c)^b): the response rises or falls from a at x = 0 to d
+// at large x, passing halfway at x = c, the EC50, with a steepness b.
+func FourParameterLogistic() NonlinearModel {
+	return NonlinearModel{
+		Name:   "4PL",
+		Params: []string{"a", "b", "c", "d"},
+		Func: func(x, theta []float64) float64 {
+			a, b, c, d := theta[0], theta[1], theta[2], theta[3]
+			return d + (a-d)/(1+math.Pow(x[0]/c, b))
+		},
+		Start: func(data DataPoints) []float64 {
+			return logisticStart(data)
+		},
+		EC50: func(theta []float64) float64 {
+			return theta[2]
+		},
+	}
+}
+
+// FiveParameterLogistic is the five parameter logistic (5PL) model d + (a - d)/(1 + (x/c)^b)^g, which
+// adds an asymmetry g to the 4PL model for curves that approach one asymptote faster than the other.
+// With g = 1 it is the 4PL model, and c is then the EC50; otherwise the EC50 is c(2^(1/g) - 1)^(1/b).
+func FiveParameterLogistic() NonlinearModel {
+	return NonlinearModel{
+		Name:   "5PL",
+		Params: []string{"a", "b", "c", "d", "g"},
+		Func: func(x, theta []float64) float64 {
+			a, b, c, d, g := theta[0], theta[1], theta[2], theta[3], theta[4]
+			return d + (a-d)/math.Pow(1+math.Pow(x[0]/c, b), g)
+		},
+		Start: func(data DataPoints) []float64 {
+			return append(logisticStart(data), 1)
+		},
+		EC50: func(theta []float64) float64 {
+			b, c, g := theta[1], theta[2], theta[4]
+			return c * math.Pow(math.Pow(2, 1/g)-1, 1/b)
+		},
+	}
+}
+
+// logisticStart estimates starting values of the 4PL parameters. The asymptotes a and d are the mean
+// responses at the smallest and largest x, widened by 5% of their difference so that every response
+// lies between them, and b and c come from the least squares line through the linearized model
+// log((a - y)/(y - d)) = b log x - b log c at the positive x. Without enough points between the
+// asymptotes the slope is 1 and c the x whose response is nearest their midpoint.
+func logisticStart(data DataPoints) []float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, d := range data {
+		lo, hi = math.Min(lo, d.Variables[0]), math.Max(hi, d.Variables[0])
+	}
+	var a, d float64
+	var nLo, nHi int
+	for _, p := range data {
+		if p.Variables[0] == lo {
+			a += p.Observed
+			nLo++
+		}
+		if p.Variables[0] == hi {
+			d += p.Observed
+			nHi++
+		}
+	}
+	a, d = a/float64(nLo), d/float64(nHi)
+	pad := 0.05 * (d - a)
+	a, d = a-pad, d+pad
+
+	// Regress the logit of the scaled response on log x
+	var n, sx, sy, sxx, sxy float64
+	mid, c, best := (a+d)/2, math.Sqrt(math.Max(lo, 1e-3*hi)*hi), math.Inf(1)
+	for _, p := range data {
+		x, y := p.Variables[0], p.Observed
+		if !(x > 0) {
+			continue
+		}
+		if math.Abs(y-mid) < best {
+			c, best = x, math.Abs(y-mid)
+		}
+		if ratio := (a - y) / (y - d); ratio > 0 {
+			lx, ly := math.Log(x), math.Log(ratio)
+			n, sx, sy, sxx, sxy = n+1, sx+lx, sy+ly, sxx+lx*lx, sxy+lx*ly
+		}
+	}
+	b := 1.0
+	if n >= 2 && n*sxx-sx*sx > 0 {
+		if slope := (n*sxy - sx*sy) / (n*sxx - sx*sx); slope > 0 {
+			b, c = slope, math.Exp(-(sy-slope*sx)/n/slope)
+		}
+	}
+	return []float64{a, b, c, d}
+}
+
+// EC50 returns the variable at which the response of a fitted dose-response model, such as 4PL or
+// 5PL, is halfway between its asymptotes, with its standard error by the delta method. For a falling
+// curve this is the IC50. ErrIncompatibleOptions is returned for models without an EC50.
+func (n *Nonlinear) EC50() (ec50, stdErr float64, err error) {
+	if !n.hasRun {
+		return 0, 0, ErrRegressionNotRun
+	}
+	if n.model.EC50 == nil {
+		return 0, 0, fmt.Errorf("%w: the %s model has no EC50", ErrIncompatibleOptions, n.model.Name)
+	}
+	return n.model.EC50(n.params), math.Sqrt(n.deltaVariance(n.model.EC50)), nil
+}
+
+// IC50 returns the EC50 of a model whose response falls with the variable, the concentration giving
+// half the maximal inhibition.
+func (n *Nonlinear) IC50() (ic50, stdErr float64, err error) {
+	return n.EC50()
+}
@@ -0,0 +1,69 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLogisticStart(t *testing.T) {
+	start := logisticStart(logisticData(0, rand.New(rand.NewSource(1))))
+	// The asymptotes are widened by 5%, and the linearized slope and midpoint are close to the truth
+	if math.Abs(start[0]-(0.1-0.12)) > 0.01 || math.Abs(start[3]-(2.5+0.12)) > 0.1 {
+		t.Errorf("Expected asymptotes near -0.02 and 2.62, got %v", start)
+	}
+	if math.Abs(start[1]-1.2) > 0.3 || math.Abs(start[2]-50)/50 > 0.3 {
+		t.Errorf("Expected b near 1.2 and c near 50, got %v", start)
+	}
+}
+
+func TestDoseResponse(t *testing.T) {
+	four := NewNonlinear(FourParameterLogistic())
+	four.Train(logisticData(0.02, rand.New(rand.NewSource(3)))...)
+	if err := four.Run(); err != nil {
+		t.Fatal(err)
+	}
+	ec50, se, err := four.EC50()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ec50 != four.Param(2) || math.Abs(se-four.StdErr(2))/se > 1e-4 || math.Abs(ec50-50) > 3*se {
+		t.Errorf("Expected the EC50 c = 50 with its standard error, got %v ± %v", ec50, se)
+	}
+
+	// An asymmetric inhibition curve falling from 100 to 5, with g = 0.5
+	rng := rand.New(rand.NewSource(4))
+	five := NewNonlinear(FiveParameterLogistic())
+	truth := []float64{100, 1.5, 10, 5, 0.5}
+	for _, x := range []float64{0, 0.3, 1, 2, 3, 5, 10, 20, 30, 50, 100, 300} {
+		for rep := 0; rep < 3; rep++ {
+			five.Train(DataPoint(five.Model().Func([]float64{x}, truth)+0.5*rng.NormFloat64(), []float64{x}))
+		}
+	}
+	if err := five.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := 10 * math.Pow(math.Pow(2, 1/0.5)-1, 1/1.5)
+	if ic50, se, err := five.IC50(); err != nil || math.Abs(ic50-want) > 3*se {
+		t.Errorf("Expected the IC50 %v, got %v ± %v, %v", want, ic50, se, err)
+	}
+	// Halfway between the fitted asymptotes at the EC50
+	ic50, _, _ := five.IC50()
+	half, _ := five.Predict([]float64{ic50})
+	if math.Abs(half-(five.Param(0)+five.Param(3))/2) > 1e-9 {
+		t.Errorf("Expected the response at the IC50 to be halfway between the asymptotes, got %v", half)
+	}
+
+	linear := NewNonlinear(NonlinearModel{Name: "line", Params: []string{"a", "b"}, Func: func(x, theta []float64) float64 {
+		return theta[0] + theta[1]*x[0]
+	}})
+	linear.Train(MakeDataPoints(anscombe, 0)...)
+	linear.SetStart([]float64{0, 0})
+	if err := linear.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := linear.EC50(); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions for a model without an EC50, got %v", err)
+	}
+}
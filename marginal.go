@@ -0,0 +1,99 @@
+package regression
+
+import (
+	"math"
+)
+
+// MarginalEffect is the effect on the prediction of a change in a single input variable.
+// Once feature crosses are applied an input's coefficient is no longer its marginal effect,
+// as the input also enters through the crossed features.
+type MarginalEffect struct {
+	Index int
+	Name  string
+	// Effect is the change in the prediction per unit change in the variable.
+	Effect float64
+	// Elasticity is the percentage change in the prediction per percentage change in the variable.
+	Elasticity float64
+}
+
+// AverageMarginalEffects returns the marginal effect and elasticity of each input variable,
+// before any feature crosses, averaged over the training data.
+func (r *Regression) AverageMarginalEffects() ([]MarginalEffect, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	effects := r.newMarginalEffects()
+	for _, d := range r.Data {
+		vars := d.Variables
+		p, err := r.predict(vars)
+		if err != nil {
+			return nil, err
+		}
+		for j := range effects {
+			slope, err := r.partialDerivative(vars, j)
+			if err != nil {
+				return nil, err
+			}
+			effects[j].Effect += slope / float64(len(r.Data))
+			effects[j].Elasticity += slope * vars[j] / p / float64(len(r.Data))
+		}
+	}
+	return effects, nil
+}
+
+// MarginalEffectsAtMeans returns the marginal effect and elasticity of each input variable,
+// before any feature crosses, evaluated at the mean of the training data.
+func (r *Regression) MarginalEffectsAtMeans() ([]MarginalEffect, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	means := make([]float64, r.rawVars)
+	for _, d := range r.Data {
+		for j := range means {
+			means[j] += d.Variables[j] / float64(len(r.Data))
+		}
+	}
+	p, err := r.predict(means)
+	if err != nil {
+		return nil, err
+	}
+
+	effects := r.newMarginalEffects()
+	for j := range effects {
+		slope, err := r.partialDerivative(means, j)
+		if err != nil {
+			return nil, err
+		}
+		effects[j].Effect = slope
+		effects[j].Elasticity = slope * means[j] / p
+	}
+	return effects, nil
+}
+
+func (r *Regression) newMarginalEffects() []MarginalEffect {
+	effects := make([]MarginalEffect, r.rawVars)
+	for j := range effects {
+		effects[j] = MarginalEffect{Index: j, Name: r.GetVar(j)}
+	}
+	return effects
+}
+
+// partialDerivative estimates the derivative of the prediction with respect to input variable j
+// at vars using central differences, so that it accounts for any feature crosses.
+func (r *Regression) partialDerivative(vars []float64, j int) (float64, error) {
+	h := 1e-6 * math.Max(1, math.Abs(vars[j]))
+	x := make([]float64, len(vars))
+	copy(x, vars)
+
+	x[j] = vars[j] + h
+	up, err := r.predict(x)
+	if err != nil {
+		return 0, err
+	}
+	x[j] = vars[j] - h
+	down, err := r.predict(x)
+	if err != nil {
+		return 0, err
+	}
+	return (up - down) / (2 * h), nil
+}
@@ -0,0 +1,62 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMarginalEffects(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "A")
+	r.SetVar(1, "B")
+	for i := 0; i < 20; i++ {
+		a := float64(i)
+		b := 1 + math.Mod(float64(i*7), 5)
+		r.Train(DataPoint(3+2*a+0.5*a*a+4*b+a*b, []float64{a, b}))
+	}
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(MultiplierCross(0, 1))
+	if _, err := r.AverageMarginalEffects(); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// dy/da = 2 + a + b and dy/db = 4 + a
+	var meanA, meanB, ame float64
+	for _, d := range r.Data {
+		meanA += d.Variables[0] / 20
+		meanB += d.Variables[1] / 20
+	}
+	for _, d := range r.Data {
+		ame += (2 + d.Variables[0] + d.Variables[1]) / 20
+	}
+
+	avg, err := r.AverageMarginalEffects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(avg) != 2 || avg[0].Name != "A" || avg[1].Index != 1 {
+		t.Fatalf("Expected effects for the two input variables, got %v", avg)
+	}
+	if math.Abs(avg[0].Effect-ame) > 1e-4 {
+		t.Errorf("Expected an average marginal effect of %v, got %v", ame, avg[0].Effect)
+	}
+	if math.Abs(avg[1].Effect-(4+meanA)) > 1e-4 {
+		t.Errorf("Expected an average marginal effect of %v, got %v", 4+meanA, avg[1].Effect)
+	}
+
+	atMeans, err := r.MarginalEffectsAtMeans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(atMeans[0].Effect-(2+meanA+meanB)) > 1e-4 {
+		t.Errorf("Expected a marginal effect at means of %v, got %v", 2+meanA+meanB, atMeans[0].Effect)
+	}
+	p, _ := r.Predict([]float64{meanA, meanB})
+	if e := atMeans[1].Effect * meanB / p; math.Abs(atMeans[1].Elasticity-e) > 1e-9 {
+		t.Errorf("Expected an elasticity of %v, got %v", e, atMeans[1].Elasticity)
+	}
+}
@@ -0,0 +1,164 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// simexLambdas are the multiples of the measurement error variance added by SIMEX, and
+// simexReplicates the number of simulations at each.
+var simexLambdas = []float64{0.5, 1, 1.5, 2}
+
+const simexReplicates = 50
+
+// MeasurementErrorFit holds coefficients corrected for the attenuation caused by errors in the
+// measurement of the variables.
+type MeasurementErrorFit struct {
+	// Naive holds the coefficients of the uncorrected fit and Coeffs the corrected coefficients,
+	// starting with the offset as in GetCoeffs.
+	Naive  []float64
+	Coeffs []float64
+	// Reliability holds the reliability ratio of each variable, the fraction of its observed variance
+	// that is not measurement error. Coefficients are attenuated by roughly this factor.
+	Reliability []float64
+}
+
+// checkErrorVariances checks that there is a non-negative measurement error variance for each variable
+// of a model that can be refitted, returning the number of variables.
+func (r *Regression) checkErrorVariances(errVars []float64) (int, error) {
+	if err := r.requireData(); err != nil {
+		return 0, err
+	}
+	raw, _ := r.numVars()
+	if len(errVars) != raw {
+		return 0, fmt.Errorf("%w: %d measurement error variances for %d variables", ErrDesign, len(errVars), raw)
+	}
+	for i, v := range errVars {
+		if !(v >= 0) {
+			return 0, fmt.Errorf("%w: measurement error variance %v for variable %d", ErrDesign, v, i)
+		}
+	}
+	if r.fixedEffects || len(r.endogenous) > 0 || len(r.instruments) > 0 || len(r.constraints) > 0 || r.signConstrained() {
+		return 0, fmt.Errorf("%w: measurement error correction needs an unrestricted least squares fit", ErrIncompatibleOptions)
+	}
+	return raw, nil
+}
+
+// reliability returns the reliability ratio of each variable given its measurement error variance.
+func reliability(points DataPoints, errVars []float64) []float64 {
+	n := float64(len(points))
+	ratios := make([]float64, len(errVars))
+	for j, u := range errVars {
+		var mean, ss float64
+		for _, d := range points {
+			mean += d.Variables[j] / n
+		}
+		for _, d := range points {
+			ss += (d.Variables[j] - mean) * (d.Variables[j] - mean)
+		}
+		ratios[j] = 1 - u/(ss/(n-1))
+	}
+	return ratios
+}
+
+// CorrectMeasurementError corrects the coefficients for known measurement error variances of the
+// variables, one for each variable with zero for those measured exactly, by the method of moments:
+// the error variances are subtracted from the diagonal of XᵀX/n before solving the normal equations.
+// The errors are assumed independent of each other and of the true values. Feature crosses of
+// noisy variables have errors that depend on the true values, so models with crosses must use SIMEX.
+func (r *Regression) CorrectMeasurementError(errVars []float64) (*MeasurementErrorFit, error) {
+	raw, err := r.checkErrorVariances(errVars)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.crosses) > 0 {
+		return nil, fmt.Errorf("%w: the method of moments does not correct feature crosses, use SIMEX", ErrIncompatibleOptions)
+	}
+
+	points := r.rawData()
+	n := len(points)
+	x := mat.NewDense(n, raw+1, nil)
+	y := mat.NewVecDense(n, nil)
+	for i, d := range points {
+		x.Set(i, 0, 1)
+		for j, v := range d.Variables {
+			x.Set(i, j+1, v)
+		}
+		y.SetVec(i, d.Observed)
+	}
+	m := mat.NewSymDense(raw+1, nil)
+	m.SymOuterK(1/float64(n), x.T())
+	for j, u := range errVars {
+		m.SetSym(j+1, j+1, m.At(j+1, j+1)-u)
+	}
+	var chol mat.Cholesky
+	if ok := chol.Factorize(m); !ok {
+		return nil, fmt.Errorf("%w: the measurement error variances exceed the variance of the variables", ErrSingular)
+	}
+	var xty, b mat.VecDense
+	xty.MulVec(x.T(), y)
+	xty.ScaleVec(1/float64(n), &xty)
+	if err := chol.SolveVecTo(&b, &xty); err != nil {
+		return nil, err
+	}
+	return &MeasurementErrorFit{
+		Naive:       r.GetCoeffs(),
+		Coeffs:      mat.Col(nil, 0, &b),
+		Reliability: reliability(points, errVars),
+	}, nil
+}
+
+// SIMEX corrects the coefficients for known measurement error variances of the variables by
+// simulation and extrapolation: the model is refitted with extra noise added to the variables, at
+// several multiples λ of the error variances, and the trend of the coefficients in λ extrapolated
+// quadratically back to λ = -1, where there would be no measurement error. Unlike
+// CorrectMeasurementError it accounts for feature crosses of the noisy variables, but the
+// correction is approximate and random.
+func (r *Regression) SIMEX(errVars []float64, opts ...Option) (*MeasurementErrorFit, error) {
+	if _, err := r.checkErrorVariances(errVars); err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+	points := r.rawData()
+	naive := r.GetCoeffs()
+
+	// Fit the mean coefficients at each λ, with λ = 0 the naive fit
+	lambdas := append([]float64{0}, simexLambdas...)
+	means := mat.NewDense(len(lambdas), len(naive), nil)
+	means.SetRow(0, naive)
+	for l, lambda := range simexLambdas {
+		for b := 0; b < simexReplicates; b++ {
+			noisy := make(DataPoints, len(points))
+			for i, d := range points {
+				vars := make([]float64, len(d.Variables))
+				for j, v := range d.Variables {
+					vars[j] = v + o.rand.NormFloat64()*math.Sqrt(lambda*errVars[j])
+				}
+				noisy[i] = &dataPoint{Observed: d.Observed, Variables: vars, Entity: d.Entity, Label: d.Label}
+			}
+			fit, err := r.fitLike(noisy)
+			if err != nil {
+				return nil, fmt.Errorf("simulating measurement error at λ = %v: %w", lambda, err)
+			}
+			for j := range naive {
+				means.Set(l+1, j, means.At(l+1, j)+fit.Coeff(j)/simexReplicates)
+			}
+		}
+	}
+
+	quadratic := mat.NewDense(len(lambdas), 3, nil)
+	for l, lambda := range lambdas {
+		quadratic.SetRow(l, []float64{1, lambda, lambda * lambda})
+	}
+	coeffs := make([]float64, len(naive))
+	for j := range naive {
+		g, err := leastSquares(quadratic, means.ColView(j))
+		if err != nil {
+			return nil, err
+		}
+		coeffs[j] = g[0] - g[1] + g[2]
+	}
+	return &MeasurementErrorFit{Naive: naive, Coeffs: coeffs, Reliability: reliability(points, errVars)}, nil
+}
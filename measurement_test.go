@@ -0,0 +1,78 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// noisyData returns points for y = 1 + 2x + e, where x is observed with error of variance u.
+func noisyData(n int, u float64, rng *rand.Rand) DataPoints {
+	points := make(DataPoints, n)
+	for i := range points {
+		x := rng.NormFloat64()
+		y := 1 + 2*x + 0.1*rng.NormFloat64()
+		points[i] = DataPoint(y, []float64{x + math.Sqrt(u)*rng.NormFloat64()})
+	}
+	return points
+}
+
+func TestCorrectMeasurementError(t *testing.T) {
+	r := new(Regression)
+	r.Train(noisyData(2000, 0.5, rand.New(rand.NewSource(1)))...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// The naive slope is attenuated by the reliability, 1/1.5
+	if slope := r.Coeff(1); math.Abs(slope-4.0/3) > 0.1 {
+		t.Errorf("Expected an attenuated slope near 4/3, got %v", slope)
+	}
+
+	fit, err := r.CorrectMeasurementError([]float64{0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(fit.Coeffs[1]-2) > 0.15 || math.Abs(fit.Coeffs[0]-1) > 0.1 {
+		t.Errorf("Expected corrected coefficients near [1 2], got %v", fit.Coeffs)
+	}
+	if math.Abs(fit.Reliability[0]-2.0/3) > 0.05 {
+		t.Errorf("Expected reliability near 2/3, got %v", fit.Reliability[0])
+	}
+	if fit.Naive[1] != r.Coeff(1) {
+		t.Errorf("Expected the naive slope %v, got %v", r.Coeff(1), fit.Naive[1])
+	}
+
+	if _, err := r.CorrectMeasurementError([]float64{5}); !errors.Is(err, ErrSingular) {
+		t.Errorf("Expected ErrSingular for an error variance above the variance, got %v", err)
+	}
+	if _, err := r.CorrectMeasurementError([]float64{0.5, 0.5}); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for the wrong number of variances, got %v", err)
+	}
+	c := new(Regression)
+	c.AddCross(PowCross(0, 2))
+	c.Train(noisyData(50, 0.5, rand.New(rand.NewSource(2)))...)
+	c.Run()
+	if _, err := c.CorrectMeasurementError([]float64{0.5}); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions with crosses, got %v", err)
+	}
+}
+
+func TestSIMEX(t *testing.T) {
+	r := new(Regression)
+	r.Train(noisyData(500, 0.25, rand.New(rand.NewSource(3)))...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	fit, err := r.SIMEX([]float64{0.25}, WithSeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Quadratic extrapolation removes most but not all of the attenuation
+	if fit.Coeffs[1] <= fit.Naive[1] || math.Abs(fit.Coeffs[1]-2) > 0.2 {
+		t.Errorf("Expected a corrected slope near 2 above the naive %v, got %v", fit.Naive[1], fit.Coeffs[1])
+	}
+	if r.Data[0].Variables[0] != r.rawData()[0].Variables[0] {
+		t.Errorf("Expected the training data to be unchanged")
+	}
+}
@@ -0,0 +1,199 @@
+package regression
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// modulePath is the path of this module, whose version is recorded in the metadata of fitted models.
+const modulePath = "github.com/Synthace/regression"
+
+// FitMetadata records how and when a model was fitted, so that a model used in production can be
+// audited and its fit reproduced. It is encoded with the model by MarshalJSON.
+type FitMetadata struct {
+	// Time is when the model was fitted, in UTC.
+	Time time.Time `json:"time"`
+	// N is the number of data points fitted and K the number of coefficients, including the offset.
+	N int `json:"n"`
+	K int `json:"k"`
+	// Solver is the method used to fit the coefficients, such as "QR" or "censored maximum likelihood".
+	Solver string `json:"solver"`
+	// Options holds the configuration of the regression that differs from the defaults.
+	Options map[string]string `json:"options,omitempty"`
+	// DataHash is the hex SHA-256 hash of the data points fitted, which identifies the training data.
+	DataHash string `json:"data_hash"`
+	// PackageVersion is the version of this module that fitted the model, or "(devel)" when it was built
+	// from source outside a released version.
+	PackageVersion string `json:"package_version"`
+}
+
+// Metadata returns the record of how and when the model was fitted, or nil if it has not been run.
+// The metadata of a model decoded by UnmarshalJSON is that of the original fit.
+func (r *Regression) Metadata() *FitMetadata {
+	return r.metadata
+}
+
+// newMetadata returns the metadata of the current fit.
+func (r *Regression) newMetadata() *FitMetadata {
+	return &FitMetadata{
+		Time:           time.Now().UTC(),
+		N:              len(r.Data),
+		K:              len(r.coeff),
+		Solver:         r.fitMethod(),
+		Options:        r.fitOptions(),
+		DataHash:       hashData(r.Data),
+		PackageVersion: packageVersion(),
+	}
+}
+
+// fitMethod names the method Run used to fit the coefficients, choosing between them as Run does.
+func (r *Regression) fitMethod() string {
+	switch {
+	case r.maximumLikelihood():
+		return "censored maximum likelihood"
+	case r.signConstrained():
+		return "sign constrained least squares"
+	case len(r.constraints) > 0:
+		return "constrained least squares"
+	case r.fixedEffects:
+		return "fixed effects"
+	case len(r.endogenous) > 0 || len(r.instruments) > 0:
+		return "two stage least squares"
+	}
+	return r.solverUsed.String()
+}
+
+// covarianceNames names the covariance estimators in the fit options.
+var covarianceNames = map[CovarianceType]string{
+	Classical: "classical", HC0: "HC0", HC1: "HC1", HC2: "HC2", HC3: "HC3", ClusterRobust: "cluster robust",
+}
+
+// fitOptions returns the configuration of the regression that differs from the defaults.
+func (r *Regression) fitOptions() map[string]string {
+	options := make(map[string]string)
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	if r.solver != SolverQR {
+		options["solver"] = r.solver.String()
+	}
+	if r.covType != Classical {
+		options["covariance"] = covarianceNames[r.covType]
+	}
+	if r.collinearityTol != 0 {
+		options["collinearity_tolerance"] = format(r.collinearityTol)
+	}
+	flags := []struct {
+		name string
+		set  bool
+	}{
+		{"drop_constant", r.dropConstant},
+		{"internal_scaling", r.internalScaling},
+		{"legacy_statistics", r.legacyStatistics},
+		{"fixed_effects", r.fixedEffects},
+		{"variance_model", r.varianceModel},
+	}
+	for _, f := range flags {
+		if f.set {
+			options[f.name] = "true"
+		}
+	}
+	if len(r.endogenous) > 0 {
+		options["endogenous"] = fmt.Sprint(r.endogenous)
+	}
+	if len(r.instruments) > 0 {
+		options["instruments"] = fmt.Sprint(r.instruments)
+	}
+	if len(r.constraints) > 0 {
+		options["constraints"] = strconv.Itoa(len(r.constraints))
+	}
+	if len(r.signs) > 0 {
+		vars := make([]int, 0, len(r.signs))
+		for i := range r.signs {
+			vars = append(vars, i)
+		}
+		sort.Ints(vars)
+		var signs []string
+		for _, i := range vars {
+			switch r.signs[i] {
+			case NonNegative:
+				signs = append(signs, fmt.Sprintf("%d>=0", i))
+			case NonPositive:
+				signs = append(signs, fmt.Sprintf("%d<=0", i))
+			}
+		}
+		if signs != nil {
+			options["coefficient_signs"] = fmt.Sprint(signs)
+		}
+	}
+	if t := r.truncation; t != nil {
+		options["truncation"] = fmt.Sprintf("[%s, %s]", format(t.lower), format(t.upper))
+	}
+	if c := r.convergence; c != (Convergence{}) {
+		options["convergence"] = fmt.Sprintf("max_iterations=%d tolerance=%s step_size=%s", c.MaxIterations, format(c.Tolerance), format(c.StepSize))
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
+
+// hashData returns the hex SHA-256 hash of the observed values, variables, entities, labels and
+// censoring of the data points, in order.
+func hashData(data []*dataPoint) string {
+	h := sha256.New()
+	buf := make([]byte, 8)
+	putFloat := func(f float64) {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(f))
+		h.Write(buf)
+	}
+	putString := func(s string) {
+		binary.LittleEndian.PutUint64(buf, uint64(len(s)))
+		h.Write(buf)
+		h.Write([]byte(s))
+	}
+	for _, d := range data {
+		putFloat(d.Observed)
+		binary.LittleEndian.PutUint64(buf, uint64(len(d.Variables)))
+		h.Write(buf)
+		for _, v := range d.Variables {
+			putFloat(v)
+		}
+		putString(d.Entity)
+		putString(d.Label)
+		if c := d.censored; c != nil {
+			putFloat(c.lower)
+			putFloat(c.upper)
+		} else {
+			putFloat(math.NaN())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// packageVersion returns the version of this module in the running binary, from its build information.
+func packageVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
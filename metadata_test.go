@@ -0,0 +1,69 @@
+package regression
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMetadata(t *testing.T) {
+	fit := func(last float64, configure func(r *Regression)) *Regression {
+		r := new(Regression)
+		for x := 0.0; x < 9; x++ {
+			r.Train(DataPoint(1+2*x+float64(int(x)%3), []float64{x, x * x}))
+		}
+		r.Train(DataPoint(last, []float64{9, 81}))
+		if configure != nil {
+			configure(r)
+		}
+		if r.Metadata() != nil {
+			t.Error("Expected no metadata before Run")
+		}
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	before := time.Now().UTC()
+	r := fit(19, nil)
+	m := r.Metadata()
+	if m.N != 10 || m.K != 3 || m.Solver != "QR" || m.Options != nil {
+		t.Errorf("Expected 10 points, 3 coefficients by QR with default options, got %+v", m)
+	}
+	if m.Time.Before(before) || m.Time.After(time.Now().UTC()) || m.PackageVersion == "" {
+		t.Errorf("Expected the time of the fit and a package version, got %v and %q", m.Time, m.PackageVersion)
+	}
+	if same := fit(19, nil).Metadata(); same.DataHash != m.DataHash || len(m.DataHash) != 64 {
+		t.Errorf("Expected the same data to hash alike, got %s and %s", m.DataHash, same.DataHash)
+	}
+	if other := fit(20, nil).Metadata(); other.DataHash == m.DataHash {
+		t.Error("Expected different data to hash differently")
+	}
+
+	signed := fit(19, func(r *Regression) {
+		r.SetCoeffSign(1, NonPositive)
+		r.SetCollinearityTolerance(1e-6)
+	}).Metadata()
+	if signed.Solver != "sign constrained least squares" || signed.Options["coefficient_signs"] != "[1<=0]" || signed.Options["collinearity_tolerance"] != "1e-06" {
+		t.Errorf("Expected a sign constrained fit recorded with its signs, got %+v", signed)
+	}
+
+	// The metadata is encoded with the model and refreshed by updates
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Regression
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if d := decoded.Metadata(); d == nil || d.DataHash != m.DataHash || !d.Time.Equal(m.Time) {
+		t.Errorf("Expected the metadata to survive encoding, got %+v", d)
+	}
+	if err := r.Update(DataPoint(21, []float64{10, 100})); err != nil {
+		t.Fatal(err)
+	}
+	if u := r.Metadata(); u.N != 11 || u.DataHash == m.DataHash {
+		t.Errorf("Expected the update to refresh the metadata, got %+v", u)
+	}
+}
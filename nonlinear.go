@@ -0,0 +1,609 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// nonlinearMaxIterations is the default largest number of Levenberg-Marquardt steps taken by
+// Nonlinear.Run, and nonlinearTolerance the default relative change in the residual sum of squares at
+// which it has converged.
+const (
+	nonlinearMaxIterations = 200
+	nonlinearTolerance     = 1e-10
+)
+
+// nonlinearStartSamples is the number of points drawn within the bounds of the parameters, for a model
+// with no starting values of its own, from which Nonlinear.Run starts at the one that fits best.
+const nonlinearStartSamples = 200
+
+// Weighting selects the weights of the data points in a nonlinear fit as a function of their observed
+// value, for errors whose variance grows with the response.
+type Weighting int
+
+const (
+	// UnitWeighting weights every data point equally, for errors of constant variance.
+	UnitWeighting Weighting = iota
+	// InverseWeighting weights each data point by 1/y, for errors whose variance is proportional to
+	// the response, such as counts.
+	InverseWeighting
+	// InverseSquareWeighting weights each data point by 1/y², for errors whose standard deviation is
+	// proportional to the response, that is a constant coefficient of variation.
+	InverseSquareWeighting
+)
+
+// String returns the name of the weighting.
+func (w Weighting) String() string {
+	switch w {
+	case UnitWeighting:
+		return "1"
+	case InverseWeighting:
+		return "1/y"
+	case InverseSquareWeighting:
+		return "1/y²"
+	}
+	return "unknown"
+}
+
+// NonlinearModel is a model whose prediction is a nonlinear function of its parameters.
+type NonlinearModel struct {
+	Name string
+	// Params names the parameters of the model.
+	Params []string
+	// Func returns the prediction for the variables x given the parameters theta.
+	Func func(x, theta []float64) float64
+	// Start returns starting values of the parameters estimated from the training data. It is used
+	// when no starting values are set with SetStart and may be nil.
+	Start func(data DataPoints) []float64
+	// EC50 returns the variable at which the response is halfway between its asymptotes given the
+	// parameters, for dose-response models, and is nil for other models.
+	EC50 func(theta []float64) float64
+}
+
+// Nonlinear fits a NonlinearModel to data points by least squares, using the Levenberg-Marquardt
+// algorithm with a finite difference Jacobian. It is trained with the same data points as Regression
+// and implements Predictor.
+type Nonlinear struct {
+	Data []*dataPoint
+
+	model       NonlinearModel
+	start       []float64
+	weights     []float64
+	weighting   Weighting
+	w           []float64
+	bounds      [][2]float64
+	restarts    int
+	opts        []Option
+	convergence Convergence
+	validation  float64
+	patience    int
+	held        []float64
+	stopped     int
+	progress    func(Progress)
+	hasRun      bool
+	params      []float64
+	cov         *mat.SymDense
+	residualSS  float64
+	residualDF  int
+	iterations  int
+}
+
+// NewNonlinear creates a nonlinear least squares fit of the model.
+func NewNonlinear(model NonlinearModel) *Nonlinear {
+	return &Nonlinear{model: model}
+}
+
+// SetStart sets the starting values of the parameters, in place of those estimated by the model.
+func (n *Nonlinear) SetStart(theta []float64) {
+	n.start = append([]float64(nil), theta...)
+}
+
+// SetBounds sets a [low, high] range of plausible values for each parameter. The fit is not constrained
+// to the ranges, which set where restarts are drawn from and, for a model with no starting values of
+// its own, where Run searches for them. Values are drawn uniformly within a range, or uniformly in its
+// logarithm for a positive range spanning more than two orders of magnitude, such as a rate constant.
+func (n *Nonlinear) SetBounds(bounds [][2]float64) {
+	n.bounds = append([][2]float64(nil), bounds...)
+}
+
+// SetRestarts makes Run fit the model again from the given number of random starting values within the
+// bounds set by SetBounds, keeping the fit with the smallest residual sum of squares, so that it does not
+// settle in a poor local minimum. The options set the source of the random starting values.
+func (n *Nonlinear) SetRestarts(restarts int, opts ...Option) {
+	n.restarts = restarts
+	n.opts = append(n.opts, opts...)
+}
+
+// SetEarlyStopping holds out a random fraction of the data points for validation and stops the fit at
+// the iteration with the smallest validation error, once it has not fallen for patience iterations, so
+// that a flexible model is not fitted to the noise of the data. The held out points are left out of the
+// fit and its residual statistics. With restarts the fit with the smallest validation error is kept. The
+// options set the source of the random hold out, as for SetRestarts.
+func (n *Nonlinear) SetEarlyStopping(fraction float64, patience int, opts ...Option) {
+	n.validation, n.patience = fraction, patience
+	n.opts = append(n.opts, opts...)
+}
+
+// SetConvergence sets when the Levenberg-Marquardt iterations stop, by default after 200 iterations or
+// a relative change of 1e-10 in the residual sum of squares.
+func (n *Nonlinear) SetConvergence(c Convergence) {
+	n.convergence = c
+}
+
+// SetWeights sets a positive weight for each data point, in the order they are trained, so that Run
+// minimizes the weighted sum of squares Σwᵢ(yᵢ - f(xᵢ))². Weights inversely proportional to the
+// variance of each observation give the most precise fit. They multiply any weights set by SetWeighting.
+func (n *Nonlinear) SetWeights(weights []float64) {
+	n.weights = append([]float64(nil), weights...)
+}
+
+// SetWeighting sets weights for the data points from their observed values, such as 1/y² for assays
+// whose errors have a constant coefficient of variation. The observed values must then be positive.
+func (n *Nonlinear) SetWeighting(w Weighting) {
+	n.weighting = w
+}
+
+// pointWeights returns the weight of each data point, combining the weights set with SetWeights and
+// SetWeighting.
+func (n *Nonlinear) pointWeights() ([]float64, error) {
+	if n.weights != nil && len(n.weights) != len(n.Data) {
+		return nil, fmt.Errorf("%w: %d weights for %d data points", ErrDesign, len(n.weights), len(n.Data))
+	}
+	w := make([]float64, len(n.Data))
+	for i, d := range n.Data {
+		w[i] = 1
+		if n.weights != nil {
+			w[i] = n.weights[i]
+		}
+		switch n.weighting {
+		case InverseWeighting:
+			w[i] /= d.Observed
+		case InverseSquareWeighting:
+			w[i] /= d.Observed * d.Observed
+		}
+		if !(w[i] > 0) || math.IsInf(w[i], 0) {
+			return nil, &DataPointError{Index: i, Err: fmt.Errorf("%w: weight %v, weighted by %v of observed value %v", ErrDesign, w[i], n.weighting, d.Observed)}
+		}
+	}
+	return w, nil
+}
+
+// Weights returns the weight of each data point in the fit.
+func (n *Nonlinear) Weights() []float64 {
+	return append([]float64(nil), n.w...)
+}
+
+// Train adds data points to the training set.
+func (n *Nonlinear) Train(d ...*dataPoint) {
+	n.Data = append(n.Data, d...)
+}
+
+// Run fits the parameters of the model to the training data, from the starting values set with
+// SetStart, else those estimated by the model, else the best of a random sample within the bounds set
+// with SetBounds, and from any restarts set with SetRestarts. ErrNotConverged is returned if the
+// residual sum of squares is still falling after the maximum number of iterations from every start.
+func (n *Nonlinear) Run() (err error) {
+	defer func(start time.Time) {
+		instrumentFit("nonlinear", start, err)
+	}(time.Now())
+	if n.hasRun {
+		return ErrRegressionRun
+	}
+	k := len(n.model.Params)
+	if len(n.Data) <= k {
+		return fmt.Errorf("%w: %d data points for %d parameters", ErrNotEnoughData, len(n.Data), k)
+	}
+	for i, d := range n.Data {
+		if !d.finite() {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: NaN or infinite value", ErrDesign)}
+		}
+	}
+	if n.bounds != nil {
+		if len(n.bounds) != k {
+			return fmt.Errorf("%w: %d bounds for %d parameters", ErrDesign, len(n.bounds), k)
+		}
+		for j, b := range n.bounds {
+			if !(b[0] <= b[1]) || math.IsInf(b[0], 0) || math.IsInf(b[1], 0) {
+				return fmt.Errorf("%w: bounds [%v, %v] of parameter %s", ErrDesign, b[0], b[1], n.model.Params[j])
+			}
+		}
+	} else if n.restarts > 0 {
+		return fmt.Errorf("%w: restarts need bounds for the parameters", ErrDesign)
+	}
+	w, err := n.pointWeights()
+	if err != nil {
+		return err
+	}
+	n.w = w
+
+	rng := newOptions(n.opts).rand
+	fitted := len(n.Data)
+	if n.validation != 0 {
+		held := int(math.Round(n.validation * float64(len(n.Data))))
+		switch {
+		case !(n.validation > 0 && n.validation < 1) || n.patience < 1:
+			return fmt.Errorf("%w: early stopping on a validation fraction %v with patience %d", ErrDesign, n.validation, n.patience)
+		case held < 1 || len(n.Data)-held <= k:
+			return fmt.Errorf("%w: holding out %d of %d data points for %d parameters", ErrNotEnoughData, held, len(n.Data), k)
+		}
+		// Hold out points by moving their weights out of the fit
+		n.held = make([]float64, len(n.Data))
+		for _, i := range rng.Perm(len(n.Data))[:held] {
+			n.held[i], n.w[i] = n.w[i], 0
+		}
+		fitted -= held
+	}
+	theta := n.start
+	switch {
+	case theta != nil:
+	case n.model.Start != nil:
+		theta = n.model.Start(n.Data)
+	case n.bounds != nil:
+		// Start from the best of a sample of points within the bounds
+		best := math.Inf(1)
+		for s := 0; s < nonlinearStartSamples; s++ {
+			sample := n.drawParams(rng)
+			if sse := n.sumOfSquares(sample); sse < best {
+				theta, best = sample, sse
+			}
+		}
+	}
+	if len(theta) != k {
+		return fmt.Errorf("%w: %d starting values for %d parameters", ErrDesign, len(theta), k)
+	}
+	starts := [][]float64{append([]float64(nil), theta...)}
+	for s := 0; s < n.restarts; s++ {
+		starts = append(starts, n.drawParams(rng))
+	}
+
+	// Fit from each starting value, keeping the converged fit with the smallest sum of squares, or the
+	// smallest validation error when stopping early
+	sse, score := math.Inf(1), math.Inf(1)
+	err = fmt.Errorf("%w: the model is not finite at the starting values", ErrDesign)
+	for _, start := range starts {
+		if s := n.sumOfSquares(start); math.IsInf(s, 0) {
+			continue
+		}
+		var stop func(int, []float64) bool
+		var best []float64
+		var bestIteration int
+		smallest := math.Inf(1)
+		if n.held != nil {
+			// Keep the iterate with the smallest validation error, stopping when it has not improved for patience iterations
+			stop = func(iteration int, theta []float64) bool {
+				if v := n.validationSS(theta); v < smallest {
+					smallest, best, bestIteration = v, theta, iteration
+				}
+				return iteration-bestIteration >= n.patience
+			}
+		}
+		result, s, iterations, converged := n.minimize(start, -1, stop)
+		if !converged {
+			if math.IsInf(score, 0) {
+				err = fmt.Errorf("%w: after %d iterations", ErrNotConverged, iterations)
+			}
+			continue
+		}
+		v := s
+		if best != nil {
+			result, s, v = best, n.sumOfSquares(best), smallest
+		}
+		if v < score {
+			theta, sse, score, n.iterations, n.stopped, err = result, s, v, iterations, bestIteration, nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	n.params, n.residualSS, n.residualDF = theta, sse, fitted-k
+	jac, _ := n.jacobian(theta)
+	if inv, err := crossProductInverse(jac); err == nil {
+		inv.ScaleSym(sse/float64(n.residualDF), inv)
+		n.cov = inv
+	}
+	n.hasRun = true
+	for _, d := range n.Data {
+		d.Predicted = n.model.Func(d.Variables, theta)
+		d.Error = d.Observed - d.Predicted
+	}
+	return nil
+}
+
+// drawParams returns parameters drawn at random within their bounds, uniformly in the logarithm of
+// positive bounds spanning more than two orders of magnitude.
+func (n *Nonlinear) drawParams(rng *rand.Rand) []float64 {
+	theta := make([]float64, len(n.bounds))
+	for j, b := range n.bounds {
+		if b[0] > 0 && b[1] > 100*b[0] {
+			theta[j] = math.Exp(math.Log(b[0]) + rng.Float64()*math.Log(b[1]/b[0]))
+		} else {
+			theta[j] = b[0] + rng.Float64()*(b[1]-b[0])
+		}
+	}
+	return theta
+}
+
+// minimize runs the Levenberg-Marquardt algorithm from theta, holding parameter fixed at its starting
+// value unless fixed is -1, and returns the parameters, their sum of squares, the number of iterations
+// and whether they converged. If stop is not nil it is called with the starting values and each
+// iterate, and the fit ends, as converged, when it returns true.
+func (n *Nonlinear) minimize(theta []float64, fixed int, stop func(iteration int, theta []float64) bool) ([]float64, float64, int, bool) {
+	k := len(theta)
+	sse := n.sumOfSquares(theta)
+	lambda := 1e-3
+	converged := false
+	iterations := 0
+	if stop != nil && stop(0, theta) {
+		return theta, sse, 0, true
+	}
+	maxIterations, tolerance := n.convergence.maxIterations(nonlinearMaxIterations), n.convergence.tolerance(nonlinearTolerance)
+	for ; iterations < maxIterations && !converged; iterations++ {
+		jac, res := n.jacobian(theta)
+		var jtj mat.SymDense
+		jtj.SymOuterK(1, jac.T())
+		var jtr mat.VecDense
+		jtr.MulVec(jac.T(), res)
+
+		// Raise the damping until a step reduces the residual sum of squares
+		improved := false
+		for !improved && lambda < 1e12 {
+			damped := mat.NewSymDense(k, nil)
+			damped.CopySym(&jtj)
+			for j := 0; j < k; j++ {
+				damped.SetSym(j, j, jtj.At(j, j)*(1+lambda)+1e-12)
+			}
+			if fixed >= 0 {
+				// Decouple the fixed parameter so that its step is zero
+				for j := 0; j < k; j++ {
+					damped.SetSym(fixed, j, 0)
+				}
+				damped.SetSym(fixed, fixed, 1)
+				jtr.SetVec(fixed, 0)
+			}
+			var chol mat.Cholesky
+			var step mat.VecDense
+			if chol.Factorize(damped) && chol.SolveVecTo(&step, &jtr) == nil {
+				delta := append([]float64(nil), step.RawVector().Data...)
+				n.convergence.limitStep(theta, delta)
+				next := make([]float64, k)
+				for j := range next {
+					next[j] = theta[j] + delta[j]
+				}
+				if s := n.sumOfSquares(next); s < sse {
+					converged = (sse - s) <= tolerance*(sse+tolerance)
+					theta, sse, improved = next, s, true
+					lambda /= 10
+					break
+				}
+			}
+			lambda *= 10
+		}
+		if !improved {
+			// No step reduces the residuals, so theta is a minimum to working precision
+			converged = true
+		}
+		if n.progress != nil && fixed < 0 {
+			n.progress(Progress{Stage: "fit", Rows: len(n.Data), Iteration: iterations + 1, Loss: sse})
+		}
+		if stop != nil && stop(iterations+1, theta) {
+			converged = true
+		}
+	}
+	return theta, sse, iterations, converged
+}
+
+// sumOfSquares returns the weighted residual sum of squares of the training data at theta.
+func (n *Nonlinear) sumOfSquares(theta []float64) float64 {
+	var sse float64
+	for i, d := range n.Data {
+		e := d.Observed - n.model.Func(d.Variables, theta)
+		sse += n.w[i] * e * e
+	}
+	if math.IsNaN(sse) {
+		return math.Inf(1)
+	}
+	return sse
+}
+
+// validationSS returns the weighted sum of squares of the data points held out for early stopping at theta.
+func (n *Nonlinear) validationSS(theta []float64) float64 {
+	var sse float64
+	for i, d := range n.Data {
+		if n.held[i] > 0 {
+			e := d.Observed - n.model.Func(d.Variables, theta)
+			sse += n.held[i] * e * e
+		}
+	}
+	return sse
+}
+
+// jacobian returns the derivatives of the model at each data point with respect to the parameters,
+// by central differences, and the residuals, each row scaled by the square root of the weight of the
+// data point so that the weighted fit is an unweighted fit of the scaled rows.
+func (n *Nonlinear) jacobian(theta []float64) (*mat.Dense, *mat.VecDense) {
+	k := len(theta)
+	jac := mat.NewDense(len(n.Data), k, nil)
+	res := mat.NewVecDense(len(n.Data), nil)
+	shifted := append([]float64(nil), theta...)
+	for i, d := range n.Data {
+		sw := math.Sqrt(n.w[i])
+		res.SetVec(i, sw*(d.Observed-n.model.Func(d.Variables, theta)))
+		for j := range theta {
+			h := 1e-6 * math.Max(math.Abs(theta[j]), 1e-3)
+			shifted[j] = theta[j] + h
+			up := n.model.Func(d.Variables, shifted)
+			shifted[j] = theta[j] - h
+			down := n.model.Func(d.Variables, shifted)
+			shifted[j] = theta[j]
+			jac.Set(i, j, sw*(up-down)/(2*h))
+		}
+	}
+	return jac, res
+}
+
+// Predict returns the prediction of the fitted model for vars.
+func (n *Nonlinear) Predict(vars []float64) (float64, error) {
+	if !n.hasRun {
+		return 0, ErrRegressionNotRun
+	}
+	if len(vars) != len(n.Data[0].Variables) {
+		return 0, fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), len(n.Data[0].Variables))
+	}
+	return n.model.Func(vars, n.params), nil
+}
+
+// Model returns the model being fitted.
+func (n *Nonlinear) Model() NonlinearModel {
+	return n.model
+}
+
+// Params returns the fitted parameters.
+func (n *Nonlinear) Params() []float64 {
+	return append([]float64(nil), n.params...)
+}
+
+// Param returns the fitted value of parameter i.
+func (n *Nonlinear) Param(i int) float64 {
+	return n.params[i]
+}
+
+// StdErr returns the asymptotic standard error of parameter i, or NaN if it is unavailable.
+func (n *Nonlinear) StdErr(i int) float64 {
+	if n.cov == nil {
+		return math.NaN()
+	}
+	return math.Sqrt(n.cov.At(i, i))
+}
+
+// ConfInt returns the Wald confidence interval of parameter i at the given level, from its standard
+// error and the t distribution with the residual degrees of freedom.
+func (n *Nonlinear) ConfInt(i int, level float64) (lower, upper float64) {
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(n.residualDF)}.Quantile((1 + level) / 2)
+	return n.params[i] - t*n.StdErr(i), n.params[i] + t*n.StdErr(i)
+}
+
+// profileSteps is the largest number of steps, each half a standard error, that ProfileConfInt takes
+// away from the estimate in search of each end of the interval.
+const profileSteps = 50
+
+// ProfileConfInt returns the profile likelihood confidence interval of parameter i at the given level:
+// the values of the parameter for which the residual sum of squares, minimized over the other
+// parameters, exceeds that of the fit by no more than σ²t², for the t quantile with the residual degrees
+// of freedom. Unlike the Wald interval of ConfInt it follows the curvature of the model, so it is
+// asymmetric about the estimate where the model is, as for the EC50 of a logistic curve. An end is
+// infinite if the sum of squares does not rise far enough within 50 half standard errors of the estimate.
+func (n *Nonlinear) ProfileConfInt(i int, level float64) (lower, upper float64, err error) {
+	if !n.hasRun {
+		return 0, 0, ErrRegressionNotRun
+	}
+	if !(level > 0 && level < 1) {
+		return 0, 0, fmt.Errorf("%w: confidence level %v", ErrSignificance, level)
+	}
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(n.residualDF)}.Quantile((1 + level) / 2)
+	threshold := n.residualSS + n.Sigma()*n.Sigma()*t*t
+	step := n.StdErr(i) / 2
+	if !(step > 0) || math.IsInf(step, 0) {
+		step = 0.01 * math.Max(math.Abs(n.params[i]), 1)
+	}
+
+	// profile returns the minimized sum of squares with parameter i fixed at v, starting from theta
+	profile := func(theta []float64, v float64) ([]float64, float64) {
+		theta = append([]float64(nil), theta...)
+		theta[i] = v
+		theta, sse, _, _ := n.minimize(theta, i, nil)
+		return theta, sse
+	}
+	end := func(direction float64) float64 {
+		inside, theta := n.params[i], n.params
+		for s := 1; s <= profileSteps; s++ {
+			v := n.params[i] + direction*float64(s)*step
+			next, sse := profile(theta, v)
+			if sse <= threshold {
+				inside, theta = v, next
+				continue
+			}
+			// Bisect between the last value inside the interval and the first outside it
+			outside := v
+			for b := 0; b < 50 && math.Abs(outside-inside) > 1e-10*math.Max(math.Abs(inside), 1); b++ {
+				mid := (inside + outside) / 2
+				if next, sse := profile(theta, mid); sse <= threshold {
+					inside, theta = mid, next
+				} else {
+					outside = mid
+				}
+			}
+			return (inside + outside) / 2
+		}
+		return direction * math.Inf(1)
+	}
+	return end(-1), end(1), nil
+}
+
+// ResidualSS returns the residual sum of squares of the fit, weighted if weights were set.
+func (n *Nonlinear) ResidualSS() float64 {
+	return n.residualSS
+}
+
+// ResidualDF returns the residual degrees of freedom, the number of data points less the number of parameters.
+func (n *Nonlinear) ResidualDF() int {
+	return n.residualDF
+}
+
+// Sigma returns the residual standard error. For a weighted fit it is the standard deviation of an
+// observation of unit weight, so that of observation i is Sigma/√wᵢ.
+func (n *Nonlinear) Sigma() float64 {
+	return math.Sqrt(n.residualSS / float64(n.residualDF))
+}
+
+// Iterations returns the number of Levenberg-Marquardt iterations taken by Run.
+func (n *Nonlinear) Iterations() int {
+	return n.iterations
+}
+
+// StoppedAt returns the iteration selected by early stopping, that with the smallest validation error,
+// or 0 if early stopping was not set.
+func (n *Nonlinear) StoppedAt() int {
+	return n.stopped
+}
+
+// Converged reports whether the fit converged. Run returns ErrNotConverged rather than keep a fit that
+// has not, so it is true once Run has succeeded.
+func (n *Nonlinear) Converged() bool {
+	return n.hasRun
+}
+
+// predictionVariance returns the variance of the fitted mean at vars, gᵀVg for the gradient g of the
+// model with respect to the parameters and their covariance V.
+func (n *Nonlinear) predictionVariance(vars []float64) float64 {
+	return n.deltaVariance(func(theta []float64) float64 {
+		return n.model.Func(vars, theta)
+	})
+}
+
+// deltaVariance returns the variance of a function of the fitted parameters by the delta method,
+// gᵀVg for the gradient g of the function by central differences and the covariance V.
+func (n *Nonlinear) deltaVariance(f func(theta []float64) float64) float64 {
+	if n.cov == nil {
+		return math.NaN()
+	}
+	k := len(n.params)
+	g := make([]float64, k)
+	shifted := append([]float64(nil), n.params...)
+	for j, p := range n.params {
+		h := 1e-6 * math.Max(math.Abs(p), 1e-3)
+		shifted[j] = p + h
+		up := f(shifted)
+		shifted[j] = p - h
+		down := f(shifted)
+		shifted[j] = p
+		g[j] = (up - down) / (2 * h)
+	}
+	gv := mat.NewVecDense(k, g)
+	return mat.Inner(gv, n.cov, gv)
+}
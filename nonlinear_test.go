@@ -0,0 +1,301 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// logisticData samples a 4PL curve with a = 0.1, b = 1.2, c = 50 and d = 2.5 at duplicate doses.
+func logisticData(noise float64, rng *rand.Rand) DataPoints {
+	var data DataPoints
+	for _, x := range []float64{0, 1, 3, 10, 30, 100, 300, 1000} {
+		for rep := 0; rep < 2; rep++ {
+			y := 2.5 + (0.1-2.5)/(1+math.Pow(x/50, 1.2))
+			data = append(data, DataPoint(y+noise*rng.NormFloat64(), []float64{x}))
+		}
+	}
+	return data
+}
+
+func TestNonlinear(t *testing.T) {
+	n := NewNonlinear(FourParameterLogistic())
+	n.Train(logisticData(0.02, rand.New(rand.NewSource(1)))...)
+	if _, err := n.Predict([]float64{1}); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := n.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{0.1, 1.2, 50, 2.5} {
+		if math.Abs(n.Param(i)-want) > 3*n.StdErr(i) {
+			t.Errorf("Expected %s near %v, got %.4f ± %.4f", n.Model().Params[i], want, n.Param(i), n.StdErr(i))
+		}
+	}
+	if n.ResidualDF() != 12 || math.Abs(n.Sigma()-0.02) > 0.01 {
+		t.Errorf("Expected 12 residual degrees of freedom and σ near 0.02, got %d and %v", n.ResidualDF(), n.Sigma())
+	}
+	if lower, upper := n.ConfInt(2, 0.95); !(lower < 50 && 50 < upper) {
+		t.Errorf("Expected the 95%% interval of c to contain 50, got [%v, %v]", lower, upper)
+	}
+
+	// A custom model from explicit starting values
+	decay := NonlinearModel{
+		Name:   "exponential decay",
+		Params: []string{"y0", "k"},
+		Func: func(x, theta []float64) float64 {
+			return theta[0] * math.Exp(-theta[1]*x[0])
+		},
+	}
+	exact := NewNonlinear(decay)
+	for x := 0.0; x < 5; x += 0.5 {
+		exact.Train(DataPoint(3*math.Exp(-0.7*x), []float64{x}))
+	}
+	if err := exact.Run(); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign without starting values, got %v", err)
+	}
+	exact.SetStart([]float64{1, 0.1})
+	if err := exact.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(exact.Param(0)-3) > 1e-6 || math.Abs(exact.Param(1)-0.7) > 1e-6 {
+		t.Errorf("Expected y0 = 3 and k = 0.7, got %v", exact.Params())
+	}
+}
+
+func TestWeightedNonlinear(t *testing.T) {
+	// Rates with a 10% coefficient of variation, spanning two orders of magnitude
+	rng := rand.New(rand.NewSource(2))
+	var data DataPoints
+	for _, s := range []float64{0.1, 0.2, 0.5, 1, 2, 5, 10, 20, 50} {
+		for rep := 0; rep < 4; rep++ {
+			data = append(data, DataPoint(10*s/(2+s)*(1+0.1*rng.NormFloat64()), []float64{s}))
+		}
+	}
+	weighted := NewNonlinear(MichaelisMenten())
+	weighted.Train(data...)
+	weighted.SetWeighting(InverseSquareWeighting)
+	if err := weighted.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(weighted.Sigma()-0.1) > 0.03 {
+		t.Errorf("Expected the coefficient of variation 0.1 as the unit weight σ, got %v", weighted.Sigma())
+	}
+	if math.Abs(weighted.Param(1)-2) > 3*weighted.StdErr(1) {
+		t.Errorf("Expected Km near 2, got %v ± %v", weighted.Param(1), weighted.StdErr(1))
+	}
+
+	// The same weights given explicitly give the same fit
+	explicit := NewNonlinear(MichaelisMenten())
+	explicit.Train(data...)
+	w := make([]float64, len(data))
+	for i, d := range data {
+		w[i] = 1 / (d.Observed * d.Observed)
+	}
+	explicit.SetWeights(w)
+	if err := explicit.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(explicit.Param(0)-weighted.Param(0)) > 1e-6 || math.Abs(explicit.Weights()[3]-weighted.Weights()[3]) > 1e-12 {
+		t.Errorf("Expected explicit weights to match 1/y² weighting, got %v and %v", explicit.Params(), weighted.Params())
+	}
+
+	short := NewNonlinear(MichaelisMenten())
+	short.Train(data...)
+	short.SetWeights(w[1:])
+	if err := short.Run(); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for too few weights, got %v", err)
+	}
+	zero := NewNonlinear(MichaelisMenten())
+	zero.Train(append(data, DataPoint(0, []float64{0}))...)
+	zero.SetWeighting(InverseWeighting)
+	var pointErr *DataPointError
+	if err := zero.Run(); !errors.As(err, &pointErr) || pointErr.Index != len(data) {
+		t.Errorf("Expected a DataPointError for the zero rate, got %v", err)
+	}
+}
+
+func TestProfileConfInt(t *testing.T) {
+	n := NewNonlinear(FourParameterLogistic())
+	n.Train(logisticData(0.02, rand.New(rand.NewSource(1)))...)
+	if _, _, err := n.ProfileConfInt(2, 0.95); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := n.Run(); err != nil {
+		t.Fatal(err)
+	}
+	lower, upper, err := n.ProfileConfInt(2, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(lower < 50 && 50 < upper) {
+		t.Errorf("Expected the 95%% profile interval of c to contain 50, got [%v, %v]", lower, upper)
+	}
+	// The EC50 is better determined below the estimate than above it
+	c := n.Param(2)
+	if !(upper-c > c-lower) {
+		t.Errorf("Expected the profile interval of c to extend further above %v, got [%v, %v]", c, lower, upper)
+	}
+	if _, _, err := n.ProfileConfInt(2, 1); !errors.Is(err, ErrSignificance) {
+		t.Errorf("Expected ErrSignificance, got %v", err)
+	}
+
+	// For a model linear in its parameters the profile and Wald intervals agree
+	line := NewNonlinear(NonlinearModel{
+		Name:   "line",
+		Params: []string{"a", "b"},
+		Func: func(x, theta []float64) float64 {
+			return theta[0] + theta[1]*x[0]
+		},
+	})
+	line.SetStart([]float64{0, 0})
+	rng := rand.New(rand.NewSource(4))
+	for x := 0.0; x < 10; x++ {
+		line.Train(DataPoint(1+2*x+0.5*rng.NormFloat64(), []float64{x}))
+	}
+	if err := line.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		waldLower, waldUpper := line.ConfInt(i, 0.9)
+		profileLower, profileUpper, err := line.ProfileConfInt(i, 0.9)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(waldLower-profileLower) > 1e-4 || math.Abs(waldUpper-profileUpper) > 1e-4 {
+			t.Errorf("Expected the profile interval of %s to match [%v, %v], got [%v, %v]", line.Model().Params[i], waldLower, waldUpper, profileLower, profileUpper)
+		}
+	}
+}
+
+func TestNonlinearRestarts(t *testing.T) {
+	// The sum of squares of a frequency has many local minima
+	wave := NonlinearModel{
+		Name:   "sine",
+		Params: []string{"amplitude", "frequency"},
+		Func: func(x, theta []float64) float64 {
+			return theta[0] * math.Sin(theta[1]*x[0])
+		},
+	}
+	rng := rand.New(rand.NewSource(5))
+	var data DataPoints
+	for x := 0.0; x < 10; x += 0.25 {
+		data = append(data, DataPoint(2*math.Sin(3*x)+0.05*rng.NormFloat64(), []float64{x}))
+	}
+	bounds := [][2]float64{{0.5, 5}, {0.1, 5}}
+
+	local := NewNonlinear(wave)
+	local.Train(data...)
+	local.SetStart([]float64{1, 0.5})
+	if err := local.Run(); err != nil {
+		t.Fatal(err)
+	}
+	restarted := NewNonlinear(wave)
+	restarted.Train(data...)
+	restarted.SetStart([]float64{1, 0.5})
+	restarted.SetBounds(bounds)
+	restarted.SetRestarts(20, WithSeed(1))
+	if err := restarted.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(restarted.Param(1)-3) > 0.01 || !(restarted.ResidualSS() < local.ResidualSS()) {
+		t.Errorf("Expected restarts to find frequency 3 below the local minimum at %v, got %v", local.Param(1), restarted.Params())
+	}
+
+	// Without starting values the model starts from the best of a sample within the bounds
+	sampled := NewNonlinear(wave)
+	sampled.Train(data...)
+	sampled.SetBounds(bounds)
+	sampled.SetRestarts(0, WithSeed(2))
+	if err := sampled.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(sampled.Param(1)-3) > 0.01 {
+		t.Errorf("Expected frequency 3 from sampled starting values, got %v", sampled.Params())
+	}
+
+	// With early stopping the restarts are compared on the held out points, so that no start fitted
+	// alone with the same hold out has a smaller validation error
+	stopped := NewNonlinear(wave)
+	stopped.Train(data...)
+	stopped.SetStart([]float64{1, 1})
+	stopped.SetBounds(bounds)
+	stopped.SetEarlyStopping(0.25, 5)
+	stopped.SetRestarts(10, WithSeed(4))
+	if err := stopped.Run(); err != nil {
+		t.Fatal(err)
+	}
+	v := stopped.validationSS(stopped.Params())
+	rng = newOptions([]Option{WithSeed(4)}).rand
+	rng.Perm(len(data))
+	starts := [][]float64{{1, 1}}
+	for s := 0; s < 10; s++ {
+		starts = append(starts, stopped.drawParams(rng))
+	}
+	for _, start := range starts {
+		single := NewNonlinear(wave)
+		single.Train(data...)
+		single.SetStart(start)
+		single.SetEarlyStopping(0.25, 5, WithSeed(4))
+		if err := single.Run(); err != nil {
+			continue
+		}
+		if local := single.validationSS(single.Params()); local < v {
+			t.Errorf("Expected the start %v with validation error %v to be kept over %v", start, local, v)
+		}
+	}
+
+	unbounded := NewNonlinear(wave)
+	unbounded.Train(data...)
+	unbounded.SetStart([]float64{1, 0.5})
+	unbounded.SetRestarts(5)
+	if err := unbounded.Run(); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for restarts without bounds, got %v", err)
+	}
+}
+
+func TestEarlyStopping(t *testing.T) {
+	n := NewNonlinear(FourParameterLogistic())
+	n.Train(logisticData(0.05, rand.New(rand.NewSource(7)))...)
+	n.SetEarlyStopping(0.25, 3, WithSeed(1))
+	if err := n.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if n.ResidualDF() != 8 {
+		t.Errorf("Expected 8 residual degrees of freedom from 12 fitted points, got %d", n.ResidualDF())
+	}
+	held := 0
+	for _, w := range n.Weights() {
+		if w == 0 {
+			held++
+		}
+	}
+	if held != 4 {
+		t.Errorf("Expected 4 points held out, got %d", held)
+	}
+	if n.StoppedAt() < 1 || n.StoppedAt() > n.Iterations() {
+		t.Errorf("Expected to stop at one of the %d iterations, got %d", n.Iterations(), n.StoppedAt())
+	}
+	if math.Abs(n.Param(2)-50) > 3*n.StdErr(2) {
+		t.Errorf("Expected c near 50, got %v ± %v", n.Param(2), n.StdErr(2))
+	}
+
+	for _, c := range []struct {
+		fraction float64
+		patience int
+		err      error
+	}{
+		{1.5, 3, ErrDesign},
+		{0.25, 0, ErrDesign},
+		{0.01, 3, ErrNotEnoughData},
+		{0.8, 3, ErrNotEnoughData},
+	} {
+		bad := NewNonlinear(FourParameterLogistic())
+		bad.Train(logisticData(0.05, rand.New(rand.NewSource(7)))...)
+		bad.SetEarlyStopping(c.fraction, c.patience)
+		if err := bad.Run(); !errors.Is(err, c.err) {
+			t.Errorf("Expected %v for fraction %v and patience %d, got %v", c.err, c.fraction, c.patience, err)
+		}
+	}
+}
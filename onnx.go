@@ -0,0 +1,186 @@
+package regression
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// ONNX protobuf field numbers and enum values used by WriteONNX, from onnx.proto.
+const (
+	onnxIRVersion = 7
+
+	onnxModelIRVersion    = 1
+	onnxModelProducerName = 2
+	onnxModelGraph        = 7
+	onnxModelOpsetImport  = 8
+
+	onnxOpsetDomain  = 1
+	onnxOpsetVersion = 2
+
+	onnxGraphNode   = 1
+	onnxGraphName   = 2
+	onnxGraphInput  = 11
+	onnxGraphOutput = 12
+
+	onnxNodeInput     = 1
+	onnxNodeOutput    = 2
+	onnxNodeName      = 3
+	onnxNodeOpType    = 4
+	onnxNodeAttribute = 5
+	onnxNodeDomain    = 7
+
+	onnxAttributeName   = 1
+	onnxAttributeInt    = 3
+	onnxAttributeString = 4
+	onnxAttributeFloats = 7
+	onnxAttributeType   = 20
+
+	onnxAttributeTypeInt    = 2
+	onnxAttributeTypeString = 3
+	onnxAttributeTypeFloats = 6
+
+	onnxValueInfoName = 1
+	onnxValueInfoType = 2
+
+	onnxTypeTensor      = 1
+	onnxTensorElemType  = 1
+	onnxTensorShape     = 2
+	onnxShapeDim        = 1
+	onnxDimValue        = 1
+	onnxDimParam        = 2
+	onnxTensorElemFloat = 1
+
+	onnxMLDomain           = "ai.onnx.ml"
+	onnxMLDomainVersion    = 1
+	onnxDefaultDomainOpset = 13
+)
+
+// protoBuffer builds a protocol buffers message using the wire format directly.
+type protoBuffer []byte
+
+func (b *protoBuffer) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	*b = append(*b, buf[:n]...)
+}
+
+func (b *protoBuffer) key(field, wireType int) {
+	b.varint(uint64(field<<3 | wireType))
+}
+
+func (b *protoBuffer) int(field int, v int64) {
+	b.key(field, 0)
+	b.varint(uint64(v))
+}
+
+func (b *protoBuffer) bytes(field int, v []byte) {
+	b.key(field, 2)
+	b.varint(uint64(len(v)))
+	*b = append(*b, v...)
+}
+
+func (b *protoBuffer) string(field int, v string) {
+	b.bytes(field, []byte(v))
+}
+
+func (b *protoBuffer) float(field int, v float32) {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+	b.key(field, 5)
+	*b = append(*b, buf[:]...)
+}
+
+// WriteONNX writes the fitted model as an ONNX model with a single ai.onnx.ml LinearRegressor node,
+// so that predictions can be made by ONNX runtimes. The model takes an input "X" of shape [N, k] and
+// gives an output "Y" of shape [N, 1]. ONNX stores the coefficients as float32, so some precision is
+// lost. Models using feature crosses cannot yet be exported.
+func (r *Regression) WriteONNX(w io.Writer) error {
+	if len(r.coeff) == 0 {
+		return ErrRegressionNotRun
+	}
+	if len(r.crosses) > 0 {
+		return ErrUnsupportedCross
+	}
+	numVars := len(r.coeff) - 1
+
+	var node protoBuffer
+	node.string(onnxNodeInput, "X")
+	node.string(onnxNodeOutput, "Y")
+	node.string(onnxNodeName, "LinearRegressor")
+	node.string(onnxNodeOpType, "LinearRegressor")
+	node.string(onnxNodeDomain, onnxMLDomain)
+
+	var attr protoBuffer
+	attr.string(onnxAttributeName, "coefficients")
+	for i := 1; i <= numVars; i++ {
+		attr.float(onnxAttributeFloats, float32(r.Coeff(i)))
+	}
+	attr.int(onnxAttributeType, onnxAttributeTypeFloats)
+	node.bytes(onnxNodeAttribute, attr)
+
+	attr = nil
+	attr.string(onnxAttributeName, "intercepts")
+	attr.float(onnxAttributeFloats, float32(r.Coeff(0)))
+	attr.int(onnxAttributeType, onnxAttributeTypeFloats)
+	node.bytes(onnxNodeAttribute, attr)
+
+	attr = nil
+	attr.string(onnxAttributeName, "post_transform")
+	attr.string(onnxAttributeString, "NONE")
+	attr.int(onnxAttributeType, onnxAttributeTypeString)
+	node.bytes(onnxNodeAttribute, attr)
+
+	attr = nil
+	attr.string(onnxAttributeName, "targets")
+	attr.int(onnxAttributeInt, 1)
+	attr.int(onnxAttributeType, onnxAttributeTypeInt)
+	node.bytes(onnxNodeAttribute, attr)
+
+	var graph protoBuffer
+	graph.bytes(onnxGraphNode, node)
+	graph.string(onnxGraphName, "regression")
+	graph.bytes(onnxGraphInput, onnxFloatTensor("X", numVars))
+	graph.bytes(onnxGraphOutput, onnxFloatTensor("Y", 1))
+
+	var model protoBuffer
+	model.int(onnxModelIRVersion, onnxIRVersion)
+	model.string(onnxModelProducerName, "github.com/Synthace/regression")
+	model.bytes(onnxModelGraph, graph)
+	for _, domain := range []string{"", onnxMLDomain} {
+		version := int64(onnxDefaultDomainOpset)
+		if domain == onnxMLDomain {
+			version = onnxMLDomainVersion
+		}
+		var opset protoBuffer
+		opset.string(onnxOpsetDomain, domain)
+		opset.int(onnxOpsetVersion, version)
+		model.bytes(onnxModelOpsetImport, opset)
+	}
+
+	_, err := w.Write(model)
+	return err
+}
+
+// onnxFloatTensor describes a float tensor input or output of shape [N, cols].
+func onnxFloatTensor(name string, cols int) protoBuffer {
+	var rows, width protoBuffer
+	rows.string(onnxDimParam, "N")
+	width.int(onnxDimValue, int64(cols))
+
+	var shape protoBuffer
+	shape.bytes(onnxShapeDim, rows)
+	shape.bytes(onnxShapeDim, width)
+
+	var tensor protoBuffer
+	tensor.int(onnxTensorElemType, onnxTensorElemFloat)
+	tensor.bytes(onnxTensorShape, shape)
+
+	var typ protoBuffer
+	typ.bytes(onnxTypeTensor, tensor)
+
+	var info protoBuffer
+	info.string(onnxValueInfoName, name)
+	info.bytes(onnxValueInfoType, typ)
+	return info
+}
@@ -0,0 +1,103 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+// protoFields decodes a protocol buffers message into its raw field values, keyed by field number.
+// Varints are returned as uint64, fixed32 values as uint32 and length delimited values as []byte.
+func protoFields(t *testing.T, b []byte) map[int][]interface{} {
+	fields := make(map[int][]interface{})
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		b = b[n:]
+		field := int(key >> 3)
+		switch key & 7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			b = b[n:]
+			fields[field] = append(fields[field], v)
+		case 2:
+			l, n := binary.Uvarint(b)
+			b = b[n:]
+			fields[field] = append(fields[field], b[:l])
+			b = b[l:]
+		case 5:
+			fields[field] = append(fields[field], binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		default:
+			t.Fatalf("Unexpected wire type %v", key&7)
+		}
+	}
+	return fields
+}
+
+func TestWriteONNX(t *testing.T) {
+	r := new(Regression)
+	var buf bytes.Buffer
+	if err := r.WriteONNX(&buf); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Train(MakeDataPoints([][]float64{
+		{1, 1, 5}, {3, 2, 3}, {2, 3, 4}, {5, 4, 1}, {7, 5, 2},
+	}, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteONNX(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	model := protoFields(t, buf.Bytes())
+	if model[onnxModelIRVersion][0].(uint64) != onnxIRVersion {
+		t.Errorf("Unexpected IR version %v", model[onnxModelIRVersion])
+	}
+	if len(model[onnxModelOpsetImport]) != 2 {
+		t.Fatalf("Expected 2 opset imports, got %v", len(model[onnxModelOpsetImport]))
+	}
+	ml := protoFields(t, model[onnxModelOpsetImport][1].([]byte))
+	if string(ml[onnxOpsetDomain][0].([]byte)) != "ai.onnx.ml" || ml[onnxOpsetVersion][0].(uint64) != 1 {
+		t.Errorf("Unexpected opset import %v", ml)
+	}
+
+	graph := protoFields(t, model[onnxModelGraph][0].([]byte))
+	node := protoFields(t, graph[onnxGraphNode][0].([]byte))
+	if string(node[onnxNodeOpType][0].([]byte)) != "LinearRegressor" || string(node[onnxNodeDomain][0].([]byte)) != "ai.onnx.ml" {
+		t.Errorf("Unexpected node %v", node)
+	}
+
+	attrs := make(map[string]map[int][]interface{})
+	for _, a := range node[onnxNodeAttribute] {
+		attr := protoFields(t, a.([]byte))
+		attrs[string(attr[onnxAttributeName][0].([]byte))] = attr
+	}
+	coeffs := attrs["coefficients"][onnxAttributeFloats]
+	if len(coeffs) != 2 {
+		t.Fatalf("Expected 2 coefficients, got %v", len(coeffs))
+	}
+	for i, c := range coeffs {
+		if v := math.Float32frombits(c.(uint32)); v != float32(r.Coeff(i+1)) {
+			t.Errorf("Expected coefficient %v to be %v, got %v", i, r.Coeff(i+1), v)
+		}
+	}
+	if v := math.Float32frombits(attrs["intercepts"][onnxAttributeFloats][0].(uint32)); v != float32(r.Coeff(0)) {
+		t.Errorf("Expected intercept %v, got %v", r.Coeff(0), v)
+	}
+	if attrs["targets"][onnxAttributeInt][0].(uint64) != 1 {
+		t.Errorf("Expected a single target, got %v", attrs["targets"])
+	}
+
+	// The input has a symbolic batch dimension and one column per variable
+	input := protoFields(t, graph[onnxGraphInput][0].([]byte))
+	typ := protoFields(t, input[onnxValueInfoType][0].([]byte))
+	tensor := protoFields(t, typ[onnxTypeTensor][0].([]byte))
+	shape := protoFields(t, tensor[onnxTensorShape][0].([]byte))
+	width := protoFields(t, shape[onnxShapeDim][1].([]byte))
+	if width[onnxDimValue][0].(uint64) != 2 {
+		t.Errorf("Expected an input width of 2, got %v", width)
+	}
+}
@@ -0,0 +1,185 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// maxOptimumCorners is the largest number of variables for which Optimize starts a numerical search
+// from every corner of the bounds. With more variables it starts from points on each axis instead.
+const maxOptimumCorners = 6
+
+// Optimum is the point within bounds at which a fitted model predicts its largest or smallest value.
+type Optimum struct {
+	Vars  []float64
+	Value float64
+	// Analytic reports whether the optimum was found exactly, from the coefficients of a linear or
+	// quadratic model, rather than by a numerical search.
+	Analytic bool
+}
+
+// Optimize finds the variables within bounds, a [low, high] pair for each variable, that maximize or
+// minimize the prediction of the fitted model. Linear models and quadratic models, whose only feature
+// crosses are squares of variables and products of two variables, are optimized analytically when the
+// optimum is a corner of the bounds or a stationary point within them. Other models are optimized
+// numerically from several starting points, which finds the global optimum of most response surfaces
+// but cannot guarantee it.
+func (r *Regression) Optimize(bounds [][2]float64, maximize bool) (*Optimum, error) {
+	if !r.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	if len(bounds) != r.rawVars {
+		return nil, fmt.Errorf("%w: got %d bounds, expected %d", ErrVariableCount, len(bounds), r.rawVars)
+	}
+	for j, b := range bounds {
+		if !(b[0] <= b[1]) || math.IsInf(b[0], 0) || math.IsInf(b[1], 0) {
+			return nil, fmt.Errorf("%w: bounds [%v, %v] of variable %d", ErrDesign, b[0], b[1], j)
+		}
+	}
+	sign := 1.0
+	if maximize {
+		sign = -1
+	}
+
+	if linear, quadratic, ok := r.quadraticForm(); ok {
+		if x, ok := quadraticOptimum(linear, quadratic, bounds, sign); ok {
+			value, err := r.predict(x)
+			if err != nil {
+				return nil, err
+			}
+			return &Optimum{Vars: x, Value: value, Analytic: true}, nil
+		}
+	}
+
+	// Search over u, with each variable x = mid + half·sin(u) kept within its bounds
+	k := len(bounds)
+	toVars := func(u []float64) []float64 {
+		x := make([]float64, k)
+		for j, b := range bounds {
+			x[j] = (b[0]+b[1])/2 + (b[1]-b[0])/2*math.Sin(u[j])
+		}
+		return x
+	}
+	problem := optimize.Problem{Func: func(u []float64) float64 {
+		p, _ := r.predict(toVars(u))
+		return sign * p
+	}}
+	starts := [][]float64{make([]float64, k)}
+	if k <= maxOptimumCorners {
+		for c := 0; c < 1<<k; c++ {
+			u := make([]float64, k)
+			for j := range u {
+				u[j] = math.Pi / 4
+				if c&(1<<j) != 0 {
+					u[j] = -math.Pi / 4
+				}
+			}
+			starts = append(starts, u)
+		}
+	} else {
+		for j := 0; j < k; j++ {
+			for _, s := range []float64{-math.Pi / 4, math.Pi / 4} {
+				u := make([]float64, k)
+				u[j] = s
+				starts = append(starts, u)
+			}
+		}
+	}
+	var best *optimize.Result
+	for _, start := range starts {
+		result, err := optimize.Minimize(problem, start, nil, &optimize.NelderMead{})
+		if err != nil && result == nil {
+			return nil, err
+		}
+		if best == nil || result.F < best.F {
+			best = result
+		}
+	}
+	x := toVars(best.X)
+	value, err := r.predict(x)
+	if err != nil {
+		return nil, err
+	}
+	return &Optimum{Vars: x, Value: value}, nil
+}
+
+// quadraticForm returns the linear and quadratic coefficients of a model whose prediction is
+// c + bᵀx + xᵀQx in the raw variables x, or false if its feature crosses are not all squares and
+// products of two variables.
+func (r *Regression) quadraticForm() ([]float64, *mat.SymDense, bool) {
+	k := r.rawVars
+	linear := make([]float64, k)
+	for j := range linear {
+		linear[j] = r.Coeff(j + 1)
+	}
+	quadratic := mat.NewSymDense(k, nil)
+	col := k + 1
+	for _, cross := range r.crosses {
+		c, ok := cross.(EncodableCross)
+		if !ok || cross.Outputs() != 1 {
+			return nil, nil, false
+		}
+		spec, coeff := c.Spec(), r.Coeff(col)
+		col++
+		switch {
+		case spec.Type == "pow" && len(spec.Params) == 1 && spec.Params[0] == 1:
+			linear[spec.Vars[0]] += coeff
+		case spec.Type == "pow" && len(spec.Params) == 1 && spec.Params[0] == 2:
+			j := spec.Vars[0]
+			quadratic.SetSym(j, j, quadratic.At(j, j)+coeff)
+		case spec.Type == "multiplier" && len(spec.Vars) == 1:
+			linear[spec.Vars[0]] += coeff
+		case spec.Type == "multiplier" && len(spec.Vars) == 2:
+			j, l := spec.Vars[0], spec.Vars[1]
+			if j == l {
+				quadratic.SetSym(j, j, quadratic.At(j, j)+coeff)
+			} else {
+				quadratic.SetSym(j, l, quadratic.At(j, l)+coeff/2)
+			}
+		default:
+			return nil, nil, false
+		}
+	}
+	return linear, quadratic, true
+}
+
+// quadraticOptimum returns the minimum of sign·(bᵀx + xᵀQx) within bounds if it can be found exactly:
+// for a linear model at the corner given by the signs of b, otherwise at the stationary point
+// x = -Q⁻¹b/2 if sign·Q is positive definite and the point lies within bounds.
+func quadraticOptimum(linear []float64, quadratic *mat.SymDense, bounds [][2]float64, sign float64) ([]float64, bool) {
+	k := len(linear)
+	x := make([]float64, k)
+	if mat.Norm(quadratic, 1) == 0 {
+		for j, b := range linear {
+			x[j] = bounds[j][0]
+			if sign*b < 0 {
+				x[j] = bounds[j][1]
+			}
+		}
+		return x, true
+	}
+	scaled := mat.NewSymDense(k, nil)
+	scaled.ScaleSym(sign, quadratic)
+	var chol mat.Cholesky
+	if !chol.Factorize(scaled) {
+		return nil, false
+	}
+	rhs := mat.NewVecDense(k, nil)
+	for j, b := range linear {
+		rhs.SetVec(j, -sign*b/2)
+	}
+	var stationary mat.VecDense
+	if err := chol.SolveVecTo(&stationary, rhs); err != nil {
+		return nil, false
+	}
+	for j := range x {
+		x[j] = stationary.AtVec(j)
+		if x[j] < bounds[j][0] || x[j] > bounds[j][1] {
+			return nil, false
+		}
+	}
+	return x, true
+}
@@ -0,0 +1,65 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// surface fits y = f(x, z) over a grid with the given crosses.
+func surface(t *testing.T, f func(x, z float64) float64, crosses ...featureCross) *Regression {
+	r := new(Regression)
+	for _, c := range crosses {
+		r.AddCross(c)
+	}
+	for x := -2.0; x <= 2; x += 0.5 {
+		for z := -2.0; z <= 2; z += 0.5 {
+			r.Train(DataPoint(f(x, z), []float64{x, z}))
+		}
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestOptimize(t *testing.T) {
+	bounds := [][2]float64{{-2, 2}, {-2, 2}}
+	quadratic := []featureCross{PowCross(0, 2), PowCross(1, 2), MultiplierCross(0, 1)}
+
+	// A peak at (1, -1), the stationary point of 1 - 2x - z = 0 and -1 - 2z - x = 0, with value 4
+	peak := surface(t, func(x, z float64) float64 { return 3 + x - z - x*x - z*z - x*z }, quadratic...)
+	opt, err := peak.Optimize(bounds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opt.Analytic || math.Abs(opt.Vars[0]-1) > 1e-9 || math.Abs(opt.Vars[1]+1) > 1e-9 || math.Abs(opt.Value-4) > 1e-9 {
+		t.Errorf("Expected the analytic maximum 4 at (1, -1), got %+v", opt)
+	}
+
+	// The minimum of a line is at the corner given by the signs of its slopes
+	line := surface(t, func(x, z float64) float64 { return 1 + 2*x - 3*z })
+	if opt, err := line.Optimize([][2]float64{{0, 1}, {5, 10}}, false); err != nil || !opt.Analytic || opt.Vars[0] != 0 || opt.Vars[1] != 10 {
+		t.Errorf("Expected the analytic minimum at (0, 10), got %+v, %v", opt, err)
+	}
+
+	// A peak outside the bounds is found on their edge numerically
+	if opt, err := peak.Optimize([][2]float64{{-0.5, 0.5}, {-0.5, 0.5}}, true); err != nil || opt.Analytic || math.Abs(opt.Vars[0]-0.5) > 1e-4 || math.Abs(opt.Vars[1]+0.5) > 1e-4 {
+		// On the edge x = 0.5 the surface is 3.25 - 1.5z - z², increasing down to z = -0.5
+		t.Errorf("Expected the numerical maximum at (0.5, -0.5), got %+v, %v", opt, err)
+	}
+
+	// A cubic is optimized numerically
+	cubic := surface(t, func(x, z float64) float64 { return x*x*x - 3*x - z*z }, PowCross(0, 2), PowCross(0, 3), PowCross(1, 2))
+	if opt, err := cubic.Optimize(bounds, true); err != nil || opt.Analytic || math.Abs(opt.Value-2) > 1e-6 {
+		// x³ - 3x is 2 at both x = -1 and x = 2
+		t.Errorf("Expected the numerical maximum 2, got %+v, %v", opt, err)
+	}
+
+	if _, err := peak.Optimize(bounds[:1], true); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+	if _, err := peak.Optimize([][2]float64{{1, 0}, {0, 1}}, true); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for empty bounds, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package regression
+
+import (
+	"math/rand"
+)
+
+// Option configures how a resampling, search or parallel method, such as Bag, LearningCurve, GridSearch,
+// Split or PredictBatch, runs.
+type Option func(*options)
+
+type options struct {
+	rand        *rand.Rand
+	concurrency int
+	progress    func(Progress)
+}
+
+// WithSeed makes the random choices of a method reproducible by drawing them from a source with the given seed.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithRand draws the random choices of a method from rng, which must not be used concurrently elsewhere.
+func WithRand(rng *rand.Rand) Option {
+	return func(o *options) {
+		o.rand = rng
+	}
+}
+
+// WithConcurrency limits a method to n goroutines at a time, for example on a shared server. By default
+// methods use up to GOMAXPROCS goroutines, as they do if n is not positive.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithProgress calls fn as each job of a search or resampling method completes, with the number done
+// and the total, for progress bars. Calls are serialized but may come from any goroutine.
+func WithProgress(fn func(Progress)) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+// newOptions applies the options over the defaults, which draw random choices from a randomly seeded source.
+func newOptions(opts []Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.rand == nil {
+		o.rand = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return o
+}
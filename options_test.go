@@ -0,0 +1,53 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestWithSeed(t *testing.T) {
+	r := new(Regression)
+	for i := 0; i < 30; i++ {
+		x := float64(i % 10)
+		r.Train(DataPoint(1+x+math.Sin(float64(i*i)), []float64{x}))
+	}
+
+	a, err := r.Bag(5, WithSeed(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.Bag(5, WithRand(rand.New(rand.NewSource(42))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.Coeffs(), b.Coeffs()) || a.OOBRMSE != b.OOBRMSE {
+		t.Errorf("Expected the same seed to give the same ensemble")
+	}
+
+	c1, _ := r.LearningCurve([]float64{0.5, 1}, 3, WithSeed(7))
+	c2, _ := r.LearningCurve([]float64{0.5, 1}, 3, WithSeed(7))
+	if !reflect.DeepEqual(c1, c2) {
+		t.Errorf("Expected the same seed to give the same learning curve")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	data := MakeDataPoints(anscombe, 0)
+	train, test := Split(data, 0.7, WithSeed(1))
+	if len(train) != 7 || len(test) != 4 {
+		t.Fatalf("Expected 7 training and 4 test points, got %d and %d", len(train), len(test))
+	}
+	seen := make(map[*dataPoint]bool)
+	for _, d := range append(train, test...) {
+		seen[d] = true
+	}
+	if len(seen) != len(data) {
+		t.Errorf("Expected every data point in exactly one set")
+	}
+	again, _ := Split(data, 0.7, WithSeed(1))
+	if !reflect.DeepEqual(train, again) {
+		t.Errorf("Expected the same seed to give the same split")
+	}
+}
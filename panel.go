@@ -0,0 +1,135 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// PanelDataPoint creates a well formed *datapoint for panel data, tagged with the entity it was observed on.
+func PanelDataPoint(entity string, obs float64, vars []float64) *dataPoint {
+	return &dataPoint{Observed: obs, Variables: vars, Entity: entity}
+}
+
+// SetFixedEffects enables the fixed effects (within) estimator for panel data. When the regression
+// is run each variable and the observed value are demeaned by entity, absorbing an effect per entity.
+// The offset is then reported as the average entity effect.
+func (r *Regression) SetFixedEffects(enabled bool) {
+	r.fixedEffects = enabled
+}
+
+// WithinR2 returns the R² of the demeaned (within entity) regression for a fixed effects model.
+func (r *Regression) WithinR2() float64 {
+	return r.withinR2
+}
+
+// EntityEffects returns the absorbed effect of each entity for a fixed effects model.
+func (r *Regression) EntityEffects() map[string]float64 {
+	if r.entityEffects == nil {
+		return nil
+	}
+	effects := make(map[string]float64, len(r.entityEffects))
+	for entity, effect := range r.entityEffects {
+		effects[entity] = effect
+	}
+	return effects
+}
+
+// PredictEntity predicts the observed value for the variables of a known entity in a fixed effects model.
+func (r *Regression) PredictEntity(entity string, vars []float64) (float64, error) {
+	effect, ok := r.entityEffects[entity]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownEntity, entity)
+	}
+	p, err := r.Predict(vars)
+	if err != nil {
+		return 0, err
+	}
+	return p + effect - r.Coeff(0), nil
+}
+
+// withinEstimator fits the coefficients on data demeaned by entity and records the entity effects,
+// within R² and the coefficient covariance.
+func (r *Regression) withinEstimator(observed, variables *mat.Dense) ([]float64, error) {
+	n, cols := variables.Dims()
+
+	entities := make(map[string][]int)
+	for i, d := range r.Data {
+		entities[d.Entity] = append(entities[d.Entity], i)
+	}
+	if n <= len(entities)+cols-1 {
+		return nil, fmt.Errorf("%w: %d observations for %d entities and %d variables", ErrTooManyVars, n, len(entities), cols-1)
+	}
+
+	// Demean the observed value and each variable, dropping the column of ones
+	means := make(map[string][]float64, len(entities))
+	y := mat.NewDense(n, 1, nil)
+	x := mat.NewDense(n, cols-1, nil)
+	for entity, rows := range entities {
+		mean := make([]float64, cols)
+		for _, i := range rows {
+			mean[0] += observed.At(i, 0) / float64(len(rows))
+			for j := 1; j < cols; j++ {
+				mean[j] += variables.At(i, j) / float64(len(rows))
+			}
+		}
+		for _, i := range rows {
+			y.Set(i, 0, observed.At(i, 0)-mean[0])
+			for j := 1; j < cols; j++ {
+				x.Set(i, j-1, variables.At(i, j)-mean[j])
+			}
+		}
+		means[entity] = mean
+	}
+
+	b, err := leastSquares(x, y)
+	if err != nil {
+		return nil, err
+	}
+	sse := sumOfSquaredResiduals(x, y, b)
+	var sst float64
+	for i := 0; i < n; i++ {
+		sst += y.At(i, 0) * y.At(i, 0)
+	}
+	r.withinR2 = 1 - sse/sst
+
+	r.entityEffects = make(map[string]float64, len(entities))
+	for entity, mean := range means {
+		effect := mean[0]
+		for j, v := range b {
+			effect -= mean[j+1] * v
+		}
+		r.entityEffects[entity] = effect
+	}
+
+	c := make([]float64, cols)
+	copy(c[1:], b)
+	var total float64
+	for i := 0; i < n; i++ {
+		total += observed.At(i, 0)
+	}
+	c[0] = total / float64(n)
+	for j := 1; j < cols; j++ {
+		var m float64
+		for i := 0; i < n; i++ {
+			m += variables.At(i, j)
+		}
+		c[0] -= b[j-1] * m / float64(n)
+	}
+
+	// The degrees of freedom account for the absorbed entity effects
+	r.residualDF = n - len(entities) - cols + 1
+	r.cov = nil
+	if inv, err := crossProductInverse(x); err == nil {
+		sigma2 := sse / float64(r.residualDF)
+		r.cov = mat.NewSymDense(cols, nil)
+		r.cov.SetSym(0, 0, math.NaN())
+		for j := 1; j < cols; j++ {
+			for k := 1; k <= j; k++ {
+				r.cov.SetSym(j, k, sigma2*inv.At(j-1, k-1))
+			}
+		}
+	}
+	return c, nil
+}
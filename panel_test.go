@@ -0,0 +1,86 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func panelData() []*dataPoint {
+	var points []*dataPoint
+	effects := map[string]float64{"a": 10, "b": 20, "c": 30}
+	for k, entity := range []string{"a", "b", "c"} {
+		for i := 0; i < 8; i++ {
+			// The variable is correlated with the entity effect, biasing a pooled regression
+			x := float64(i) + 5*float64(k)
+			y := effects[entity] + 2*x + math.Sin(float64(i*3+k))
+			points = append(points, PanelDataPoint(entity, y, []float64{x}))
+		}
+	}
+	return points
+}
+
+func TestFixedEffects(t *testing.T) {
+	r := new(Regression)
+	r.Train(panelData()...)
+	r.SetFixedEffects(true)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r.Coeff(1)-2) > 0.2 {
+		t.Errorf("Expected a slope close to 2, got %.4f", r.Coeff(1))
+	}
+	effects := r.EntityEffects()
+	for entity, expected := range map[string]float64{"a": 10, "b": 20, "c": 30} {
+		if math.Abs(effects[entity]-expected) > 1 {
+			t.Errorf("Expected the effect of %v to be close to %v, got %.4f", entity, expected, effects[entity])
+		}
+	}
+	if r.WithinR2() < 0.9 || r.WithinR2() > r.R2 {
+		t.Errorf("Expected a high within R² below the overall R², got %.4f and %.4f", r.WithinR2(), r.R2)
+	}
+
+	p, err := r.PredictEntity("b", []float64{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(p-(effects["b"]+3*r.Coeff(1))) > 1e-9 {
+		t.Errorf("Expected the entity prediction to include its effect, got %.4f", p)
+	}
+	if _, err := r.PredictEntity("z", []float64{3}); !errors.Is(err, ErrUnknownEntity) {
+		t.Errorf("Expected ErrUnknownEntity, got %v", err)
+	}
+
+	// The within estimator is equivalent to a regression with a dummy variable per entity
+	dummies := new(Regression)
+	for _, d := range panelData() {
+		vars := []float64{d.Variables[0], 0, 0}
+		if d.Entity == "b" {
+			vars[1] = 1
+		}
+		if d.Entity == "c" {
+			vars[2] = 1
+		}
+		dummies.Train(DataPoint(d.Observed, vars))
+	}
+	if err := dummies.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(dummies.Coeff(1)-r.Coeff(1)) > 1e-9 || math.Abs(dummies.StdErr(1)-r.StdErr(1)) > 1e-9 {
+		t.Errorf("Expected the dummy variable fit to match, got %v ± %v and %v ± %v",
+			dummies.Coeff(1), dummies.StdErr(1), r.Coeff(1), r.StdErr(1))
+	}
+	if math.Abs(dummies.Coeff(0)-effects["a"]) > 1e-9 {
+		t.Errorf("Expected the effect of a to be %v, got %v", dummies.Coeff(0), effects["a"])
+	}
+}
+
+func TestFixedEffectsIncompatible(t *testing.T) {
+	r := new(Regression)
+	r.Train(panelData()...)
+	r.SetFixedEffects(true)
+	r.SetEndogenous(0)
+	if err := r.Run(); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions, got %v", err)
+	}
+}
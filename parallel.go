@@ -0,0 +1,33 @@
+package regression
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelFor calls fn for each i in [0, n) using up to workers goroutines, or GOMAXPROCS if workers
+// is not positive, returning once all calls are complete.
+func parallelFor(workers, n int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
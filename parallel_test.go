@@ -0,0 +1,53 @@
+package regression
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelForConcurrency(t *testing.T) {
+	var active, peak int32
+	parallelFor(2, 20, func(i int) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&active, -1)
+	})
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent calls, got %d", peak)
+	}
+}
+
+func TestPredictBatch(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Run()
+
+	vars := make([][]float64, 3000)
+	for i := range vars {
+		vars[i] = []float64{float64(i) / 100}
+	}
+	predictions, err := r.PredictBatch(vars, WithConcurrency(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vars {
+		if p, _ := r.Predict(v); predictions[i] != p {
+			t.Fatalf("Expected prediction %d to be %v, got %v", i, p, predictions[i])
+		}
+	}
+
+	vars[2500] = []float64{1, 2}
+	_, err = r.PredictBatch(vars)
+	var pointErr *DataPointError
+	if !errors.As(err, &pointErr) || pointErr.Index != 2500 {
+		t.Errorf("Expected an error for data point 2500, got %v", err)
+	}
+}
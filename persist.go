@@ -0,0 +1,402 @@
+package regression
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// modelFormatVersion is the version of the JSON model format written by Regression.MarshalJSON, and
+// pipelineFormatVersion that written by Pipeline.MarshalJSON. Files written in earlier versions are
+// migrated to the current version as they are read, and later versions are rejected rather than misread.
+//
+// Version 2 of the model format records the residual and total sums of squares and the fit metadata.
+const (
+	modelFormatVersion    = 2
+	pipelineFormatVersion = 1
+)
+
+// migration upgrades the fields of a JSON encoded model from one version of the format to the next.
+type migration func(fields map[string]json.RawMessage) error
+
+// modelMigrations upgrade models from version i+1 of the format to version i+2, and pipelineMigrations
+// pipelines, so there is one for every version before the current one. A pipeline migrates its model
+// as the model is decoded.
+var (
+	modelMigrations    = []migration{migrateModelV1}
+	pipelineMigrations = []migration{}
+)
+
+// migrateModelV1 derives the sums of squares missing from models written in version 1 from their
+// residual standard error, degrees of freedom and R².
+func migrateModelV1(fields map[string]json.RawMessage) error {
+	if _, ok := fields["residual_ss"]; ok {
+		return nil
+	}
+	var sigma, r2 jsonFloat
+	var df int
+	for name, v := range map[string]interface{}{"residual_std_err": &sigma, "r2": &r2, "residual_df": &df} {
+		if raw, ok := fields[name]; ok {
+			if err := json.Unmarshal(raw, v); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		}
+	}
+	residualSS := float64(sigma) * float64(sigma) * float64(df)
+	totalSS := math.NaN()
+	if r2 < 1 {
+		totalSS = residualSS / (1 - float64(r2))
+	}
+	fields["residual_ss"], _ = json.Marshal(jsonFloat(residualSS))
+	fields["total_ss"], _ = json.Marshal(jsonFloat(totalSS))
+	return nil
+}
+
+// migrate upgrades JSON written in an earlier version of a format to the current version, applying
+// each migration from its version in turn.
+func migrate(b []byte, current int, migrations []migration) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrModelFormat, err)
+	}
+	var version int
+	if err := json.Unmarshal(fields["version"], &version); err != nil {
+		return nil, fmt.Errorf("%w: no version", ErrModelFormat)
+	}
+	if version < 1 || version > current {
+		return nil, fmt.Errorf("%w: version %d", ErrModelFormat, version)
+	}
+	if version == current {
+		return b, nil
+	}
+	for ; version < current; version++ {
+		if err := migrations[version-1](fields); err != nil {
+			return nil, fmt.Errorf("%w: migrating from version %d: %v", ErrModelFormat, version, err)
+		}
+	}
+	fields["version"], _ = json.Marshal(current)
+	return json.Marshal(fields)
+}
+
+// MigrateModel rewrites a model encoded by MarshalJSON in an earlier version of the format in the
+// current version, for upgrading stored models ahead of the package dropping support for old versions.
+// Models already in the current version are returned unchanged.
+func MigrateModel(b []byte) ([]byte, error) {
+	return migrate(b, modelFormatVersion, modelMigrations)
+}
+
+// jsonFloat is a float64 that encodes NaN as null, since JSON has no representation of NaN.
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(f))
+}
+
+func (f *jsonFloat) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*f = jsonFloat(math.NaN())
+		return nil
+	}
+	return json.Unmarshal(b, (*float64)(f))
+}
+
+func toJSONFloats(x []float64) []jsonFloat {
+	if x == nil {
+		return nil
+	}
+	out := make([]jsonFloat, len(x))
+	for i, v := range x {
+		out[i] = jsonFloat(v)
+	}
+	return out
+}
+
+func fromJSONFloats(x []jsonFloat) []float64 {
+	if x == nil {
+		return nil
+	}
+	out := make([]float64, len(x))
+	for i, v := range x {
+		out[i] = float64(v)
+	}
+	return out
+}
+
+// regressionJSON is the JSON form of a fitted model: what is needed to predict, with the statistics
+// needed to report the coefficients, but not the training data.
+type regressionJSON struct {
+	Version        int                `json:"version"`
+	Observed       string             `json:"observed,omitempty"`
+	Variables      []string           `json:"variables"`
+	RawVars        int                `json:"raw_vars"`
+	Coefficients   []jsonFloat        `json:"coefficients"`
+	Covariance     [][]jsonFloat      `json:"covariance,omitempty"`
+	ResidualDF     int                `json:"residual_df"`
+	ResidualStdErr jsonFloat          `json:"residual_std_err"`
+	TotalSS        jsonFloat          `json:"total_ss"`
+	ResidualSS     jsonFloat          `json:"residual_ss"`
+	R2             jsonFloat          `json:"r2"`
+	AdjustedR2     jsonFloat          `json:"adjusted_r2"`
+	EntityEffects  map[string]float64 `json:"entity_effects,omitempty"`
+	VarianceCoeffs []jsonFloat        `json:"variance_coeffs,omitempty"`
+	Crosses        []CrossSpec        `json:"crosses,omitempty"`
+	Metadata       *FitMetadata       `json:"metadata,omitempty"`
+}
+
+// MarshalJSON encodes the fitted model: its names, coefficients, their covariance, the model level
+// statistics and the metadata of the fit, but not its training data. Feature crosses are encoded by their CrossSpec, so only
+// crosses whose type is registered with RegisterCross can be encoded.
+func (r *Regression) MarshalJSON() ([]byte, error) {
+	if !r.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	m := regressionJSON{
+		Version:        modelFormatVersion,
+		Observed:       r.names.obs,
+		Variables:      r.Variables(),
+		RawVars:        r.rawVars,
+		Coefficients:   toJSONFloats(r.GetCoeffs()),
+		ResidualDF:     r.residualDF,
+		ResidualStdErr: jsonFloat(r.residualStdErr),
+		TotalSS:        jsonFloat(r.totalSS),
+		ResidualSS:     jsonFloat(r.residualSS),
+		R2:             jsonFloat(r.R2),
+		AdjustedR2:     jsonFloat(r.AdjustedR2),
+		EntityEffects:  r.entityEffects,
+		VarianceCoeffs: toJSONFloats(r.varianceCoeffs),
+		Metadata:       r.metadata,
+	}
+	for _, cross := range r.crosses {
+		spec, err := encodeCross(cross)
+		if err != nil {
+			return nil, err
+		}
+		m.Crosses = append(m.Crosses, spec)
+	}
+	if r.cov != nil {
+		n := r.cov.SymmetricDim()
+		m.Covariance = make([][]jsonFloat, n)
+		for i := range m.Covariance {
+			m.Covariance[i] = make([]jsonFloat, n)
+			for j := range m.Covariance[i] {
+				m.Covariance[i][j] = jsonFloat(r.cov.At(i, j))
+			}
+		}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes a model encoded by MarshalJSON into r, replacing its configuration. The
+// decoded model predicts and reports its coefficients, but has no training data, so diagnostics
+// that need it return ErrNotEnoughData.
+func (r *Regression) UnmarshalJSON(b []byte) error {
+	b, err := MigrateModel(b)
+	if err != nil {
+		return err
+	}
+	var m regressionJSON
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("%w: %v", ErrModelFormat, err)
+	}
+	if len(m.Coefficients) != len(m.Variables)+1 || m.RawVars < 0 || m.RawVars > len(m.Variables) {
+		return fmt.Errorf("%w: %d coefficients for %d variables", ErrModelFormat, len(m.Coefficients), len(m.Variables))
+	}
+	*r = Regression{
+		initialised:    true,
+		hasRun:         true,
+		rawVars:        m.RawVars,
+		residualDF:     m.ResidualDF,
+		residualStdErr: float64(m.ResidualStdErr),
+		totalSS:        float64(m.TotalSS),
+		residualSS:     float64(m.ResidualSS),
+		R2:             float64(m.R2),
+		AdjustedR2:     float64(m.AdjustedR2),
+		entityEffects:  m.EntityEffects,
+		varianceCoeffs: fromJSONFloats(m.VarianceCoeffs),
+		metadata:       m.Metadata,
+	}
+	total := m.RawVars
+	for _, spec := range m.Crosses {
+		cross, err := decodeCross(spec)
+		if err != nil {
+			return err
+		}
+		for _, i := range cross.Inputs() {
+			if i < 0 || i >= m.RawVars {
+				return fmt.Errorf("%w: cross %s uses variable %d of %d", ErrModelFormat, cross.ID(), i, m.RawVars)
+			}
+		}
+		total += cross.Outputs()
+		r.crosses = append(r.crosses, cross)
+	}
+	if total != len(m.Variables) {
+		return fmt.Errorf("%w: %d variables and crosses generating %d more for %d variables", ErrModelFormat, m.RawVars, total-m.RawVars, len(m.Variables))
+	}
+	r.names.obs = m.Observed
+	for i, name := range m.Variables {
+		r.SetVar(i, name)
+	}
+	r.coeff = fromJSONFloats(m.Coefficients)
+	if m.Covariance != nil {
+		n := len(m.Coefficients)
+		r.cov = mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			if len(m.Covariance) != n || len(m.Covariance[i]) != n {
+				return fmt.Errorf("%w: covariance is not %d by %d", ErrModelFormat, n, n)
+			}
+			for j := i; j < n; j++ {
+				r.cov.SetSym(i, j, float64(m.Covariance[i][j]))
+			}
+		}
+	}
+	r.setFormula()
+	return nil
+}
+
+// GobEncode encodes the fitted model for encoding/gob, in the same form as MarshalJSON.
+func (r *Regression) GobEncode() ([]byte, error) {
+	return r.MarshalJSON()
+}
+
+// GobDecode decodes a model encoded by GobEncode.
+func (r *Regression) GobDecode(b []byte) error {
+	return r.UnmarshalJSON(b)
+}
+
+// transformJSON is the JSON form of a response or predictor transform, identified by its kind.
+type transformJSON struct {
+	Kind   string          `json:"kind"`
+	Lambda float64         `json:"lambda,omitempty"`
+	Col    int             `json:"col,omitempty"`
+	Means  map[int]float64 `json:"means,omitempty"`
+	Scales map[int]float64 `json:"scales,omitempty"`
+	Levels []float64       `json:"levels,omitempty"`
+	Cross  *CrossSpec      `json:"cross,omitempty"`
+}
+
+// pipelineJSON is the JSON form of a fitted pipeline.
+type pipelineJSON struct {
+	Version  int             `json:"version"`
+	Inputs   []string        `json:"inputs"`
+	Response *transformJSON  `json:"response,omitempty"`
+	Steps    []transformJSON `json:"steps,omitempty"`
+	Model    *Regression     `json:"model"`
+}
+
+// encodeTransform returns the JSON form of one of the transforms provided by this package.
+func encodeTransform(t interface{}) (transformJSON, error) {
+	switch t := t.(type) {
+	case *boxCox:
+		return transformJSON{Kind: "boxcox", Lambda: t.lambda}, nil
+	case *standardize:
+		return transformJSON{Kind: "standardize", Means: t.means, Scales: t.scales}, nil
+	case *oneHot:
+		return transformJSON{Kind: "onehot", Col: t.col, Levels: t.levels}, nil
+	case *crossStep:
+		spec, err := encodeCross(t.cross)
+		if err != nil {
+			return transformJSON{}, err
+		}
+		return transformJSON{Kind: "cross", Cross: &spec}, nil
+	}
+	return transformJSON{}, fmt.Errorf("%w: transform of type %T", ErrModelFormat, t)
+}
+
+// decodePredictorTransform returns the predictor transform encoded by encodeTransform.
+func decodePredictorTransform(t transformJSON) (PredictorTransform, error) {
+	switch t.Kind {
+	case "standardize":
+		if len(t.Means) != len(t.Scales) {
+			return nil, fmt.Errorf("%w: %d means and %d scales", ErrModelFormat, len(t.Means), len(t.Scales))
+		}
+		return &standardize{means: t.Means, scales: t.Scales}, nil
+	case "onehot":
+		if len(t.Levels) < 2 {
+			return nil, fmt.Errorf("%w: %d levels to encode", ErrModelFormat, len(t.Levels))
+		}
+		if t.Col < 0 {
+			return nil, fmt.Errorf("%w: cannot encode variable %d", ErrModelFormat, t.Col)
+		}
+		return &oneHot{col: t.Col, levels: t.Levels}, nil
+	case "cross":
+		if t.Cross == nil {
+			return nil, fmt.Errorf("%w: no feature cross", ErrModelFormat)
+		}
+		cross, err := decodeCross(*t.Cross)
+		if err != nil {
+			return nil, err
+		}
+		return &crossStep{cross: cross}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown predictor transform %q", ErrModelFormat, t.Kind)
+}
+
+// MarshalJSON encodes the fitted pipeline, with the fitted parameters of its transforms alongside
+// the model, so that a decoded pipeline preprocesses data exactly as in training. Only the
+// transforms provided by this package can be encoded.
+func (p *Pipeline) MarshalJSON() ([]byte, error) {
+	if !p.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	m := pipelineJSON{Version: pipelineFormatVersion, Inputs: p.inputs, Model: p.model}
+	if p.response != nil {
+		t, err := encodeTransform(p.response)
+		if err != nil {
+			return nil, err
+		}
+		m.Response = &t
+	}
+	for _, step := range p.steps {
+		t, err := encodeTransform(step)
+		if err != nil {
+			return nil, err
+		}
+		m.Steps = append(m.Steps, t)
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes a pipeline encoded by MarshalJSON into p, ready to predict.
+func (p *Pipeline) UnmarshalJSON(b []byte) error {
+	b, err := migrate(b, pipelineFormatVersion, pipelineMigrations)
+	if err != nil {
+		return err
+	}
+	var m pipelineJSON
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("%w: %v", ErrModelFormat, err)
+	}
+	if m.Model == nil {
+		return fmt.Errorf("%w: no model", ErrModelFormat)
+	}
+	*p = Pipeline{model: m.Model, inputs: m.Inputs, hasRun: true}
+	if m.Response != nil {
+		if m.Response.Kind != "boxcox" {
+			return fmt.Errorf("%w: unknown response transform %q", ErrModelFormat, m.Response.Kind)
+		}
+		p.response = BoxCox(m.Response.Lambda)
+	}
+	for _, t := range m.Steps {
+		step, err := decodePredictorTransform(t)
+		if err != nil {
+			return err
+		}
+		p.steps = append(p.steps, step)
+	}
+	return nil
+}
+
+// GobEncode encodes the fitted pipeline for encoding/gob, in the same form as MarshalJSON.
+func (p *Pipeline) GobEncode() ([]byte, error) {
+	return p.MarshalJSON()
+}
+
+// GobDecode decodes a pipeline encoded by GobEncode.
+func (p *Pipeline) GobDecode(b []byte) error {
+	return p.UnmarshalJSON(b)
+}
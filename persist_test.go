@@ -0,0 +1,222 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestRegressionJSON(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "x")
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(Regression)
+	if err := json.Unmarshal(b, loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Formula != r.Formula || loaded.GetObserved() != r.GetObserved() {
+		t.Errorf("Expected formula %q, got %q", r.Formula, loaded.Formula)
+	}
+	vars := []float64{10}
+	want, _ := r.Predict(vars)
+	if got, err := loaded.Predict(vars); err != nil || got != want {
+		t.Errorf("Expected prediction %v, got %v, %v", want, got, err)
+	}
+	rows, _ := r.Tidy()
+	loadedRows, err := loaded.Tidy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range rows {
+		if rows[i] != loadedRows[i] {
+			t.Errorf("Expected row %v, got %v", rows[i], loadedRows[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(r); err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(Regression)
+	if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Coeff(1) != r.Coeff(1) {
+		t.Errorf("Expected coefficient %v, got %v", r.Coeff(1), decoded.Coeff(1))
+	}
+
+	if err := json.Unmarshal([]byte(`{"version":99}`), new(Regression)); !errors.Is(err, ErrModelFormat) {
+		t.Errorf("Expected ErrModelFormat for a later version, got %v", err)
+	}
+	if _, err := json.Marshal(new(Regression)); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+}
+
+func TestRegressionJSONDroppedVariable(t *testing.T) {
+	// A duplicated column is dropped, leaving NaN in the covariance, which must survive encoding
+	r := new(Regression)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		r.Train(DataPoint(1+2*x+math.Sin(x), []float64{x, x}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(Regression)
+	if err := json.Unmarshal(b, loaded); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if want, got := r.StdErr(i), loaded.StdErr(i); got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("Expected standard error %v for coefficient %d, got %v", want, i, got)
+		}
+	}
+}
+
+func TestPipelineJSON(t *testing.T) {
+	var points DataPoints
+	for i := 0; i < 30; i++ {
+		x, group := float64(i%10), float64(1+i%3)
+		points = append(points, DataPoint(math.Exp(1+0.5*x+0.1*group*group), []float64{x, group}))
+	}
+	r := new(Regression)
+	r.SetVar(0, "dose")
+	r.SetVar(1, "group")
+	p := NewPipeline(r).Response(BoxCox(0.5)).Add(Standardize()).Add(OneHot(1))
+	p.Train(points...)
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(Pipeline)
+	if err := json.Unmarshal(b, loaded); err != nil {
+		t.Fatal(err)
+	}
+	for _, vars := range [][]float64{{4, 2}, {9, 3}, {0, 1}} {
+		want, _ := p.Predict(vars)
+		if got, err := loaded.Predict(vars); err != nil || got != want {
+			t.Errorf("Expected prediction %v for %v, got %v, %v", want, vars, got, err)
+		}
+	}
+	if got := loaded.Inputs(); len(got) != 2 || got[0] != "dose" {
+		t.Errorf("Expected inputs [dose group], got %v", got)
+	}
+
+	c := NewPipeline(new(Regression)).Add(OneHot(1)).Add(CrossStep(MultiplierCross(0, 2)))
+	c.Train(points...)
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = json.Marshal(c); err != nil {
+		t.Fatal(err)
+	}
+	loaded = new(Pipeline)
+	if err := json.Unmarshal(b, loaded); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := c.Predict([]float64{4, 3})
+	if got, err := loaded.Predict([]float64{4, 3}); err != nil || got != want {
+		t.Errorf("Expected prediction %v with a crossed step, got %v, %v", want, got, err)
+	}
+}
+
+func TestRegressionJSONInconsistent(t *testing.T) {
+	for _, model := range []string{
+		`{"version":2,"variables":["a","b"],"raw_vars":1,"coefficients":[1,2,3]}`,
+		`{"version":2,"variables":["a"],"raw_vars":-1,"coefficients":[1,2]}`,
+		`{"version":2,"variables":["a","b"],"raw_vars":1,"coefficients":[1,2,3],"crosses":[{"type":"pow","vars":[1],"params":[2]}]}`,
+		`{"version":2,"variables":["a","b","c"],"raw_vars":1,"coefficients":[1,2,3,4],"crosses":[{"type":"pow","vars":[0],"params":[2]}]}`,
+	} {
+		if err := json.Unmarshal([]byte(model), new(Regression)); !errors.Is(err, ErrModelFormat) {
+			t.Errorf("Expected ErrModelFormat for %s, got %v", model, err)
+		}
+	}
+	valid := `{"version":2,"variables":["a","b"],"raw_vars":1,"coefficients":[1,2,3],"crosses":[{"type":"pow","vars":[0],"params":[2]}]}`
+	r := new(Regression)
+	if err := json.Unmarshal([]byte(valid), r); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := r.Predict([]float64{2}); err != nil || got != 1+2*2+3*4 {
+		t.Errorf("Expected prediction 17, got %v, %v", got, err)
+	}
+
+	p := `{"version":%d,"inputs":["a"],"steps":[{"kind":"onehot","col":-1,"levels":[1,2]}],"model":` + valid + `}`
+	if err := json.Unmarshal([]byte(fmt.Sprintf(p, pipelineFormatVersion)), new(Pipeline)); !errors.Is(err, ErrModelFormat) {
+		t.Errorf("Expected ErrModelFormat for a negative one-hot column, got %v", err)
+	}
+}
+
+func TestModelMigration(t *testing.T) {
+	if len(modelMigrations) != modelFormatVersion-1 || len(pipelineMigrations) != pipelineFormatVersion-1 {
+		t.Fatalf("Expected a migration from every earlier version, got %d models and %d pipelines", len(modelMigrations), len(pipelineMigrations))
+	}
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the model as version 1 wrote it, without sums of squares or metadata
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	fields["version"] = 1
+	for _, name := range []string{"total_ss", "residual_ss", "metadata"} {
+		delete(fields, name)
+	}
+	v1, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := new(Regression)
+	if err := json.Unmarshal(v1, loaded); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(loaded.ResidualSS()-r.ResidualSS()) > 1e-9 || math.Abs(loaded.TotalSS()-r.TotalSS()) > 1e-9 {
+		t.Errorf("Expected sums of squares %v and %v from a version 1 model, got %v and %v", r.ResidualSS(), r.TotalSS(), loaded.ResidualSS(), loaded.TotalSS())
+	}
+	if loaded.Metadata() != nil {
+		t.Errorf("Expected no metadata from a version 1 model, got %+v", loaded.Metadata())
+	}
+
+	migrated, err := MigrateModel(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var version struct{ Version int }
+	if err := json.Unmarshal(migrated, &version); err != nil || version.Version != modelFormatVersion {
+		t.Errorf("Expected the migrated model in version %d, got %d, %v", modelFormatVersion, version.Version, err)
+	}
+	if same, err := MigrateModel(b); err != nil || !bytes.Equal(same, b) {
+		t.Errorf("Expected a current model to be unchanged, got %v", err)
+	}
+	if _, err := MigrateModel([]byte(`{"coefficients":[1]}`)); !errors.Is(err, ErrModelFormat) {
+		t.Errorf("Expected ErrModelFormat without a version, got %v", err)
+	}
+}
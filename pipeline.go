@@ -0,0 +1,358 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// ResponseTransform transforms the observed values before a model is fitted, and transforms its
+// predictions back to the original scale.
+type ResponseTransform interface {
+	// Forward transforms an observed value, returning NaN if it is outside the domain of the transform.
+	Forward(y float64) float64
+	Inverse(z float64) float64
+}
+
+// PredictorTransform transforms the variables of each data point before they reach the model. Its
+// parameters, such as the means of the variables to scale, are fitted to the training data.
+type PredictorTransform interface {
+	// Fit estimates the parameters of the transform from the variables of the training data.
+	Fit(rows [][]float64) error
+	// Transform returns the transformed variables without changing vars.
+	Transform(vars []float64) ([]float64, error)
+	// Names returns the names of the transformed variables given the names of the inputs.
+	Names(names []string) []string
+}
+
+type boxCox struct {
+	lambda float64
+}
+
+// BoxCox transforms the observed values y, which must be positive, to (y^λ - 1)/λ, or log y if λ is 0.
+// Predictions transformed back to the original scale estimate the median rather than the mean.
+func BoxCox(lambda float64) ResponseTransform {
+	return &boxCox{lambda: lambda}
+}
+
+// LogResponse transforms the observed values to their natural logarithm, BoxCox(0).
+func LogResponse() ResponseTransform {
+	return BoxCox(0)
+}
+
+func (b *boxCox) Forward(y float64) float64 {
+	if !(y > 0) {
+		return math.NaN()
+	}
+	if b.lambda == 0 {
+		return math.Log(y)
+	}
+	return (math.Pow(y, b.lambda) - 1) / b.lambda
+}
+
+func (b *boxCox) Inverse(z float64) float64 {
+	if b.lambda == 0 {
+		return math.Exp(z)
+	}
+	return math.Pow(b.lambda*z+1, 1/b.lambda)
+}
+
+type standardize struct {
+	cols   []int
+	means  map[int]float64
+	scales map[int]float64
+}
+
+// Standardize centers the given variables, or all of them if none are given, on their training mean
+// and scales them to unit standard deviation. NaN values are ignored when fitting.
+func Standardize(cols ...int) PredictorTransform {
+	return &standardize{cols: cols}
+}
+
+func (s *standardize) Fit(rows [][]float64) error {
+	cols := s.cols
+	if len(cols) == 0 && len(rows) > 0 {
+		for j := range rows[0] {
+			cols = append(cols, j)
+		}
+	}
+	s.means = make(map[int]float64, len(cols))
+	s.scales = make(map[int]float64, len(cols))
+	for _, j := range cols {
+		if len(rows) > 0 && (j < 0 || j >= len(rows[0])) {
+			return fmt.Errorf("%w: cannot standardize variable %d", ErrVariableIndex, j)
+		}
+		var n, mean, ss float64
+		for _, row := range rows {
+			if !math.IsNaN(row[j]) {
+				n++
+				d := row[j] - mean
+				mean += d / n
+				ss += d * (row[j] - mean)
+			}
+		}
+		if n < 2 || ss == 0 {
+			return fmt.Errorf("%w: cannot standardize variable %d", ErrConstantVariable, j)
+		}
+		s.means[j], s.scales[j] = mean, math.Sqrt(ss/(n-1))
+	}
+	return nil
+}
+
+func (s *standardize) Transform(vars []float64) ([]float64, error) {
+	out := append([]float64(nil), vars...)
+	for j, mean := range s.means {
+		if j >= len(out) {
+			return nil, fmt.Errorf("%w: cannot standardize variable %d", ErrVariableIndex, j)
+		}
+		out[j] = (out[j] - mean) / s.scales[j]
+	}
+	return out, nil
+}
+
+func (s *standardize) Names(names []string) []string {
+	return names
+}
+
+type oneHot struct {
+	col    int
+	levels []float64
+}
+
+// OneHot encodes a variable holding numeric category codes as an indicator variable for each code
+// seen in training except the smallest, which is the reference level. The indicators replace the
+// variable, following the remaining variables. Predicting with an unseen code is an error.
+func OneHot(col int) PredictorTransform {
+	return &oneHot{col: col}
+}
+
+func (o *oneHot) Fit(rows [][]float64) error {
+	seen := make(map[float64]bool)
+	o.levels = nil
+	for _, row := range rows {
+		if o.col < 0 || o.col >= len(row) {
+			return fmt.Errorf("%w: cannot encode variable %d", ErrVariableIndex, o.col)
+		}
+		if v := row[o.col]; !seen[v] && !math.IsNaN(v) {
+			seen[v] = true
+			o.levels = append(o.levels, v)
+		}
+	}
+	sort.Float64s(o.levels)
+	if len(o.levels) < 2 {
+		return fmt.Errorf("%w: variable %d needs at least 2 levels to encode", ErrDesign, o.col)
+	}
+	return nil
+}
+
+func (o *oneHot) Transform(vars []float64) ([]float64, error) {
+	if o.col >= len(vars) {
+		return nil, fmt.Errorf("%w: cannot encode variable %d", ErrVariableIndex, o.col)
+	}
+	out := make([]float64, 0, len(vars)+len(o.levels)-2)
+	out = append(out, vars[:o.col]...)
+	out = append(out, vars[o.col+1:]...)
+	v := vars[o.col]
+	known := math.IsNaN(v) || v == o.levels[0]
+	for _, level := range o.levels[1:] {
+		switch {
+		case math.IsNaN(v):
+			out = append(out, math.NaN())
+		case v == level:
+			out = append(out, 1)
+			known = true
+		default:
+			out = append(out, 0)
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: unknown level %v of variable %d", ErrDesign, v, o.col)
+	}
+	return out, nil
+}
+
+func (o *oneHot) Names(names []string) []string {
+	out := make([]string, 0, len(names)+len(o.levels)-2)
+	out = append(out, names[:o.col]...)
+	out = append(out, names[o.col+1:]...)
+	for _, level := range o.levels[1:] {
+		out = append(out, names[o.col]+"["+strconv.FormatFloat(level, 'g', -1, 64)+"]")
+	}
+	return out
+}
+
+type crossStep struct {
+	cross featureCross
+}
+
+// CrossStep appends the features generated by a feature cross of the variables at that point in the
+// pipeline, so that crosses can be taken of transformed variables such as encoded categories.
+func CrossStep(cross featureCross) PredictorTransform {
+	return &crossStep{cross: cross}
+}
+
+func (c *crossStep) Fit(rows [][]float64) error {
+	return nil
+}
+
+func (c *crossStep) Transform(vars []float64) ([]float64, error) {
+	vars = vars[:len(vars):len(vars)]
+	return append(vars, c.cross.Calculate(vars)...), nil
+}
+
+func (c *crossStep) Names(names []string) []string {
+	named := make(map[int]string, len(names))
+	for i, name := range names {
+		named[i] = name
+	}
+	extra := c.cross.ExtendNames(named, len(names))
+	out := append([]string(nil), names...)
+	for i := 0; i < extra; i++ {
+		out = append(out, named[len(names)+i])
+	}
+	return out
+}
+
+// Pipeline chains a response transform and predictor transforms with a regression, so that the
+// preprocessing fitted to the training data is applied in the same way whenever the model predicts.
+type Pipeline struct {
+	model    *Regression
+	response ResponseTransform
+	steps    []PredictorTransform
+	inputs   []string
+	data     DataPoints
+	hasRun   bool
+}
+
+// NewPipeline creates a pipeline that fits the regression r, which should be configured but not
+// trained, to the transformed data. Names set on r with SetVar name the variables before transformation.
+func NewPipeline(r *Regression) *Pipeline {
+	return &Pipeline{model: r}
+}
+
+// Response sets the transform of the observed values.
+func (p *Pipeline) Response(t ResponseTransform) *Pipeline {
+	p.response = t
+	return p
+}
+
+// Add appends a transform of the variables, applied after those already added.
+func (p *Pipeline) Add(t PredictorTransform) *Pipeline {
+	p.steps = append(p.steps, t)
+	return p
+}
+
+// Train the pipeline with some data points, which are not changed.
+func (p *Pipeline) Train(d ...*dataPoint) {
+	p.data = append(p.data, d...)
+}
+
+// Model returns the regression fitted to the transformed data.
+func (p *Pipeline) Model() *Regression {
+	return p.model
+}
+
+// Inputs returns the names of the variables before transformation.
+func (p *Pipeline) Inputs() []string {
+	return p.inputs
+}
+
+// Run fits each transform in turn to the training data as transformed by the transforms before it,
+// then trains and runs the regression on the transformed data.
+func (p *Pipeline) Run() error {
+	if p.hasRun {
+		return ErrRegressionRun
+	}
+	if len(p.data) == 0 {
+		return ErrNotEnoughData
+	}
+	n := len(p.data[0].Variables)
+	for i, d := range p.data {
+		if len(d.Variables) != n {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(d.Variables), n)}
+		}
+	}
+	p.inputs = make([]string, n)
+	for i := range p.inputs {
+		p.inputs[i] = p.model.GetVar(i)
+	}
+
+	rows := make([][]float64, len(p.data))
+	for i, d := range p.data {
+		rows[i] = d.Variables
+	}
+	names := p.inputs
+	for k, step := range p.steps {
+		if err := step.Fit(rows); err != nil {
+			return fmt.Errorf("fitting transform %d: %w", k, err)
+		}
+		for i, row := range rows {
+			out, err := step.Transform(row)
+			if err != nil {
+				return &DataPointError{Index: i, Err: err}
+			}
+			rows[i] = out
+		}
+		names = step.Names(names)
+	}
+
+	points := make(DataPoints, len(p.data))
+	for i, d := range p.data {
+		y := d.Observed
+		if p.response != nil {
+			if y = p.response.Forward(y); math.IsNaN(y) {
+				return &DataPointError{Index: i, Err: fmt.Errorf("%w: observed value %v", ErrTransform, d.Observed)}
+			}
+		}
+		points[i] = &dataPoint{Observed: y, Variables: rows[i], Entity: d.Entity, Label: d.Label}
+	}
+
+	// A failed fit leaves the model as it was, so that it can be corrected and run again
+	vars, data, initialised := p.model.names.vars, p.model.Data, p.model.initialised
+	p.model.names.vars = nil
+	for i, name := range names {
+		p.model.SetVar(i, name)
+	}
+	p.model.Train(points...)
+	if err := p.model.Run(); err != nil {
+		p.model.names.vars, p.model.Data, p.model.initialised = vars, data, initialised
+		return err
+	}
+	p.hasRun = true
+	return nil
+}
+
+// Transform applies the fitted predictor transforms to vars.
+func (p *Pipeline) Transform(vars []float64) ([]float64, error) {
+	if !p.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	if len(vars) != len(p.inputs) {
+		return nil, fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), len(p.inputs))
+	}
+	var err error
+	for _, step := range p.steps {
+		if vars, err = step.Transform(vars); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// Predict transforms vars, predicts with the regression and transforms the prediction back to the
+// scale of the observed values.
+func (p *Pipeline) Predict(vars []float64) (float64, error) {
+	x, err := p.Transform(vars)
+	if err != nil {
+		return 0, err
+	}
+	z, err := p.model.Predict(x)
+	if err != nil {
+		return 0, err
+	}
+	if p.response != nil {
+		return p.response.Inverse(z), nil
+	}
+	return z, nil
+}
@@ -0,0 +1,120 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	// y = exp(1 + 0.5x + group effect), with group coded 1, 2 or 3
+	effects := map[float64]float64{1: 0, 2: 0.3, 3: -0.2}
+	var points DataPoints
+	for i := 0; i < 30; i++ {
+		x, group := float64(i%10), float64(1+i%3)
+		points = append(points, DataPoint(math.Exp(1+0.5*x+effects[group]), []float64{x, group}))
+	}
+	r := new(Regression)
+	r.SetVar(0, "dose")
+	r.SetVar(1, "group")
+	p := NewPipeline(r).Response(LogResponse()).Add(Standardize(0)).Add(OneHot(1))
+	p.Train(points...)
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"dose", "group[2]", "group[3]"}
+	for i, name := range want {
+		if got := p.Model().GetVar(i); got != name {
+			t.Errorf("Expected variable %d to be %v, got %v", i, name, got)
+		}
+	}
+	got, err := p.Predict([]float64{4, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := math.Exp(1 + 0.5*4 + 0.3); math.Abs(got-want) > 1e-9*want {
+		t.Errorf("Expected prediction %v, got %v", want, got)
+	}
+	if points[0].Variables[1] != 1 || len(points[0].Variables) != 2 {
+		t.Errorf("Expected the training data to be unchanged, got %v", points[0].Variables)
+	}
+
+	if _, err := p.Predict([]float64{4, 7}); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for an unknown level, got %v", err)
+	}
+	if _, err := p.Predict([]float64{4}); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+
+	bad := NewPipeline(new(Regression)).Response(LogResponse())
+	bad.Train(append(DataPoints{DataPoint(-1, []float64{0, 1})}, points...)...)
+	var dpErr *DataPointError
+	if err := bad.Run(); !errors.Is(err, ErrTransform) || !errors.As(err, &dpErr) || dpErr.Index != 0 {
+		t.Errorf("Expected ErrTransform for data point 0, got %v", err)
+	}
+}
+
+func TestPipelineRetry(t *testing.T) {
+	var points DataPoints
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		points = append(points, DataPoint(1+2*x+math.Sin(x), []float64{x, 5}))
+	}
+	r := new(Regression)
+	r.SetVar(0, "dose")
+	r.SetVar(1, "batch")
+	p := NewPipeline(r).Response(LogResponse())
+	p.Train(points...)
+	if err := p.Run(); !errors.Is(err, ErrConstantVariable) {
+		t.Fatalf("Expected ErrConstantVariable, got %v", err)
+	}
+
+	// The failed run leaves the model untrained, so the retry fits the data once
+	r.SetDropConstant(true)
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(p.Model().Data); n != 10 || p.Model().ResidualDF() != 8 {
+		t.Errorf("Expected 10 data points and 8 residual degrees of freedom, got %d and %d", n, p.Model().ResidualDF())
+	}
+	if inputs := p.Inputs(); len(inputs) != 2 || inputs[0] != "dose" || inputs[1] != "batch" {
+		t.Errorf("Expected inputs dose and batch, got %v", inputs)
+	}
+}
+
+func TestBoxCox(t *testing.T) {
+	for _, lambda := range []float64{-1, 0, 0.5, 2} {
+		b := BoxCox(lambda)
+		for _, y := range []float64{0.1, 1, 7} {
+			if got := b.Inverse(b.Forward(y)); math.Abs(got-y) > 1e-12 {
+				t.Errorf("Expected BoxCox(%v) to round trip %v, got %v", lambda, y, got)
+			}
+		}
+		if !math.IsNaN(b.Forward(0)) {
+			t.Errorf("Expected NaN for BoxCox(%v) of 0", lambda)
+		}
+	}
+}
+
+func TestCrossStep(t *testing.T) {
+	var points DataPoints
+	for i := 0; i < 20; i++ {
+		x, g := float64(i), float64(i%2)
+		points = append(points, DataPoint(1+x+2*g*x, []float64{x, g}))
+	}
+	r := new(Regression)
+	r.SetVar(0, "x")
+	r.SetVar(1, "g")
+	p := NewPipeline(r).Add(OneHot(1)).Add(CrossStep(MultiplierCross(0, 1)))
+	p.Train(points...)
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Model().Coeff(3); math.Abs(got-2) > 1e-9 {
+		t.Errorf("Expected an interaction of 2, got %v", got)
+	}
+	if got, _ := p.Predict([]float64{3, 1}); math.Abs(got-10) > 1e-9 {
+		t.Errorf("Expected prediction 10, got %v", got)
+	}
+}
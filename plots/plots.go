@@ -0,0 +1,135 @@
+// Package plots draws diagnostic plots of a fitted regression using gonum/plot. It is a separate
+// module, so that programs that do not import it do not depend on gonum/plot.
+package plots
+
+import (
+	"math"
+
+	"github.com/Synthace/regression"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// ResidualsVsFitted plots the residuals against the fitted values, with a line at zero.
+// Curvature suggests a missing term, and a funnel shape non-constant variance.
+func ResidualsVsFitted(r *regression.Regression) (*plot.Plot, error) {
+	fitted, residuals, err := r.ResidualsVsFitted()
+	if err != nil {
+		return nil, err
+	}
+	p := plot.New()
+	p.Title.Text = "Residuals vs fitted"
+	p.X.Label.Text = "Fitted values"
+	p.Y.Label.Text = "Residuals"
+	if err := addScatter(p, fitted, residuals); err != nil {
+		return nil, err
+	}
+	p.Add(plotter.NewFunction(func(float64) float64 { return 0 }))
+	return p, nil
+}
+
+// QQ plots the sorted standardized residuals against the quantiles of the standard normal
+// distribution. Points should lie close to the line y = x if the residuals are normal.
+func QQ(r *regression.Regression) (*plot.Plot, error) {
+	theoretical, sample, err := r.QQ()
+	if err != nil {
+		return nil, err
+	}
+	p := plot.New()
+	p.Title.Text = "Normal Q-Q"
+	p.X.Label.Text = "Theoretical quantiles"
+	p.Y.Label.Text = "Standardized residuals"
+	if err := addScatter(p, theoretical, sample); err != nil {
+		return nil, err
+	}
+	p.Add(plotter.NewFunction(func(x float64) float64 { return x }))
+	return p, nil
+}
+
+// ScaleLocation plots the square root of the absolute standardized residuals against the fitted
+// values. A trend suggests that the variance changes with the mean.
+func ScaleLocation(r *regression.Regression) (*plot.Plot, error) {
+	fitted, _, err := r.ResidualsVsFitted()
+	if err != nil {
+		return nil, err
+	}
+	standardized, err := r.StandardizedResiduals()
+	if err != nil {
+		return nil, err
+	}
+	scale := make([]float64, len(standardized))
+	for i, e := range standardized {
+		scale[i] = math.Sqrt(math.Abs(e))
+	}
+
+	p := plot.New()
+	p.Title.Text = "Scale-location"
+	p.X.Label.Text = "Fitted values"
+	p.Y.Label.Text = "√|Standardized residuals|"
+	if err := addScatter(p, fitted, scale); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Leverage plots the standardized residuals against the leverage of each point. Points with both
+// high leverage and large residuals have a large influence on the fit.
+func Leverage(r *regression.Regression) (*plot.Plot, error) {
+	leverage, err := r.Leverage()
+	if err != nil {
+		return nil, err
+	}
+	standardized, err := r.StandardizedResiduals()
+	if err != nil {
+		return nil, err
+	}
+
+	p := plot.New()
+	p.Title.Text = "Residuals vs leverage"
+	p.X.Label.Text = "Leverage"
+	p.Y.Label.Text = "Standardized residuals"
+	if err := addScatter(p, leverage, standardized); err != nil {
+		return nil, err
+	}
+	p.Add(plotter.NewFunction(func(float64) float64 { return 0 }))
+	return p, nil
+}
+
+// AddedVariable plots the residuals of the observed value against those of variable i, each regressed
+// on the other variables, with a line through the origin whose slope is the coefficient of the variable.
+func AddedVariable(r *regression.Regression, i int) (*plot.Plot, error) {
+	x, y, err := r.AddedVariable(i)
+	if err != nil {
+		return nil, err
+	}
+	name := r.GetVar(i)
+	observed := r.GetObserved()
+	if observed == "" {
+		observed = "Observed"
+	}
+
+	p := plot.New()
+	p.Title.Text = "Added-variable plot of " + name
+	p.X.Label.Text = name + " | others"
+	p.Y.Label.Text = observed + " | others"
+	if err := addScatter(p, x, y); err != nil {
+		return nil, err
+	}
+	slope := r.Coeff(i + 1)
+	p.Add(plotter.NewFunction(func(x float64) float64 { return slope * x }))
+	return p, nil
+}
+
+func addScatter(p *plot.Plot, x, y []float64) error {
+	xys := make(plotter.XYs, len(x))
+	for i := range x {
+		xys[i].X = x[i]
+		xys[i].Y = y[i]
+	}
+	s, err := plotter.NewScatter(xys)
+	if err != nil {
+		return err
+	}
+	p.Add(s)
+	return nil
+}
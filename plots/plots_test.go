@@ -0,0 +1,51 @@
+package plots
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Synthace/regression"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestPlots(t *testing.T) {
+	r := new(regression.Regression)
+	if _, err := ResidualsVsFitted(r); err != regression.ErrRegressionNotRun {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+
+	r.Train(regression.MakeDataPoints([][]float64{
+		{8.04, 10}, {6.95, 8}, {7.58, 13}, {8.81, 9}, {8.33, 11}, {9.96, 14},
+		{7.24, 6}, {4.26, 4}, {10.84, 12}, {4.82, 7}, {5.68, 5},
+	}, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for name, draw := range map[string]func(*regression.Regression) (*plot.Plot, error){
+		"ResidualsVsFitted": ResidualsVsFitted,
+		"QQ":                QQ,
+		"ScaleLocation":     ScaleLocation,
+		"Leverage":          Leverage,
+		"AddedVariable": func(r *regression.Regression) (*plot.Plot, error) {
+			return AddedVariable(r, 0)
+		},
+	} {
+		p, err := draw(r)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		w, err := p.WriterTo(4*vg.Inch, 4*vg.Inch, "svg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if _, err := w.WriteTo(&buf); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if !bytes.Contains(buf.Bytes(), []byte(p.Title.Text)) {
+			t.Errorf("%s: expected the title in the rendered plot", name)
+		}
+	}
+}
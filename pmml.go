@@ -0,0 +1,107 @@
+package regression
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// pmmlNamespace is the namespace of the PMML version written by WritePMML.
+const pmmlNamespace = "http://www.dmg.org/PMML-4_4"
+
+type pmmlDocument struct {
+	XMLName        xml.Name            `xml:"PMML"`
+	Namespace      string              `xml:"xmlns,attr"`
+	Version        string              `xml:"version,attr"`
+	Header         pmmlHeader          `xml:"Header"`
+	DataDictionary pmmlDataDictionary  `xml:"DataDictionary"`
+	Model          pmmlRegressionModel `xml:"RegressionModel"`
+}
+
+type pmmlHeader struct {
+	Description string `xml:"description,attr"`
+	Application struct {
+		Name string `xml:"name,attr"`
+	} `xml:"Application"`
+}
+
+type pmmlDataDictionary struct {
+	NumberOfFields int             `xml:"numberOfFields,attr"`
+	Fields         []pmmlDataField `xml:"DataField"`
+}
+
+type pmmlDataField struct {
+	Name     string `xml:"name,attr"`
+	OpType   string `xml:"optype,attr"`
+	DataType string `xml:"dataType,attr"`
+}
+
+type pmmlRegressionModel struct {
+	FunctionName  string              `xml:"functionName,attr"`
+	AlgorithmName string              `xml:"algorithmName,attr"`
+	MiningSchema  []pmmlMiningField   `xml:"MiningSchema>MiningField"`
+	Table         pmmlRegressionTable `xml:"RegressionTable"`
+}
+
+type pmmlMiningField struct {
+	Name      string `xml:"name,attr"`
+	UsageType string `xml:"usageType,attr,omitempty"`
+}
+
+type pmmlRegressionTable struct {
+	Intercept  float64                `xml:"intercept,attr"`
+	Predictors []pmmlNumericPredictor `xml:"NumericPredictor"`
+}
+
+type pmmlNumericPredictor struct {
+	Name        string  `xml:"name,attr"`
+	Exponent    int     `xml:"exponent,attr"`
+	Coefficient float64 `xml:"coefficient,attr"`
+}
+
+// WritePMML writes the fitted model as a PMML 4.4 RegressionModel, so that it can be deployed to
+// PMML scoring engines. Models using feature crosses cannot yet be exported.
+func (r *Regression) WritePMML(w io.Writer) error {
+	if len(r.coeff) == 0 {
+		return ErrRegressionNotRun
+	}
+	if len(r.crosses) > 0 {
+		return ErrUnsupportedCross
+	}
+
+	target := r.GetObserved()
+	if target == "" {
+		target = "Y"
+	}
+	doc := pmmlDocument{
+		Namespace: pmmlNamespace,
+		Version:   "4.4",
+		Model: pmmlRegressionModel{
+			FunctionName:  "regression",
+			AlgorithmName: "leastSquares",
+			Table:         pmmlRegressionTable{Intercept: r.Coeff(0)},
+		},
+	}
+	doc.Header.Description = "Linear regression of " + target
+	doc.Header.Application.Name = "github.com/Synthace/regression"
+
+	for i := 0; i < len(r.coeff)-1; i++ {
+		name := r.GetVar(i)
+		doc.DataDictionary.Fields = append(doc.DataDictionary.Fields, pmmlDataField{Name: name, OpType: "continuous", DataType: "double"})
+		doc.Model.MiningSchema = append(doc.Model.MiningSchema, pmmlMiningField{Name: name})
+		doc.Model.Table.Predictors = append(doc.Model.Table.Predictors, pmmlNumericPredictor{Name: name, Exponent: 1, Coefficient: r.Coeff(i + 1)})
+	}
+	doc.DataDictionary.Fields = append(doc.DataDictionary.Fields, pmmlDataField{Name: target, OpType: "continuous", DataType: "double"})
+	doc.DataDictionary.NumberOfFields = len(doc.DataDictionary.Fields)
+	doc.Model.MiningSchema = append(doc.Model.MiningSchema, pmmlMiningField{Name: target, UsageType: "target"})
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
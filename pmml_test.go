@@ -0,0 +1,68 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWritePMML(t *testing.T) {
+	r := new(Regression)
+	var buf bytes.Buffer
+	if err := r.WritePMML(&buf); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+
+	r.SetObserved("Murders")
+	r.SetVar(0, "Inhabitants")
+	r.Train(MakeDataPoints([][]float64{
+		{11.2, 587000, 16.5}, {13.4, 643000, 20.5}, {40.7, 635000, 26.3},
+		{5.3, 692000, 16.5}, {24.8, 1248000, 19.2}, {12.7, 643000, 16.5},
+	}, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WritePMML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) || !strings.Contains(buf.String(), `xmlns="http://www.dmg.org/PMML-4_4"`) {
+		t.Errorf("Expected a PMML 4.4 document, got\n%s", buf.String())
+	}
+
+	var doc pmmlDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.DataDictionary.NumberOfFields != 3 || doc.DataDictionary.Fields[2].Name != "Murders" {
+		t.Errorf("Unexpected data dictionary %+v", doc.DataDictionary)
+	}
+	if last := doc.Model.MiningSchema[2]; last.Name != "Murders" || last.UsageType != "target" {
+		t.Errorf("Expected the observed value to be the target, got %+v", last)
+	}
+	table := doc.Model.Table
+	if table.Intercept != r.Coeff(0) || len(table.Predictors) != 2 {
+		t.Fatalf("Unexpected regression table %+v", table)
+	}
+	if table.Predictors[0].Name != "Inhabitants" || table.Predictors[1].Name != "X1" {
+		t.Errorf("Unexpected predictors %+v", table.Predictors)
+	}
+	for i, p := range table.Predictors {
+		if p.Coefficient != r.Coeff(i+1) {
+			t.Errorf("Expected coefficient %v, got %v", r.Coeff(i+1), p.Coefficient)
+		}
+	}
+}
+
+func TestWritePMMLCrosses(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WritePMML(new(bytes.Buffer)); !errors.Is(err, ErrUnsupportedCross) {
+		t.Errorf("Expected ErrUnsupportedCross, got %v", err)
+	}
+}
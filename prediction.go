@@ -0,0 +1,96 @@
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// TermContribution is the part of a prediction due to a single term of the model.
+type TermContribution struct {
+	Name string
+	// Value is the coefficient of the term times the value of its feature, so that the contributions
+	// of a prediction sum to its value.
+	Value float64
+}
+
+// Prediction is a prediction with everything known about it, gathering Predict, PredictInterval and
+// PredictChecked into a single call.
+type Prediction struct {
+	Value float64
+	// StdErr is the standard error of the predicted mean, and Sigma the standard deviation of a new
+	// observation about it, as in PredictionInterval.
+	StdErr float64
+	Sigma  float64
+	// ConfLow and ConfHigh bound the 95% confidence interval of the mean, and PredLow and PredHigh
+	// the 95% prediction interval of a new observation. Like StdErr and Sigma they are NaN when the
+	// covariance of the coefficients is unavailable.
+	ConfLow  float64
+	ConfHigh float64
+	PredLow  float64
+	PredHigh float64
+	// Contributions holds the contribution of the offset followed by that of each variable, in the
+	// order of NamedCoeffs.
+	Contributions []TermContribution
+	// OutOfRange, Leverage, MaxLeverage and Extrapolated flag extrapolation as in CheckedPrediction.
+	// Models without training data cannot be checked, so their leverages are NaN and OutOfRange nil.
+	OutOfRange   []int
+	Leverage     float64
+	MaxLeverage  float64
+	Extrapolated bool
+}
+
+// PredictDetailed predicts the observed value for vars with its standard error, 95% confidence and
+// prediction intervals, the contribution of each term, and checks of whether it extrapolates beyond
+// the training data.
+func (r *Regression) PredictDetailed(vars []float64) (Prediction, error) {
+	value, err := r.Predict(vars)
+	if err != nil {
+		return Prediction{}, err
+	}
+	nan := math.NaN()
+	p := Prediction{
+		Value:       value,
+		StdErr:      nan,
+		Sigma:       nan,
+		ConfLow:     nan,
+		ConfHigh:    nan,
+		PredLow:     nan,
+		PredHigh:    nan,
+		Leverage:    nan,
+		MaxLeverage: nan,
+	}
+	// Models decoded or built without training data cannot be checked for extrapolation
+	if len(r.Data) > 0 {
+		checked, err := r.checkExtrapolation(vars, value)
+		if err != nil {
+			return Prediction{}, err
+		}
+		p.OutOfRange, p.Extrapolated = checked.OutOfRange, checked.Extrapolated
+		p.Leverage, p.MaxLeverage = checked.Leverage, checked.MaxLeverage
+	}
+
+	features := r.features(vars)
+	p.Contributions = make([]TermContribution, len(r.coeff))
+	for i, c := range r.coeff {
+		x := 1.0
+		if i > 0 {
+			x = features[i-1]
+		}
+		p.Contributions[i] = TermContribution{Name: r.coeffName(i), Value: c * x}
+	}
+
+	interval, err := r.predictionInterval(vars, value, 0.95)
+	switch {
+	case errors.Is(err, ErrSingular):
+		return p, nil
+	case err != nil:
+		return Prediction{}, err
+	}
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(r.residualDF)}.Quantile(0.975)
+	p.StdErr, p.Sigma = interval.StdErr, interval.Sigma
+	p.ConfLow, p.ConfHigh = value-t*p.StdErr, value+t*p.StdErr
+	p.PredLow, p.PredHigh = interval.Lower, interval.Upper
+	return p, nil
+}
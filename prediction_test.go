@@ -0,0 +1,60 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPredictDetailed(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "dose")
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := []float64{16}
+	p, err := r.PredictDetailed(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interval, _ := r.PredictInterval(vars, 0.95)
+	checked, _ := r.PredictChecked(vars)
+	if p.Value != interval.Value || p.StdErr != interval.StdErr || p.PredLow != interval.Lower || p.PredHigh != interval.Upper {
+		t.Errorf("Expected the prediction interval %+v, got %+v", interval, p)
+	}
+	if !(p.PredLow < p.ConfLow && p.ConfLow < p.Value && p.Value < p.ConfHigh && p.ConfHigh < p.PredHigh) {
+		t.Errorf("Expected the confidence interval within the prediction interval, got %+v", p)
+	}
+	if !p.Extrapolated || p.Leverage != checked.Leverage || len(p.OutOfRange) != 1 {
+		t.Errorf("Expected the extrapolation checks %+v, got %+v", checked, p)
+	}
+
+	names := []string{offsetName, "dose", "(dose)^2"}
+	var sum float64
+	for i, c := range p.Contributions {
+		sum += c.Value
+		if i < len(names) && c.Name != names[i] {
+			t.Errorf("Expected term %d to be %q, got %q", i, names[i], c.Name)
+		}
+	}
+	if len(p.Contributions) != len(names) || math.Abs(sum-p.Value) > 1e-9 {
+		t.Errorf("Expected contributions summing to %v, got %+v", p.Value, p.Contributions)
+	}
+
+	if _, err := r.PredictDetailed([]float64{1, 2}); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+
+	// A model without training data predicts without the extrapolation checks
+	m := FromCoefficients(1, map[string]float64{"x": 2})
+	p, err = m.PredictDetailed([]float64{3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Value != 7 || p.OutOfRange != nil || p.Extrapolated || !math.IsNaN(p.Leverage) || !math.IsNaN(p.MaxLeverage) || !math.IsNaN(p.StdErr) {
+		t.Errorf("Expected a prediction of 7 without extrapolation checks, got %+v", p)
+	}
+}
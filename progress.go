@@ -0,0 +1,81 @@
+package regression
+
+import (
+	"sync"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Progress reports how far a long running fit or search has got, for progress bars in user interfaces
+// and command line tools. Fields that do not apply to the stage are zero.
+type Progress struct {
+	// Stage names what is running, such as "fit", "update" or "grid search".
+	Stage string
+	// Rows is the number of data points ingested by the fit.
+	Rows int
+	// Iteration is the current iteration of an iterative solver, and Loss the objective it minimizes,
+	// the residual sum of squares or the negative log likelihood.
+	Iteration int
+	Loss      float64
+	// Done and Total count the completed and total jobs of a search or resampling method.
+	Done, Total int
+}
+
+// SetProgress sets a function called as the regression is fitted: with the rows ingested when Run starts
+// and once Update has added its points, and with each iteration of an iterative solver. It is called synchronously, so it
+// should return quickly, for example by sending on a buffered channel without blocking. Copies of the
+// regression fitted by resampling methods such as Bag do not report; use WithProgress to follow them.
+func (r *Regression) SetProgress(fn func(Progress)) {
+	r.progress = fn
+}
+
+// reportProgress calls the progress function of the regression, if it has one.
+func (r *Regression) reportProgress(p Progress) {
+	if r.progress != nil {
+		r.progress(p)
+	}
+}
+
+// SetProgress sets a function called with each Levenberg-Marquardt iteration of Run, as for Regression.
+func (n *Nonlinear) SetProgress(fn func(Progress)) {
+	n.progress = fn
+}
+
+// SetProgress sets a function called with each iteration of the hyperparameter search, as for Regression.
+func (g *GaussianProcess) SetProgress(fn func(Progress)) {
+	g.progress = fn
+}
+
+// track wraps the jobs of a parallel method so that the progress function, if set, is called as each
+// job completes. Calls are serialized, so the function need not be safe for concurrent use.
+func (o *options) track(stage string, total int, fn func(i int)) func(i int) {
+	if o.progress == nil {
+		return fn
+	}
+	var mu sync.Mutex
+	done := 0
+	return func(i int) {
+		fn(i)
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		o.progress(Progress{Stage: stage, Done: done, Total: total})
+	}
+}
+
+// progressRecorder reports the major iterations of a gonum optimization to a progress function.
+type progressRecorder struct {
+	fn   func(Progress)
+	rows int
+}
+
+func (p progressRecorder) Init() error {
+	return nil
+}
+
+func (p progressRecorder) Record(loc *optimize.Location, op optimize.Operation, stats *optimize.Stats) error {
+	if op == optimize.MajorIteration {
+		p.fn(Progress{Stage: "fit", Rows: p.rows, Iteration: stats.MajorIterations, Loss: loc.F})
+	}
+	return nil
+}
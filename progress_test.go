@@ -0,0 +1,80 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	// A censored fit reports its rows and then its solver iterations
+	rng := rand.New(rand.NewSource(8))
+	r := new(Regression)
+	for i := 0; i < 100; i++ {
+		x := rng.Float64() * 2
+		if y := 1 + 2*x + rng.NormFloat64(); y < 3 {
+			r.Train(CensoredDataPoint(math.Inf(-1), 3, []float64{x}))
+		} else {
+			r.Train(DataPoint(y, []float64{x}))
+		}
+	}
+	var reports []Progress
+	r.SetProgress(func(p Progress) {
+		reports = append(reports, p)
+	})
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) < 2 || reports[0].Rows != 100 || reports[0].Iteration != 0 {
+		t.Fatalf("Expected the rows and then the iterations to be reported, got %v", reports)
+	}
+	last := reports[len(reports)-1]
+	if last.Iteration != r.Iterations() || math.Abs(last.Loss+r.LogLikelihood()) > 1e-6 {
+		t.Errorf("Expected the last report at iteration %d with loss %v, got %+v", r.Iterations(), -r.LogLikelihood(), last)
+	}
+
+	// Copies fitted in parallel by resampling methods do not report, as their calls would not be serialized
+	reported := len(reports)
+	if _, err := r.Bag(4, WithSeed(1), WithConcurrency(4)); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != reported {
+		t.Errorf("Expected no reports from bagged copies, got %d", len(reports)-reported)
+	}
+
+	// Nonlinear fits report the residual sum of squares of each iteration
+	n := NewNonlinear(FourParameterLogistic())
+	n.Train(logisticData(0.02, rand.New(rand.NewSource(1)))...)
+	var losses []float64
+	n.SetProgress(func(p Progress) {
+		losses = append(losses, p.Loss)
+	})
+	if err := n.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(losses) != n.Iterations() || losses[len(losses)-1] != n.ResidualSS() {
+		t.Errorf("Expected %d losses ending at %v, got %v", n.Iterations(), n.ResidualSS(), losses)
+	}
+
+	// Searches report each completed job
+	data := make(DataPoints, 30)
+	for i := range data {
+		x := float64(i)
+		data[i] = DataPoint(1+2*x+rng.NormFloat64(), []float64{x})
+	}
+	var done []int
+	_, err := GridSearch(data, map[string][]float64{"unused": {1, 2}}, func(map[string]float64) *Regression {
+		return new(Regression)
+	}, 3, WithSeed(1), WithProgress(func(p Progress) {
+		if p.Stage != "grid search" || p.Total != 6 {
+			t.Errorf("Expected 6 grid search jobs, got %+v", p)
+		}
+		done = append(done, p.Done)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 6 || done[5] != 6 {
+		t.Errorf("Expected jobs 1 to 6 to be reported done, got %v", done)
+	}
+}
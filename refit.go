@@ -0,0 +1,86 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+)
+
+// defaultOutlierThreshold is the absolute studentized residual above which RobustRefit excludes a
+// point if no threshold is given.
+const defaultOutlierThreshold = 3
+
+// RobustRefit is the result of refitting a model without its outliers.
+type RobustRefit struct {
+	Threshold float64
+	Original  *Regression
+	// Refit is the model fitted without the excluded points, or Original if none were excluded.
+	Refit *Regression
+	// Excluded holds the indices in Original.Data of the excluded points, and Labels their labels,
+	// or nil if none of them are labeled.
+	Excluded []int
+	Labels   []string
+	Terms    []RefitTerm
+}
+
+// RefitTerm compares a coefficient of the original and refitted models.
+type RefitTerm struct {
+	Term           string
+	Original       float64
+	OriginalStdErr float64
+	Refit          float64
+	RefitStdErr    float64
+	// Change is the change in the coefficient in units of its original standard error.
+	Change float64
+}
+
+// RobustRefit flags the data points whose externally studentized residuals exceed the threshold in
+// absolute value, 3 if the threshold is zero, and refits the model without them. Both fits are
+// reported with the list of excluded points, so the influence of the outliers can be judged rather
+// than the points being silently dropped.
+func (r *Regression) RobustRefit(threshold float64) (*RobustRefit, error) {
+	if threshold == 0 {
+		threshold = defaultOutlierThreshold
+	}
+	studentized, err := r.StudentizedResiduals()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RobustRefit{Threshold: threshold, Original: r, Refit: r}
+	var kept, excluded DataPoints
+	for i, d := range r.rawData() {
+		if math.Abs(studentized[i]) > threshold {
+			res.Excluded = append(res.Excluded, i)
+			excluded = append(excluded, d)
+		} else {
+			kept = append(kept, d)
+		}
+	}
+	res.Labels = labels(excluded)
+	if len(res.Excluded) > 0 {
+		if res.Refit, err = r.fitLike(kept); err != nil {
+			return nil, fmt.Errorf("refitting without %d outliers: %w", len(res.Excluded), err)
+		}
+		r.logger().Debug("refitted without outliers", "excluded", res.Excluded, "threshold", threshold)
+	}
+
+	original, err := r.Tidy()
+	if err != nil {
+		return nil, err
+	}
+	refit, err := res.Refit.Tidy()
+	if err != nil {
+		return nil, err
+	}
+	for i, o := range original {
+		res.Terms = append(res.Terms, RefitTerm{
+			Term:           o.Term,
+			Original:       o.Estimate,
+			OriginalStdErr: o.StdErr,
+			Refit:          refit[i].Estimate,
+			RefitStdErr:    refit[i].StdErr,
+			Change:         (refit[i].Estimate - o.Estimate) / o.StdErr,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,52 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRobustRefit(t *testing.T) {
+	// Anscombe's third dataset is an exact line apart from one outlier
+	data := [][]float64{
+		{7.46, 10}, {6.77, 8}, {12.74, 13}, {7.11, 9}, {7.81, 11}, {8.84, 14},
+		{6.08, 6}, {5.39, 4}, {8.15, 12}, {6.42, 7}, {5.73, 5},
+	}
+	r := new(Regression)
+	for i, row := range data {
+		label := ""
+		if i == 2 {
+			label = "outlier"
+		}
+		r.Train(LabeledDataPoint(label, row[0], row[1:]))
+	}
+	r.Run()
+
+	res, err := r.RobustRefit(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Excluded) != 1 || res.Excluded[0] != 2 || res.Labels[0] != "outlier" {
+		t.Fatalf("Expected point 2 to be excluded, got %v %v", res.Excluded, res.Labels)
+	}
+	if len(res.Refit.Data) != len(r.Data)-1 {
+		t.Errorf("Expected %d points in the refit, got %d", len(r.Data)-1, len(res.Refit.Data))
+	}
+	if len(res.Terms) != 2 || res.Terms[1].Term != r.GetVar(0) || res.Terms[1].Original != r.Coeff(1) {
+		t.Errorf("Unexpected comparison %+v", res.Terms)
+	}
+	if math.Abs(res.Terms[1].Refit-0.345) > 0.01 {
+		t.Errorf("Expected a slope of about 0.345 without the outlier, got %v", res.Terms[1].Refit)
+	}
+
+	// Without outliers the original fit is kept
+	clean := new(Regression)
+	clean.Train(MakeDataPoints(anscombe, 0)...)
+	clean.Run()
+	res, err = clean.RobustRefit(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Refit != clean || len(res.Excluded) != 0 || res.Terms[0].Change != 0 {
+		t.Errorf("Expected the original fit to be kept, got %+v", res)
+	}
+}
@@ -3,9 +3,12 @@ package regression
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"gonum.org/v1/gonum/mat"
 )
@@ -17,20 +20,115 @@ var (
 	ErrTooManyVars = errors.New("not enough observations to to support this many variables")
 	// ErrRegressionRun signals that the Run method has already been called on the trained dataset.
 	ErrRegressionRun = errors.New("regression has already been run")
+	// ErrRegressionNotRun signals that a fitted model is required but Run has not been called.
+	ErrRegressionNotRun = errors.New("regression has not been run")
+	// ErrSingular signals that the design matrix is singular and cannot be inverted.
+	ErrSingular = errors.New("design matrix is singular")
+	// ErrVariableIndex signals that a variable index does not refer to one of the variables.
+	ErrVariableIndex = errors.New("variable index out of range")
+	// ErrUnderidentified signals that there are fewer instruments than endogenous variables.
+	ErrUnderidentified = errors.New("fewer instruments than endogenous variables")
+	// ErrIncompatibleOptions signals that the regression has been configured with options that cannot be combined.
+	ErrIncompatibleOptions = errors.New("incompatible regression options")
+	// ErrUnknownEntity signals that a panel entity was not present in the training data.
+	ErrUnknownEntity = errors.New("unknown entity")
+	// ErrConstantVariable signals that one or more variables have zero variance.
+	ErrConstantVariable = errors.New("variable has zero variance")
+	// ErrDesign signals that a design matrix could not be built from the given columns.
+	ErrDesign = errors.New("invalid design")
+	// ErrUnsupportedCross signals that an operation does not support the feature crosses in the model.
+	ErrUnsupportedCross = errors.New("unsupported feature cross")
+	// ErrCriterion signals that an unknown criterion was requested.
+	ErrCriterion = errors.New("unknown criterion")
+	// ErrSignificance signals that a statistical test does not support the requested significance level.
+	ErrSignificance = errors.New("unsupported significance level")
+	// ErrHypothesis signals that a linear hypothesis is malformed or cannot be tested.
+	ErrHypothesis = errors.New("invalid hypothesis")
+	// ErrConstraint signals that constraints on the coefficients are malformed or cannot be satisfied.
+	ErrConstraint = errors.New("invalid constraint")
+	// ErrVariableCount signals that a data point has a different number of variables to the model.
+	ErrVariableCount = errors.New("wrong number of variables")
+	// ErrDataIndex signals that a data point index is out of range.
+	ErrDataIndex = errors.New("data point index out of range")
+	// ErrTransform signals that a value is outside the domain of a transform.
+	ErrTransform = errors.New("value outside the domain of the transform")
+	// ErrModelFormat signals that an encoded model is malformed or cannot be encoded.
+	ErrModelFormat = errors.New("invalid model format")
+	// ErrNotConverged signals that an iterative fit did not converge.
+	ErrNotConverged = errors.New("fit did not converge")
+	// ErrDuplicate signals that a data point appears more than once in a dataset.
+	ErrDuplicate = errors.New("duplicate data point")
 )
 
+// DataPointError reports a problem with a single data point. It wraps one of the sentinel errors,
+// so errors.Is can still be used, and errors.As recovers the index of the data point.
+type DataPointError struct {
+	Index int
+	Err   error
+}
+
+func (e *DataPointError) Error() string {
+	return fmt.Sprintf("data point %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DataPointError) Unwrap() error {
+	return e.Err
+}
+
 // Regression is the exposed data structure for interacting with the API.
 type Regression struct {
 	names             describe
 	Data              []*dataPoint
-	coeff             map[int]float64
+	coeff             []float64
 	R2                float64
+	AdjustedR2        float64
 	Varianceobserved  float64
 	VariancePredicted float64
 	initialised       bool
 	Formula           string
 	crosses           []featureCross
 	hasRun            bool
+	cov               *mat.SymDense
+	endogenous        []int
+	instruments       []int
+	firstStage        []FirstStage
+	fixedEffects      bool
+	entityEffects     map[string]float64
+	withinR2          float64
+	rawVars           int
+	collinearityTol   float64
+	dropConstant      bool
+	legacyStatistics  bool
+	residualDF        int
+	residualStdErr    float64
+	totalSS           float64
+	truncation        *interval
+	mlSigma           float64
+	logLik            float64
+	residualSS        float64
+	dropped           []DroppedVariable
+	log               *slog.Logger
+	warnings          []Warning
+	formulaPrecision  *int
+	constraints       []linearConstraint
+	signs             map[int]CoeffSign
+	factor            *triangularFactor
+	internalScaling   bool
+	solver            Solver
+	solverUsed        Solver
+	covType           CovarianceType
+	varianceModel     bool
+	varianceCoeffs    []float64
+	convergence       Convergence
+	iterations        int
+	converged         bool
+	progress          func(Progress)
+	metadata          *FitMetadata
+	extrapolation     *extrapolationBounds
+	// internal marks a copy fitted within the package to resample or validate a model, whose fits are
+	// not instrumented.
+	internal bool
 }
 
 type dataPoint struct {
@@ -38,6 +136,9 @@ type dataPoint struct {
 	Variables []float64
 	Predicted float64
 	Error     float64
+	Entity    string
+	Label     string
+	censored  *interval
 }
 
 type describe struct {
@@ -54,24 +155,92 @@ func DataPoint(obs float64, vars []float64) *dataPoint {
 	return &dataPoint{Observed: obs, Variables: vars}
 }
 
+// LabeledDataPoint creates a well formed *datapoint with a label, such as a sample barcode, that identifies
+// it in residual tables, warnings and exported results.
+func LabeledDataPoint(label string, obs float64, vars []float64) *dataPoint {
+	return &dataPoint{Observed: obs, Variables: vars, Label: label}
+}
+
 // Predict updates the "Predicted" value for the inputed features.
 func (r *Regression) Predict(vars []float64) (float64, error) {
+	p, err := r.predict(vars)
+	instrumentPredict("regression", 1, err)
+	return p, err
+}
+
+// predict returns the prediction for vars without instrumenting it, for use within the package.
+func (r *Regression) predict(vars []float64) (float64, error) {
 	if !r.initialised {
-		return 0, ErrNotEnoughData
+		return 0, fmt.Errorf("%w: %d data points trained, need at least 3", ErrNotEnoughData, len(r.Data))
 	}
-
-	// apply any features crosses to vars
-	for _, cross := range r.crosses {
-		vars = append(vars, cross.Calculate(vars)...)
+	if len(r.coeff) > 0 && len(vars) != r.rawVars {
+		return 0, fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), r.rawVars)
 	}
 
+	vars = r.features(vars)
+
 	p := r.Coeff(0)
-	for j := 1; j < len(r.Data[0].Variables)+1; j++ {
+	for j := 1; j < len(r.coeff); j++ {
 		p += r.Coeff(j) * vars[j-1]
 	}
 	return p, nil
 }
 
+// FromCoefficients creates a model for prediction only from coefficients estimated elsewhere, such as in R,
+// Python or an earlier run. The variables are ordered by name, so Predict expects their values in sorted
+// order, and GetVar reports the name of each. The model has no training data, so diagnostics that need
+// it return ErrNotEnoughData.
+func FromCoefficients(intercept float64, coeffs map[string]float64) *Regression {
+	names := make([]string, 0, len(coeffs))
+	for name := range coeffs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r := &Regression{initialised: true, hasRun: true, rawVars: len(names)}
+	r.coeff = make([]float64, len(names)+1)
+	r.coeff[0] = intercept
+	for i, name := range names {
+		r.SetVar(i, name)
+		r.coeff[i+1] = coeffs[name]
+	}
+	r.setFormula()
+	return r
+}
+
+// requireData returns an error unless the regression has been run on training data.
+func (r *Regression) requireData() error {
+	if !r.hasRun {
+		return ErrRegressionNotRun
+	}
+	if len(r.Data) == 0 {
+		return ErrNotEnoughData
+	}
+	return nil
+}
+
+// PredictBatch predicts the observed value for each row of variables, in parallel.
+func (r *Regression) PredictBatch(vars [][]float64, opts ...Option) ([]float64, error) {
+	const chunk = 1024
+	o := newOptions(opts)
+	predictions := make([]float64, len(vars))
+	errs := make([]error, len(vars))
+	parallelFor(o.concurrency, (len(vars)+chunk-1)/chunk, func(c int) {
+		for i := c * chunk; i < len(vars) && i < (c+1)*chunk; i++ {
+			predictions[i], errs[i] = r.predict(vars[i])
+		}
+	})
+	for i, err := range errs {
+		if err != nil {
+			err = &DataPointError{Index: i, Err: err}
+			instrumentPredict("regression", len(vars), err)
+			return nil, err
+		}
+	}
+	instrumentPredict("regression", len(vars), nil)
+	return predictions, nil
+}
+
 // SetObserved sets the name of the observed value.
 func (r *Regression) SetObserved(name string) {
 	r.names.obs = name
@@ -100,6 +269,77 @@ func (r *Regression) GetVar(i int) string {
 	return x
 }
 
+// NumVars returns the number of variables supplied with each data point, before any feature crosses.
+func (r *Regression) NumVars() int {
+	raw, _ := r.numVars()
+	return raw
+}
+
+// NumFeatures returns the number of variables in the model, including those generated by feature crosses.
+func (r *Regression) NumFeatures() int {
+	_, total := r.numVars()
+	return total
+}
+
+// Variables returns the name of each variable in the model in order, including those generated by
+// feature crosses. Variables without a name are called X followed by their index, as in GetVar.
+func (r *Regression) Variables() []string {
+	_, total := r.numVars()
+	names := make([]string, total)
+	for i := range names {
+		names[i] = r.GetVar(i)
+	}
+	return names
+}
+
+// CrossIndices returns the indices of the variables generated by feature crosses, which follow the
+// variables supplied with each data point.
+func (r *Regression) CrossIndices() []int {
+	raw, total := r.numVars()
+	var indices []int
+	for i := raw; i < total; i++ {
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+// CrossInfo describes a feature cross of a model.
+type CrossInfo struct {
+	ID string
+	// Inputs holds the indices of the variables the cross is calculated from, and Outputs the indices
+	// of the variables it generates.
+	Inputs  []int
+	Outputs []int
+}
+
+// Crosses describes the feature crosses of the model in the order they are applied, so that tools can
+// relate the variables they generate to the variables they are calculated from.
+func (r *Regression) Crosses() []CrossInfo {
+	next, _ := r.numVars()
+	info := make([]CrossInfo, len(r.crosses))
+	for i, cross := range r.crosses {
+		info[i] = CrossInfo{ID: cross.ID(), Inputs: cross.Inputs()}
+		for k := 0; k < cross.Outputs(); k++ {
+			info[i].Outputs = append(info[i].Outputs, next)
+			next++
+		}
+	}
+	return info
+}
+
+// numVars returns the number of variables before and after feature crosses. Before Run, or if it
+// did not fit any coefficients, they are worked out from the first data point.
+func (r *Regression) numVars() (raw, total int) {
+	if r.hasRun && len(r.coeff) > 0 {
+		return r.rawVars, len(r.coeff) - 1
+	}
+	if len(r.Data) == 0 {
+		return 0, 0
+	}
+	vars := r.Data[0].Variables
+	return len(vars), len(r.features(vars))
+}
+
 // AddCross registers a feature cross to be applied to the data points.
 func (r *Regression) AddCross(cross featureCross) {
 	r.crosses = append(r.crosses, cross)
@@ -113,16 +353,23 @@ func (r *Regression) Train(d ...*dataPoint) {
 	}
 }
 
-// Apply any feature crosses, generating new observations and updating the data points, as well as
-// populating variable names for the feature crosses.
+// features returns the variables followed by the features generated by any feature crosses, without
+// writing into the caller's backing array.
+func (r *Regression) features(vars []float64) []float64 {
+	vars = vars[:len(vars):len(vars)]
+	for _, cross := range r.crosses {
+		vars = append(vars, cross.Calculate(vars)...)
+	}
+	return vars
+}
+
+// Apply any feature crosses, recording the number of variables before them and populating variable
+// names for the feature crosses. The data points are not changed: the crossed features are calculated
+// as the design matrix is built.
 // this should only be run once, as part of Run().
 func (r *Regression) applyCrosses() {
 	unusedVariableIndexCursor := len(r.Data[0].Variables)
-	for _, point := range r.Data {
-		for _, cross := range r.crosses {
-			point.Variables = append(point.Variables, cross.Calculate(point.Variables)...)
-		}
-	}
+	r.rawVars = unusedVariableIndexCursor
 
 	if len(r.names.vars) == 0 {
 		r.names.vars = make(map[int]string, 5)
@@ -136,42 +383,172 @@ func (r *Regression) applyCrosses() {
 // and whether or not the training has already been completed.
 // Once the above checks have passed feature crosses are applied if any
 // and the model is trained using QR decomposition.
-func (r *Regression) Run() error {
+func (r *Regression) Run() (err error) {
+	defer func(start time.Time) {
+		if !r.internal {
+			instrumentFit("regression", start, err)
+		}
+	}(time.Now())
 	if !r.initialised {
-		return ErrNotEnoughData
+		return fmt.Errorf("%w: %d data points trained, need at least 3", ErrNotEnoughData, len(r.Data))
 	}
 	if r.hasRun {
 		return ErrRegressionRun
 	}
+	for i, d := range r.Data {
+		if len(d.Variables) != len(r.Data[0].Variables) {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: has %d, expected %d as in data point 0", ErrVariableCount, len(d.Variables), len(r.Data[0].Variables))}
+		}
+	}
+
+	// Leave out any rows that cannot be fitted
+	r.warnings = nil
+	r.dropNonFinite()
+	if len(r.Data) < 3 {
+		return fmt.Errorf("%w: %d data points remain after dropping non-finite values, need at least 3", ErrNotEnoughData, len(r.Data))
+	}
+
+	r.reportProgress(Progress{Stage: "fit", Rows: len(r.Data)})
+
+	mle := r.maximumLikelihood()
+	if mle {
+		if err := r.checkCensoring(); err != nil {
+			return err
+		}
+	}
 
 	//apply any features crosses
 	r.applyCrosses()
 	r.hasRun = true
+	r.extrapolation = new(extrapolationBounds)
+	// A failed fit leaves the model untrained, so that it can be corrected and run again
+	defer func() {
+		if err != nil {
+			r.hasRun = false
+		}
+	}()
 
 	observations := len(r.Data)
-	numOfvars := len(r.Data[0].Variables)
+	numOfvars := len(r.features(r.Data[0].Variables))
 
 	if observations < (numOfvars + 1) {
-		return ErrTooManyVars
+		return fmt.Errorf("%w: %d observations for %d variables and the offset", ErrTooManyVars, observations, numOfvars)
 	}
 
-	// Create some blank variable space
-	observed := mat.NewDense(observations, 1, nil)
-	variables := mat.NewDense(observations, numOfvars+1, nil)
+	observed, variables := r.designMatrix()
 
-	for i := 0; i < observations; i++ {
-		observed.Set(i, 0, r.Data[i].Observed)
-		for j := 0; j < numOfvars+1; j++ {
-			if j == 0 {
-				variables.Set(i, 0, 1)
-			} else {
-				variables.Set(i, j, r.Data[i].Variables[j-1])
-			}
+	// Drop any collinear columns before fitting
+	instrumented := len(r.endogenous) > 0 || len(r.instruments) > 0
+	constrained := len(r.constraints) > 0
+	active, err := r.pruneColumns(variables)
+	if err != nil {
+		return err
+	}
+	if len(active) < numOfvars+1 {
+		if instrumented {
+			return fmt.Errorf("%w: variables cannot be dropped from an instrumented regression", ErrIncompatibleOptions)
+		}
+		if constrained {
+			return fmt.Errorf("%w: variables cannot be dropped from a constrained regression", ErrIncompatibleOptions)
 		}
+		variables = columns(variables, active)
 	}
 
 	// Now run the regression
-	_, n := variables.Dims() // cols
+	var c []float64
+	r.iterations, r.converged = 0, true
+	switch {
+	case r.fixedEffects && instrumented:
+		return fmt.Errorf("%w: fixed effects cannot be combined with instruments", ErrIncompatibleOptions)
+	case constrained && (r.fixedEffects || instrumented):
+		return fmt.Errorf("%w: constraints cannot be combined with fixed effects or instruments", ErrIncompatibleOptions)
+	case r.signConstrained() && (constrained || r.fixedEffects || instrumented):
+		return fmt.Errorf("%w: sign constraints cannot be combined with other constraints, fixed effects or instruments", ErrIncompatibleOptions)
+	case r.covType != Classical && (constrained || r.signConstrained() || r.fixedEffects || instrumented):
+		return fmt.Errorf("%w: heteroscedasticity consistent covariance requires an unconstrained least squares fit", ErrIncompatibleOptions)
+	case mle && (constrained || r.signConstrained() || r.fixedEffects || instrumented || r.covType != Classical):
+		return fmt.Errorf("%w: censored or truncated data can only be fitted without constraints, fixed effects, instruments or robust covariance", ErrIncompatibleOptions)
+	case mle:
+		r.logger().Debug("fitting censored regression by maximum likelihood", "observations", observations, "variables", len(active)-1)
+		c, err = r.censoredMaximumLikelihood(observed, variables)
+	case r.signConstrained():
+		r.logger().Debug("fitting sign constrained least squares regression", "observations", observations, "variables", len(active)-1)
+		c, err = r.signConstrainedLeastSquares(observed, variables, active)
+	case constrained:
+		r.logger().Debug("fitting constrained least squares regression", "observations", observations, "variables", len(active)-1, "constraints", len(r.constraints))
+		c, err = r.constrainedLeastSquares(observed, variables)
+	case r.fixedEffects:
+		r.logger().Debug("fitting fixed effects regression", "observations", observations, "variables", len(active)-1)
+		c, err = r.withinEstimator(observed, variables)
+	case instrumented:
+		r.logger().Debug("fitting two stage least squares regression", "observations", observations, "variables", len(active)-1)
+		c, err = r.twoStageLeastSquares(observed, variables)
+	default:
+		r.solverUsed = r.chooseSolver(variables)
+		r.logger().Debug("fitting least squares regression", "observations", observations, "variables", len(active)-1, "solver", r.solverUsed, "scaled", r.internalScaling)
+		switch {
+		case r.solverUsed == SolverSVD || r.solverUsed == SolverRidge:
+			c = r.svdLeastSquares(observed, variables, r.solverUsed)
+		case r.solverUsed == SolverBigFloat:
+			c, err = r.bigFloatLeastSquares(observed, variables)
+		case r.internalScaling:
+			c = r.scaledLeastSquares(observed, variables)
+		default:
+			c = r.ordinaryLeastSquares(observed, variables)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !r.converged {
+		r.warn(NotConverged, nil, nil, "the fit did not converge after %d iterations", r.iterations)
+	}
+	if r.covType != Classical {
+		clusters := make([]string, len(r.Data))
+		for i, d := range r.Data {
+			clusters[i] = d.Entity
+		}
+		r.cov = robustCovariance(r.covType, variables, observed, c, clusters)
+	}
+	if r.cov == nil {
+		r.logger().Warn("design matrix is near singular, standard errors are unavailable")
+	}
+	r.checkConditioning(variables)
+	c = r.expandColumns(c, active, numOfvars+1)
+
+	// Output the regression results
+	r.coeff = c
+	r.setFormula()
+
+	r.calcPredicted()
+	r.calcVariance()
+	r.calcR2()
+	if mle {
+		// The residuals of censored points are not observed, so report the fitted σ
+		r.residualStdErr = r.mlSigma
+	}
+	r.checkLeverage()
+	if r.varianceModel {
+		if err := r.fitVarianceModel(variables, active, numOfvars+1); err != nil {
+			return err
+		}
+	}
+	r.metadata = r.newMetadata()
+	return nil
+}
+
+// setFormula renders Formula from the coefficients at the configured precision.
+func (r *Regression) setFormula() {
+	precision := 4
+	if r.formulaPrecision != nil {
+		precision = *r.formulaPrecision
+	}
+	r.Formula = r.FormatFormula(precision)
+}
+
+// ordinaryLeastSquares fits the coefficients using QR decomposition and records their covariance.
+func (r *Regression) ordinaryLeastSquares(observed, variables *mat.Dense) []float64 {
+	rows, n := variables.Dims()
 	qr := new(mat.QR)
 	qr.Factorize(variables)
 	q := new(mat.Dense)
@@ -192,41 +569,103 @@ func (r *Regression) Run() error {
 		c[i] /= reg.At(i, i)
 	}
 
-	// Output the regression results
-	r.coeff = make(map[int]float64, numOfvars)
-	for i, val := range c {
-		r.coeff[i] = val
-		if i == 0 {
-			r.Formula = fmt.Sprintf("Predicted = %.4f", val)
-		} else {
-			r.Formula += fmt.Sprintf(" + %v*%.4f", r.GetVar(i-1), val)
+	r.cov = coefficientCovariance(variables, variables, observed, c)
+	r.residualDF = rows - n
+	r.factor = newTriangularFactor(reg, qty, n, sumOfSquaredResiduals(variables, observed, c))
+	return c
+}
+
+// designMatrix returns the observed values as a column vector along with the design matrix,
+// whose first column is all ones for the offset and whose remaining columns are the variables.
+func (r *Regression) designMatrix() (*mat.Dense, *mat.Dense) {
+	observations := len(r.Data)
+	numOfvars := len(r.features(r.Data[0].Variables))
+
+	observed := mat.NewDense(observations, 1, nil)
+	variables := mat.NewDense(observations, numOfvars+1, nil)
+	for i := 0; i < observations; i++ {
+		observed.Set(i, 0, r.Data[i].Observed)
+		variables.Set(i, 0, 1)
+		for j, v := range r.features(r.Data[i].Variables) {
+			variables.Set(i, j+1, v)
 		}
 	}
-
-	r.calcPredicted()
-	r.calcVariance()
-	r.calcR2()
-	return nil
+	return observed, variables
 }
 
 // Coeff returns the calculated coefficient for variable i.
 func (r *Regression) Coeff(i int) float64 {
-	if len(r.coeff) == 0 {
+	if i < 0 || i >= len(r.coeff) {
 		return 0
 	}
 	return r.coeff[i]
 }
 
+// StdErr returns the standard error of the coefficient for variable i, or NaN if it is unavailable.
+func (r *Regression) StdErr(i int) float64 {
+	if r.cov == nil || i < 0 || i >= r.cov.SymmetricDim() {
+		return math.NaN()
+	}
+	return math.Sqrt(r.cov.At(i, i))
+}
+
+// GetStdErrs returns the standard errors of the coefficients. The element at index 0 is for the offset.
+func (r *Regression) GetStdErrs() []float64 {
+	if r.cov == nil {
+		return nil
+	}
+	n, _ := r.cov.Dims()
+	stdErrs := make([]float64, n)
+	for i := range stdErrs {
+		stdErrs[i] = r.StdErr(i)
+	}
+	return stdErrs
+}
+
 // GetCoeffs returns the calculated coefficients. The element at index 0 is the offset.
 func (r *Regression) GetCoeffs() []float64 {
 	if len(r.coeff) == 0 {
 		return nil
 	}
-	coeffs := make([]float64, len(r.coeff))
-	for i := range coeffs {
-		coeffs[i] = r.coeff[i]
+	return append([]float64(nil), r.coeff...)
+}
+
+// NamedCoeff is a coefficient of a fitted model with the name of its term.
+type NamedCoeff struct {
+	Name  string
+	Coeff float64
+}
+
+// NamedCoeffs returns the offset, named "(Offset)", followed by each variable with its coefficient, in
+// the order of GetCoeffs.
+func (r *Regression) NamedCoeffs() []NamedCoeff {
+	if len(r.coeff) == 0 {
+		return nil
+	}
+	named := make([]NamedCoeff, len(r.coeff))
+	for i, c := range r.coeff {
+		named[i] = NamedCoeff{Name: r.coeffName(i), Coeff: c}
+	}
+	return named
+}
+
+// CoeffByName returns the coefficient of the named variable, or of the offset for "(Offset)", and
+// whether the model has a term with that name.
+func (r *Regression) CoeffByName(name string) (float64, bool) {
+	for i, c := range r.coeff {
+		if r.coeffName(i) == name {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// coeffName returns the name of the term of coefficient i.
+func (r *Regression) coeffName(i int) string {
+	if i == 0 {
+		return offsetName
 	}
-	return coeffs
+	return r.GetVar(i - 1)
 }
 
 func (r *Regression) calcPredicted() string {
@@ -234,7 +673,10 @@ func (r *Regression) calcPredicted() string {
 	var predicted float64
 	var output string
 	for i := 0; i < observations; i++ {
-		r.Data[i].Predicted, _ = r.Predict(r.Data[i].Variables)
+		r.Data[i].Predicted, _ = r.predict(r.Data[i].Variables)
+		if r.entityEffects != nil {
+			r.Data[i].Predicted += r.entityEffects[r.Data[i].Entity] - r.Coeff(0)
+		}
 		r.Data[i].Error = r.Data[i].Predicted - r.Data[i].Observed
 
 		output += fmt.Sprintf("%v. observed = %v, Predicted = %v, Error = %v", i, r.Data[i].Observed, predicted, r.Data[i].Error)
@@ -256,19 +698,86 @@ func (r *Regression) calcVariance() string {
 		obvar += math.Pow(r.Data[i].Observed-obaverage, 2)
 		prvar += math.Pow(r.Data[i].Predicted-praverage, 2)
 	}
-	r.Varianceobserved = obvar / float64(observations)
-	r.VariancePredicted = prvar / float64(observations)
+	divisor := float64(observations - 1)
+	if r.legacyStatistics {
+		divisor = float64(observations)
+	}
+	r.Varianceobserved = obvar / divisor
+	r.VariancePredicted = prvar / divisor
 	return fmt.Sprintf("N = %v\nVariance observed = %v\nVariance Predicted = %v\n", observations, r.Varianceobserved, r.VariancePredicted)
 }
 
 func (r *Regression) calcR2() string {
-	r.R2 = r.VariancePredicted / r.Varianceobserved
+	var mean, sse, sst float64
+	for _, d := range r.Data {
+		mean += d.Observed / float64(len(r.Data))
+	}
+	for _, d := range r.Data {
+		sse += d.Error * d.Error
+		sst += (d.Observed - mean) * (d.Observed - mean)
+	}
+
+	if r.legacyStatistics {
+		r.R2 = r.VariancePredicted / r.Varianceobserved
+	} else {
+		r.R2 = 1 - sse/sst
+	}
+	r.AdjustedR2 = 1 - (1-r.R2)*float64(len(r.Data)-1)/float64(r.residualDF)
+	r.residualStdErr = math.Sqrt(sse / float64(r.residualDF))
+	r.totalSS, r.residualSS = sst, sse
 	return fmt.Sprintf("R2 = %.2f", r.R2)
 }
 
+// SetLegacyStatistics restores the statistics of earlier versions of this package: R2 as the ratio of
+// the predicted to observed variance, and variances dividing by n rather than n - 1. By default R2 is
+// 1 - SSE/SST, in agreement with R and Python.
+func (r *Regression) SetLegacyStatistics(legacy bool) {
+	r.legacyStatistics = legacy
+}
+
+// ResidualStdErr returns the residual standard error, the square root of the residual sum of squares
+// divided by the residual degrees of freedom n - k - 1.
+func (r *Regression) ResidualStdErr() float64 {
+	return r.residualStdErr
+}
+
+// Sigma returns the residual standard error, as ResidualStdErr, under the name used by R.
+func (r *Regression) Sigma() float64 {
+	return r.residualStdErr
+}
+
+// ResidualDF returns the residual degrees of freedom, the number of data points less the number of
+// estimated parameters, including the offset and any entity effects, plus any linear constraints.
+func (r *Regression) ResidualDF() int {
+	return r.residualDF
+}
+
+// TotalSS returns the total sum of squares, of the observed values about their mean.
+func (r *Regression) TotalSS() float64 {
+	return r.totalSS
+}
+
+// ResidualSS returns the residual sum of squares, of the observed values about their predictions.
+func (r *Regression) ResidualSS() float64 {
+	return r.residualSS
+}
+
+// ExplainedSS returns the explained sum of squares, the total less the residual sum of squares.
+func (r *Regression) ExplainedSS() float64 {
+	return r.totalSS - r.residualSS
+}
+
 func (r *Regression) calcResiduals() string {
-	str := fmt.Sprintf("Residuals:\nobserved|\tPredicted|\tResidual\n")
+	labeled := labels(r.Data) != nil
+	str := "Residuals:\n"
+	if labeled {
+		str += "Label|\t"
+	}
+	str += "observed|\tPredicted|\tResidual\n"
 	for _, d := range r.Data {
+		if labeled {
+			str += d.Label + "|\t"
+		}
 		str += fmt.Sprintf("%.2f|\t%.2f|\t%.2f\n", d.Observed, d.Predicted, d.Observed-d.Predicted)
 	}
 	str += "\n"
@@ -295,9 +804,11 @@ func (r *Regression) String() string {
 	}
 	str += "\n"
 	for _, d := range r.Data {
-		str += fmt.Sprintf("%v\n", d)
+		// Show the crossed features under their names
+		row := &dataPoint{Observed: d.Observed, Variables: r.features(d.Variables)}
+		str += fmt.Sprintf("%v\n", row)
 	}
-	fmt.Println(r.calcResiduals())
+	r.logger().Debug(r.calcResiduals())
 	str += fmt.Sprintf("\nN = %v\nVariance observed = %v\nVariance Predicted = %v", len(r.Data), r.Varianceobserved, r.VariancePredicted)
 	str += fmt.Sprintf("\nR2 = %v\n", r.R2)
 	return str
@@ -307,6 +818,8 @@ func (r *Regression) String() string {
 // That is to say the first slice represents a row, and the second represents the cols.
 // Furthermore it is expected that all the col slices are of the same length.
 // The obsIndex parameter indicates which column should be used
+// When it is the first or last column the variables reference the rows of a, as in DataPointsFromRows,
+// and otherwise they are copied.
 func MakeDataPoints(a [][]float64, obsIndex int) []*dataPoint {
 	if obsIndex != 0 && obsIndex != len(a[0])-1 {
 		return perverseMakeDataPoints(a, obsIndex)
@@ -328,6 +841,26 @@ func MakeDataPoints(a [][]float64, obsIndex int) []*dataPoint {
 	return retVal
 }
 
+// DataPointsFromRows makes data points that reference the caller's rows as their variables rather
+// than copying them, with observed[i] the observed value of row i. The data points are allocated
+// together, so that ingesting a large in-memory dataset costs little beyond the caller's own arrays.
+//
+// The caller keeps ownership of the rows. This package never writes to them, but they must not be
+// modified while the data points are in use, such as while a model trained on them is run or its
+// diagnostics are computed, as the model would see the new values.
+func DataPointsFromRows(observed []float64, rows [][]float64) (DataPoints, error) {
+	if len(observed) != len(rows) {
+		return nil, fmt.Errorf("%w: %d observed values for %d rows", ErrDesign, len(observed), len(rows))
+	}
+	points := make([]dataPoint, len(rows))
+	retVal := make(DataPoints, len(rows))
+	for i, row := range rows {
+		points[i] = dataPoint{Observed: observed[i], Variables: row[:len(row):len(row)]}
+		retVal[i] = &points[i]
+	}
+	return retVal, nil
+}
+
 func perverseMakeDataPoints(a [][]float64, obsIndex int) []*dataPoint {
 	retVal := make([]*dataPoint, 0, len(a))
 	for _, r := range a {
@@ -1,7 +1,9 @@
 package regression
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"testing"
 )
@@ -157,6 +159,40 @@ func TestMakeDataPoints(t *testing.T) {
 	}
 }
 
+func TestDataPointsFromRows(t *testing.T) {
+	observed := make([]float64, len(anscombe))
+	rows := make([][]float64, len(anscombe))
+	for i, row := range anscombe {
+		observed[i] = row[0]
+		rows[i] = append(make([]float64, 0, 4), row[1:]...)
+	}
+	dps, err := DataPointsFromRows(observed, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, dp := range dps {
+		if &dp.Variables[0] != &rows[i][0] || dp.Observed != observed[i] {
+			t.Fatalf("Expected data point %d to reference row %v, got %v", i, rows[i], dp)
+		}
+	}
+
+	r := new(Regression)
+	r.Train(dps...)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if len(row) != 1 || row[:2][1] != 0 || row[0] != anscombe[i][1] {
+			t.Errorf("Expected row %d to be unchanged by Run, got %v", i, row[:cap(row)])
+		}
+	}
+
+	if _, err := DataPointsFromRows(observed[1:], rows); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign for mismatched lengths, got %v", err)
+	}
+}
+
 func TestGetCoeffs(t *testing.T) {
 	a := [][]float64{
 		{651, 1, 23},
@@ -186,3 +222,220 @@ func TestGetCoeffs(t *testing.T) {
 		}
 	}
 }
+
+func TestStdErr(t *testing.T) {
+	r := new(Regression)
+	if !math.IsNaN(r.StdErr(0)) || r.GetStdErrs() != nil {
+		t.Error("Expected no standard errors before running")
+	}
+	r.Train(linearSeries(20, 20, 3)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// For a single variable the slope's standard error is sqrt(σ²/Sxx)
+	var mean, sxx, sse float64
+	for _, d := range r.Data {
+		mean += d.Variables[0] / 20
+	}
+	for _, d := range r.Data {
+		sxx += (d.Variables[0] - mean) * (d.Variables[0] - mean)
+		sse += d.Error * d.Error
+	}
+	expected := math.Sqrt(sse / 18 / sxx)
+	if math.Abs(r.StdErr(1)-expected) > 1e-10 {
+		t.Errorf("Expected standard error %v, got %v", expected, r.StdErr(1))
+	}
+	if len(r.GetStdErrs()) != 2 {
+		t.Errorf("Expected 2 standard errors, got %v", r.GetStdErrs())
+	}
+	if !math.IsNaN(r.StdErr(-1)) || !math.IsNaN(r.StdErr(2)) {
+		t.Errorf("Expected NaN out of range, got %v and %v", r.StdErr(-1), r.StdErr(2))
+	}
+}
+
+func TestPredictDoesNotWriteToInput(t *testing.T) {
+	r := new(Regression)
+	r.Train(
+		DataPoint(6, []float64{2}),
+		DataPoint(20, []float64{4}),
+		DataPoint(30, []float64{5}),
+		DataPoint(72, []float64{8}),
+	)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	row := []float64{6, 42}
+	if _, err := r.Predict(row[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if row[1] != 42 {
+		t.Errorf("Expected Predict to leave the caller's array alone, got %v", row)
+	}
+}
+
+// anscombe is the first data set of Anscombe's quartet.
+var anscombe = [][]float64{
+	{8.04, 10}, {6.95, 8}, {7.58, 13}, {8.81, 9}, {8.33, 11}, {9.96, 14},
+	{7.24, 6}, {4.26, 4}, {10.84, 12}, {4.82, 7}, {5.68, 5},
+}
+
+func TestR2(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reference values from R's summary(lm(y1 ~ x1))
+	if math.Abs(r.R2-0.6665) > 1e-4 {
+		t.Errorf("Expected R² 0.6665, got %.4f", r.R2)
+	}
+	if math.Abs(r.AdjustedR2-0.6295) > 1e-4 {
+		t.Errorf("Expected adjusted R² 0.6295, got %.4f", r.AdjustedR2)
+	}
+	if math.Abs(r.ResidualStdErr()-1.237) > 1e-3 {
+		t.Errorf("Expected residual standard error 1.237, got %.4f", r.ResidualStdErr())
+	}
+	if r.ResidualDF() != 9 || r.Sigma() != r.ResidualStdErr() {
+		t.Errorf("Expected 9 residual degrees of freedom and sigma %v, got %d and %v", r.ResidualStdErr(), r.ResidualDF(), r.Sigma())
+	}
+	// anova(lm(y1 ~ x1)) gives sums of squares 27.510 for x1 and 13.763 for the residuals
+	if math.Abs(r.TotalSS()-41.2727) > 1e-4 || math.Abs(r.ExplainedSS()-27.5100) > 1e-4 || math.Abs(r.ResidualSS()-13.7627) > 1e-4 {
+		t.Errorf("Expected sums of squares 41.2727 = 27.5100 + 13.7627, got %.4f = %.4f + %.4f", r.TotalSS(), r.ExplainedSS(), r.ResidualSS())
+	}
+	if math.Abs(r.Varianceobserved-4.127269) > 1e-6 {
+		t.Errorf("Expected the sample variance 4.127269, got %.6f", r.Varianceobserved)
+	}
+
+	legacy := new(Regression)
+	legacy.Train(MakeDataPoints(anscombe, 0)...)
+	legacy.SetLegacyStatistics(true)
+	if err := legacy.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(legacy.Varianceobserved-r.Varianceobserved*10/11) > 1e-12 {
+		t.Errorf("Expected the legacy variance to divide by n, got %v", legacy.Varianceobserved)
+	}
+	if math.Abs(legacy.R2-legacy.VariancePredicted/legacy.Varianceobserved) > 1e-12 {
+		t.Errorf("Expected the legacy R² to be the ratio of variances, got %v", legacy.R2)
+	}
+}
+
+func TestFromCoefficients(t *testing.T) {
+	r := FromCoefficients(3, map[string]float64{"temp": 0.5, "pressure": -2})
+	if r.GetVar(0) != "pressure" || r.GetVar(1) != "temp" {
+		t.Errorf("Expected variables ordered by name, got %v, %v", r.GetVar(0), r.GetVar(1))
+	}
+	p, err := r.Predict([]float64{1, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != 6 {
+		t.Errorf("Expected prediction 6, got %v", p)
+	}
+	if err := r.Run(); !errors.Is(err, ErrRegressionRun) {
+		t.Errorf("Expected ErrRegressionRun, got %v", err)
+	}
+	if _, err := r.Leverage(); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+	if err := r.WritePMML(ioutil.Discard); err != nil {
+		t.Errorf("Expected a pretrained model to export, got %v", err)
+	}
+}
+
+func TestNamedCoeffs(t *testing.T) {
+	r := FromCoefficients(3, map[string]float64{"temp": 0.5, "pressure": -2, "flow": 1.25})
+	want := []NamedCoeff{{offsetName, 3}, {"flow", 1.25}, {"pressure", -2}, {"temp", 0.5}}
+	got := r.NamedCoeffs()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected term %d to be %v, got %v", i, want[i], got[i])
+		}
+	}
+	if c, ok := r.CoeffByName("pressure"); !ok || c != -2 {
+		t.Errorf("Expected pressure coefficient -2, got %v, %v", c, ok)
+	}
+	if _, ok := r.CoeffByName("humidity"); ok {
+		t.Error("Expected no coefficient for an unknown variable")
+	}
+	if r.Coeff(4) != 0 || new(Regression).NamedCoeffs() != nil {
+		t.Error("Expected no coefficients beyond those of the model")
+	}
+	r.GetCoeffs()[1] = 0
+	if r.Coeff(1) != 1.25 {
+		t.Error("Expected GetCoeffs to return a copy")
+	}
+}
+
+func TestWrappedErrors(t *testing.T) {
+	r := new(Regression)
+	r.Train(DataPoint(1, []float64{1, 2}), DataPoint(2, []float64{2, 3}), DataPoint(3, []float64{3}))
+	err := r.Run()
+	var pointErr *DataPointError
+	if !errors.As(err, &pointErr) || pointErr.Index != 2 || !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected a DataPointError for data point 2 wrapping ErrVariableCount, got %v", err)
+	}
+
+	r = new(Regression)
+	r.Train(DataPoint(1, []float64{1, 2, 0}), DataPoint(2, []float64{2, 3, 1}), DataPoint(3, []float64{3, 1, 2}))
+	err = r.Run()
+	if !errors.Is(err, ErrTooManyVars) || err.Error() != "not enough observations to to support this many variables: 3 observations for 3 variables and the offset" {
+		t.Errorf("Expected ErrTooManyVars with the counts, got %v", err)
+	}
+
+	r = new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Run()
+	if _, err := r.Predict([]float64{1, 2}); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	r := new(Regression)
+	if r.NumVars() != 0 || r.NumFeatures() != 0 || len(r.Variables()) != 0 {
+		t.Errorf("Expected no variables before training")
+	}
+	r.SetVar(0, "a")
+	r.SetVar(1, "b")
+	r.AddCross(PowCross(0, 2))
+	for i := 0; i < 6; i++ {
+		x := float64(i)
+		r.Train(DataPoint(x*x+math.Sin(x), []float64{x, math.Cos(x)}))
+	}
+
+	check := func(when string) {
+		if r.NumVars() != 2 || r.NumFeatures() != 3 {
+			t.Errorf("%s: expected 2 variables and 3 features, got %d and %d", when, r.NumVars(), r.NumFeatures())
+		}
+		if names := r.Variables(); len(names) != 3 || names[0] != "a" || names[1] != "b" {
+			t.Errorf("%s: expected variables a, b and a cross, got %v", when, names)
+		}
+		if indices := r.CrossIndices(); len(indices) != 1 || indices[0] != 2 {
+			t.Errorf("%s: expected the cross at index 2, got %v", when, indices)
+		}
+	}
+	check("before Run")
+	r.Run()
+	check("after Run")
+	if name := r.Variables()[2]; name != "(a)^2" {
+		t.Errorf("Expected the cross to be named (a)^2, got %v", name)
+	}
+
+	failed := new(Regression)
+	for i := 0; i < 6; i++ {
+		failed.Train(DataPoint(float64(i), []float64{float64(i), 1}))
+	}
+	if err := failed.Run(); !errors.Is(err, ErrConstantVariable) {
+		t.Fatalf("Expected ErrConstantVariable, got %v", err)
+	}
+	if failed.NumFeatures() != 2 || len(failed.Variables()) != 2 {
+		t.Errorf("Expected 2 features after a failed run, got %d", failed.NumFeatures())
+	}
+}
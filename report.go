@@ -0,0 +1,237 @@
+package regression
+
+import (
+	"encoding/base64"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// ReportFormat selects the output format of Report.
+type ReportFormat int
+
+const (
+	// ReportMarkdown writes the report as Markdown.
+	ReportMarkdown ReportFormat = iota
+	// ReportHTML writes the report as a standalone HTML document.
+	ReportHTML
+)
+
+// ReportOptions configures Report.
+type ReportOptions struct {
+	Format ReportFormat
+	Title  string
+	// Precision is the number of decimal places for statistics, 4 if it is nil.
+	Precision *int
+	// Plots embeds a residuals vs fitted plot as SVG.
+	Plots bool
+}
+
+// reportRow is a row of the coefficient summary table.
+type reportRow struct {
+	Term, Estimate, StdErr, TValue, PValue string
+}
+
+// reportStat is a named model statistic.
+type reportStat struct {
+	Name, Value string
+}
+
+type reportData struct {
+	Title        string
+	Formula      string
+	Coefficients []reportRow
+	Diagnostics  []reportStat
+	Residuals    []reportStat
+	Plot         string
+}
+
+// Report writes a summary of the fitted model, suitable for attaching to experiment records: the
+// coefficient table with standard errors, t statistics and p-values, model diagnostics, a summary of
+// the residuals and optionally a residuals vs fitted plot.
+func (r *Regression) Report(w io.Writer, opts ReportOptions) error {
+	if err := r.requireData(); err != nil {
+		return err
+	}
+	precision := 4
+	if opts.Precision != nil {
+		precision = *opts.Precision
+	}
+	format := func(f float64) string {
+		return fmt.Sprintf("%.*f", precision, f)
+	}
+	title := opts.Title
+	if title == "" {
+		title = "Regression report"
+	}
+	data := reportData{Title: title, Formula: r.FormulaMarkdown(precision)}
+
+	// Coefficients
+	tidy, err := r.Tidy()
+	if err != nil {
+		return err
+	}
+	for _, c := range tidy {
+		data.Coefficients = append(data.Coefficients, reportRow{
+			Term:     c.Term,
+			Estimate: format(c.Estimate),
+			StdErr:   format(c.StdErr),
+			TValue:   format(c.TValue),
+			PValue:   fmt.Sprintf("%.3g", c.PValue),
+		})
+	}
+
+	// Diagnostics
+	glance, err := r.Glance()
+	if err != nil {
+		return err
+	}
+	data.Diagnostics = []reportStat{
+		{"Observations", fmt.Sprint(glance.NObs)},
+		{"Residual degrees of freedom", fmt.Sprint(glance.DFResidual)},
+		{"R²", format(glance.R2)},
+		{"Adjusted R²", format(glance.AdjustedR2)},
+		{"Residual standard error", format(glance.Sigma)},
+		{"F statistic", format(glance.FStatistic)},
+		{"F p-value", fmt.Sprintf("%.3g", glance.PValue)},
+	}
+
+	// Residual distribution
+	fitted, residuals, err := r.ResidualsVsFitted()
+	if err != nil {
+		return err
+	}
+	sorted := append([]float64(nil), residuals...)
+	sort.Float64s(sorted)
+	data.Residuals = []reportStat{
+		{"Min", format(sorted[0])},
+		{"Q1", format(stat.Quantile(0.25, stat.Empirical, sorted, nil))},
+		{"Median", format(stat.Quantile(0.5, stat.Empirical, sorted, nil))},
+		{"Q3", format(stat.Quantile(0.75, stat.Empirical, sorted, nil))},
+		{"Max", format(sorted[len(sorted)-1])},
+	}
+
+	if opts.Plots {
+		data.Plot = scatterSVG(fitted, residuals, "Fitted", "Residual")
+	}
+
+	switch opts.Format {
+	case ReportMarkdown:
+		if data.Plot != "" {
+			data.Plot = "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(data.Plot))
+		}
+		return markdownReport.Execute(w, data)
+	case ReportHTML:
+		// The template escapes the formula, so it is rendered as plain text rather than Markdown
+		lhs := "Predicted"
+		if obs := r.GetObserved(); obs != "" {
+			lhs = "Predicted " + obs
+		}
+		data.Formula = r.renderFormula(lhs, " × ", "−", decimals(precision), func(name string) string {
+			return name
+		})
+		return htmlReport.Execute(w, struct {
+			reportData
+			SVG htmltemplate.HTML
+		}{data, htmltemplate.HTML(data.Plot)})
+	}
+	return fmt.Errorf("unknown report format %d", opts.Format)
+}
+
+var markdownReport = template.Must(template.New("report").Funcs(template.FuncMap{"escape": markdownEscaper.Replace}).Parse(
+	`# {{escape .Title}}
+
+{{.Formula}}
+
+## Coefficients
+
+| Term | Estimate | Std. error | t value | p-value |
+|------|---------:|-----------:|--------:|--------:|
+{{range .Coefficients}}| {{escape .Term}} | {{.Estimate}} | {{.StdErr}} | {{.TValue}} | {{.PValue}} |
+{{end}}
+## Diagnostics
+
+| Statistic | Value |
+|-----------|------:|
+{{range .Diagnostics}}| {{.Name}} | {{.Value}} |
+{{end}}
+## Residuals
+
+| Min | Q1 | Median | Q3 | Max |
+|----:|---:|-------:|---:|----:|
+|{{range .Residuals}} {{.Value}} |{{end}}
+{{if .Plot}}
+## Residuals vs fitted
+
+![Residuals vs fitted]({{.Plot}})
+{{end}}`))
+
+var htmlReport = htmltemplate.Must(htmltemplate.New("report").Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Formula}}</p>
+<h2>Coefficients</h2>
+<table>
+<tr><th>Term</th><th>Estimate</th><th>Std. error</th><th>t value</th><th>p-value</th></tr>
+{{range .Coefficients}}<tr><td>{{.Term}}</td><td>{{.Estimate}}</td><td>{{.StdErr}}</td><td>{{.TValue}}</td><td>{{.PValue}}</td></tr>
+{{end}}</table>
+<h2>Diagnostics</h2>
+<table>
+{{range .Diagnostics}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+<h2>Residuals</h2>
+<table>
+<tr>{{range .Residuals}}<th>{{.Name}}</th>{{end}}</tr>
+<tr>{{range .Residuals}}<td>{{.Value}}</td>{{end}}</tr>
+</table>
+{{if .SVG}}<h2>Residuals vs fitted</h2>
+{{.SVG}}
+{{end}}</body>
+</html>
+`))
+
+// scatterSVG draws a simple scatter plot of y against x as an SVG document, with a line at y = 0.
+func scatterSVG(x, y []float64, xLabel, yLabel string) string {
+	const width, height, margin = 480.0, 320.0, 40.0
+	xMin, xMax := floatsRange(x)
+	yMin, yMax := floatsRange(append(append([]float64(nil), y...), 0))
+	px := func(v float64) float64 { return margin + (v-xMin)/(xMax-xMin)*(width-2*margin) }
+	py := func(v float64) float64 { return height - margin - (v-yMin)/(yMax-yMin)*(height-2*margin) }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`, width, height, width, height)
+	fmt.Fprintf(&b, `<rect x="%.0f" y="%.0f" width="%.0f" height="%.0f" fill="none" stroke="black"/>`, margin, margin, width-2*margin, height-2*margin)
+	fmt.Fprintf(&b, `<line x1="%.0f" y1="%.2f" x2="%.0f" y2="%.2f" stroke="grey" stroke-dasharray="4"/>`, margin, py(0), width-margin, py(0))
+	for i := range x {
+		fmt.Fprintf(&b, `<circle cx="%.2f" cy="%.2f" r="3"/>`, px(x[i]), py(y[i]))
+	}
+	fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="middle">%s</text>`, width/2, height-10, htmltemplate.HTMLEscapeString(xLabel))
+	fmt.Fprintf(&b, `<text x="15" y="%.0f" text-anchor="middle" transform="rotate(-90 15 %.0f)">%s</text>`, height/2, height/2, htmltemplate.HTMLEscapeString(yLabel))
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// floatsRange returns the range of x, widened if all the values are the same.
+func floatsRange(x []float64) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, v := range x {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	if min == max {
+		min, max = min-1, max+1
+	}
+	return min, max
+}
@@ -0,0 +1,80 @@
+package regression
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func reportModel(t *testing.T) *Regression {
+	r := new(Regression)
+	r.SetObserved("Yield")
+	r.SetVar(0, "Temp <C>")
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestReportMarkdown(t *testing.T) {
+	r := new(Regression)
+	if err := r.Report(new(bytes.Buffer), ReportOptions{}); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+
+	r = reportModel(t)
+	var buf bytes.Buffer
+	precision := 3
+	if err := r.Report(&buf, ReportOptions{Title: "Anscombe I", Precision: &precision}); err != nil {
+		t.Fatal(err)
+	}
+	report := buf.String()
+	// Reference values from R's summary(lm(y1 ~ x1))
+	for _, expected := range []string{
+		"# Anscombe I\n",
+		"| (Offset) | 3.000 | 1.125 | 2.667 | 0.0257 |",
+		`| Temp \<C\> | 0.500 | 0.118 | 4.241 | 0.00217 |`,
+		"| Observations | 11 |",
+		"| F statistic | 17.990 |",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected the report to contain %q, got\n%s", expected, report)
+		}
+	}
+	if strings.Contains(report, "svg") {
+		t.Error("Expected no plot unless requested")
+	}
+
+	buf.Reset()
+	precision = 0
+	if err := r.Report(&buf, ReportOptions{Precision: &precision}); err != nil {
+		t.Fatal(err)
+	}
+	if report := buf.String(); !strings.Contains(report, "| Observations | 11 |") || !strings.Contains(report, "| F statistic | 18 |") {
+		t.Errorf("Expected statistics without decimal places, got\n%s", report)
+	}
+}
+
+func TestReportHTML(t *testing.T) {
+	r := reportModel(t)
+	var buf bytes.Buffer
+	if err := r.Report(&buf, ReportOptions{Format: ReportHTML, Plots: true}); err != nil {
+		t.Fatal(err)
+	}
+	report := buf.String()
+	for _, expected := range []string{
+		"<title>Regression report</title>",
+		"<p>Predicted Yield = 3.0001 &#43; 0.5001 × Temp &lt;C&gt;</p>",
+		"<td>Temp &lt;C&gt;</td><td>0.5001</td>",
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected the report to contain %q, got\n%s", expected, report)
+		}
+	}
+	if strings.Count(report, "<circle") != 11 {
+		t.Errorf("Expected a point per observation in the plot")
+	}
+}
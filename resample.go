@@ -0,0 +1,101 @@
+package regression
+
+import (
+	"math/rand"
+)
+
+// newLike returns an empty regression configured in the same way as r: the same names, feature
+// crosses and fitting options, but no data.
+func (r *Regression) newLike() *Regression {
+	c := &Regression{
+		crosses:          r.crosses,
+		endogenous:       r.endogenous,
+		instruments:      r.instruments,
+		fixedEffects:     r.fixedEffects,
+		collinearityTol:  r.collinearityTol,
+		dropConstant:     r.dropConstant,
+		internalScaling:  r.internalScaling,
+		solver:           r.solver,
+		covType:          r.covType,
+		varianceModel:    r.varianceModel,
+		legacyStatistics: r.legacyStatistics,
+		log:              r.log,
+		formulaPrecision: r.formulaPrecision,
+		constraints:      r.constraints,
+		signs:            r.signs,
+		truncation:       r.truncation,
+		convergence:      r.convergence,
+	}
+	c.names.obs = r.names.obs
+	c.names.vars = make(map[int]string, len(r.names.vars))
+	raw, _ := r.numVars()
+	for i, name := range r.names.vars {
+		// Names of crossed variables are regenerated when the copy is run
+		if !r.hasRun || i < raw {
+			c.names.vars[i] = name
+		}
+	}
+	return c
+}
+
+// rawData returns copies of the training data points, without their fitted values, so they can be
+// used to train another regression.
+func (r *Regression) rawData() DataPoints {
+	points := make(DataPoints, len(r.Data))
+	for i, d := range r.Data {
+		points[i] = &dataPoint{
+			Observed:  d.Observed,
+			Variables: append([]float64(nil), d.Variables...),
+			Entity:    d.Entity,
+			Label:     d.Label,
+			censored:  d.censored,
+		}
+	}
+	return points
+}
+
+// fitLike trains and runs a regression configured like r on copies of the data points.
+func (r *Regression) fitLike(points DataPoints) (*Regression, error) {
+	fit := r.newLike()
+	return fit, fit.fitCopies(points)
+}
+
+// fitCopies trains the regression on copies of the data points, so that the fitted values recorded
+// by Run do not change them, and runs it without instrumenting the fit.
+func (r *Regression) fitCopies(points DataPoints) error {
+	r.internal = true
+	for _, d := range points {
+		r.Train(&dataPoint{
+			Observed:  d.Observed,
+			Variables: append([]float64(nil), d.Variables...),
+			Entity:    d.Entity,
+			Label:     d.Label,
+			censored:  d.censored,
+		})
+	}
+	return r.Run()
+}
+
+// folds randomly splits the indices [0, n) into k folds whose sizes differ by at most one.
+func folds(n, k int, rng *rand.Rand) [][]int {
+	f := make([][]int, k)
+	for i, j := range rng.Perm(n) {
+		f[i%k] = append(f[i%k], j)
+	}
+	return f
+}
+
+// Split randomly divides the data points into a training set with the given fraction of them, rounded
+// down, and a test set with the rest.
+func Split(data DataPoints, fraction float64, opts ...Option) (train, test DataPoints) {
+	o := newOptions(opts)
+	n := int(fraction * float64(len(data)))
+	for i, j := range o.rand.Perm(len(data)) {
+		if i < n {
+			train = append(train, data[j])
+		} else {
+			test = append(test, data[j])
+		}
+	}
+	return train, test
+}
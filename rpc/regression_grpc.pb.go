@@ -0,0 +1,189 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: regression.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	RegressionService_Train_FullMethodName    = "/regression.v1.RegressionService/Train"
+	RegressionService_Predict_FullMethodName  = "/regression.v1.RegressionService/Predict"
+	RegressionService_GetModel_FullMethodName = "/regression.v1.RegressionService/GetModel"
+)
+
+// RegressionServiceClient is the client API for RegressionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RegressionServiceClient interface {
+	// Train fits a new model and makes it the current model.
+	Train(ctx context.Context, in *TrainRequest, opts ...grpc.CallOption) (*ModelProto, error)
+	// Predict scores each row with the current model.
+	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error)
+	// GetModel returns the current model.
+	GetModel(ctx context.Context, in *GetModelRequest, opts ...grpc.CallOption) (*ModelProto, error)
+}
+
+type regressionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRegressionServiceClient(cc grpc.ClientConnInterface) RegressionServiceClient {
+	return &regressionServiceClient{cc}
+}
+
+func (c *regressionServiceClient) Train(ctx context.Context, in *TrainRequest, opts ...grpc.CallOption) (*ModelProto, error) {
+	out := new(ModelProto)
+	err := c.cc.Invoke(ctx, RegressionService_Train_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regressionServiceClient) Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error) {
+	out := new(PredictResponse)
+	err := c.cc.Invoke(ctx, RegressionService_Predict_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *regressionServiceClient) GetModel(ctx context.Context, in *GetModelRequest, opts ...grpc.CallOption) (*ModelProto, error) {
+	out := new(ModelProto)
+	err := c.cc.Invoke(ctx, RegressionService_GetModel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegressionServiceServer is the server API for RegressionService service.
+// All implementations must embed UnimplementedRegressionServiceServer
+// for forward compatibility
+type RegressionServiceServer interface {
+	// Train fits a new model and makes it the current model.
+	Train(context.Context, *TrainRequest) (*ModelProto, error)
+	// Predict scores each row with the current model.
+	Predict(context.Context, *PredictRequest) (*PredictResponse, error)
+	// GetModel returns the current model.
+	GetModel(context.Context, *GetModelRequest) (*ModelProto, error)
+	mustEmbedUnimplementedRegressionServiceServer()
+}
+
+// UnimplementedRegressionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRegressionServiceServer struct {
+}
+
+func (UnimplementedRegressionServiceServer) Train(context.Context, *TrainRequest) (*ModelProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Train not implemented")
+}
+func (UnimplementedRegressionServiceServer) Predict(context.Context, *PredictRequest) (*PredictResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Predict not implemented")
+}
+func (UnimplementedRegressionServiceServer) GetModel(context.Context, *GetModelRequest) (*ModelProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModel not implemented")
+}
+func (UnimplementedRegressionServiceServer) mustEmbedUnimplementedRegressionServiceServer() {}
+
+// UnsafeRegressionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RegressionServiceServer will
+// result in compilation errors.
+type UnsafeRegressionServiceServer interface {
+	mustEmbedUnimplementedRegressionServiceServer()
+}
+
+func RegisterRegressionServiceServer(s grpc.ServiceRegistrar, srv RegressionServiceServer) {
+	s.RegisterService(&RegressionService_ServiceDesc, srv)
+}
+
+func _RegressionService_Train_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegressionServiceServer).Train(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegressionService_Train_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegressionServiceServer).Train(ctx, req.(*TrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegressionService_Predict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PredictRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegressionServiceServer).Predict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegressionService_Predict_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegressionServiceServer).Predict(ctx, req.(*PredictRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RegressionService_GetModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegressionServiceServer).GetModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RegressionService_GetModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegressionServiceServer).GetModel(ctx, req.(*GetModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RegressionService_ServiceDesc is the grpc.ServiceDesc for RegressionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RegressionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "regression.v1.RegressionService",
+	HandlerType: (*RegressionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Train",
+			Handler:    _RegressionService_Train_Handler,
+		},
+		{
+			MethodName: "Predict",
+			Handler:    _RegressionService_Predict_Handler,
+		},
+		{
+			MethodName: "GetModel",
+			Handler:    _RegressionService_GetModel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "regression.proto",
+}
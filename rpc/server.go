@@ -0,0 +1,122 @@
+// Package rpc implements a gRPC service for fitting linear regressions and scoring data against
+// them. The service and messages are defined in regression.proto, so clients can be generated
+// for other languages. It is a separate module, so that programs that do not import it do not
+// depend on gRPC.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative regression.proto
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Synthace/regression"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server implements RegressionService around the current model. Each successful Train replaces
+// the model and increments its version.
+type Server struct {
+	UnimplementedRegressionServiceServer
+
+	mu    sync.RWMutex
+	model *regression.Regression
+	proto *ModelProto
+}
+
+// NewServer returns a server with no model.
+func NewServer() *Server {
+	return new(Server)
+}
+
+// Train fits a new model to the request data and makes it the current model.
+func (s *Server) Train(ctx context.Context, req *TrainRequest) (*ModelProto, error) {
+	if len(req.Data) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no data points")
+	}
+	r := new(regression.Regression)
+	if req.ObservedName != "" {
+		r.SetObserved(req.ObservedName)
+	}
+	for i, name := range req.VariableNames {
+		r.SetVar(i, name)
+	}
+	for _, d := range req.Data {
+		r.Train(regression.LabeledDataPoint(d.Label, d.Observed, d.Variables))
+	}
+	if err := r.Run(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	m, err := modelProto(r)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.proto != nil {
+		m.Version = s.proto.Version + 1
+	} else {
+		m.Version = 1
+	}
+	s.model, s.proto = r, m
+	return proto.Clone(m).(*ModelProto), nil
+}
+
+// Predict scores each row with the current model. It fails with FailedPrecondition if the request
+// names a version other than the current one.
+func (s *Server) Predict(ctx context.Context, req *PredictRequest) (*PredictResponse, error) {
+	s.mu.RLock()
+	model, m := s.model, s.proto
+	s.mu.RUnlock()
+	if model == nil {
+		return nil, status.Error(codes.NotFound, "no model has been trained")
+	}
+	if req.Version != 0 && req.Version != m.Version {
+		return nil, status.Errorf(codes.FailedPrecondition, "requested model version %d, current version is %d", req.Version, m.Version)
+	}
+
+	rows := make([][]float64, len(req.Rows))
+	for i, row := range req.Rows {
+		rows[i] = row.Variables
+	}
+	predictions, err := model.PredictBatch(rows)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &PredictResponse{Version: m.Version, Predictions: predictions}, nil
+}
+
+// GetModel returns the current model.
+func (s *Server) GetModel(ctx context.Context, req *GetModelRequest) (*ModelProto, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.proto == nil {
+		return nil, status.Error(codes.NotFound, "no model has been trained")
+	}
+	return proto.Clone(s.proto).(*ModelProto), nil
+}
+
+// modelProto describes a fitted regression.
+func modelProto(r *regression.Regression) (*ModelProto, error) {
+	rows, err := r.Tidy()
+	if err != nil {
+		return nil, err
+	}
+	m := &ModelProto{
+		ObservedName:  r.GetObserved(),
+		VariableNames: r.Variables(),
+		Intercept:     rows[0].Estimate,
+		R2:            r.R2,
+		Formula:       r.Formula,
+	}
+	for i, row := range rows {
+		if i > 0 {
+			m.Coefficients = append(m.Coefficients, row.Estimate)
+		}
+		m.StdErrs = append(m.StdErrs, row.StdErr)
+	}
+	return m, nil
+}
@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServer(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterRegressionServiceServer(srv, NewServer())
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := NewRegressionServiceClient(conn)
+	ctx := context.Background()
+
+	if _, err := client.Predict(ctx, &PredictRequest{Rows: []*Row{{Variables: []float64{1}}}}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound before training, got %v", err)
+	}
+
+	m, err := client.Train(ctx, &TrainRequest{
+		ObservedName:  "y",
+		VariableNames: []string{"x"},
+		Data: []*DataPoint{
+			{Observed: 1, Variables: []float64{0}}, {Observed: 3, Variables: []float64{1}},
+			{Observed: 5.1, Variables: []float64{2}}, {Observed: 6.9, Variables: []float64{3}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != 1 || len(m.Coefficients) != 1 || len(m.StdErrs) != 2 || m.VariableNames[0] != "x" {
+		t.Errorf("Unexpected model %v", m)
+	}
+
+	resp, err := client.Predict(ctx, &PredictRequest{Version: 1, Rows: []*Row{{Variables: []float64{10}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := m.Intercept + 10*m.Coefficients[0]; len(resp.Predictions) != 1 || resp.Predictions[0] != want {
+		t.Errorf("Expected prediction %v, got %v", want, resp.Predictions)
+	}
+	if _, err := client.Predict(ctx, &PredictRequest{Version: 2}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a stale version, got %v", err)
+	}
+	if _, err := client.Predict(ctx, &PredictRequest{Rows: []*Row{{Variables: []float64{1, 2}}}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for the wrong number of variables, got %v", err)
+	}
+
+	got, err := client.GetModel(ctx, &GetModelRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != 1 || got.Formula != m.Formula {
+		t.Errorf("Expected the trained model, got %v", got)
+	}
+}
+
+func TestTrainExactFit(t *testing.T) {
+	// Three data points and two variables leave no residual degrees of freedom
+	m, err := NewServer().Train(context.Background(), &TrainRequest{
+		Data: []*DataPoint{
+			{Observed: 1, Variables: []float64{1, 2}}, {Observed: 4, Variables: []float64{2, 1}},
+			{Observed: 6, Variables: []float64{3, 5}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Coefficients) != 2 || len(m.StdErrs) != 3 || !math.IsNaN(m.StdErrs[0]) {
+		t.Errorf("Expected coefficients with NaN standard errors, got %v", m)
+	}
+}
@@ -0,0 +1,71 @@
+package regression
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// SetInternalScaling sets whether least squares fits mean-center and scale each variable to unit
+// standard deviation before solving, then transform the coefficients and their covariance back.
+// This improves the accuracy of designs whose variables differ by many orders of magnitude. The
+// reported coefficients, standard errors and statistics are on the original scale either way.
+func (r *Regression) SetInternalScaling(enabled bool) {
+	r.internalScaling = enabled
+}
+
+// scaledLeastSquares fits the coefficients by ordinary least squares on the centered and scaled
+// design Z = XT, where T maps the coefficients g of Z to those of X as b = Tg. The covariance is
+// transformed as TΣTᵀ and the triangular factor as R T⁻¹, so the fit can still be updated.
+func (r *Regression) scaledLeastSquares(observed, variables *mat.Dense) []float64 {
+	rows, n := variables.Dims()
+	means := make([]float64, n)
+	scales := make([]float64, n)
+	scaled := mat.DenseCopyOf(variables)
+	col := make([]float64, rows)
+	for j := 1; j < n; j++ {
+		mat.Col(col, j, variables)
+		means[j], scales[j] = stat.MeanStdDev(col, nil)
+		if scales[j] == 0 {
+			means[j], scales[j] = 0, 1
+		}
+		for i := range col {
+			col[i] = (col[i] - means[j]) / scales[j]
+		}
+		scaled.SetCol(j, col)
+	}
+	g := r.ordinaryLeastSquares(observed, scaled)
+
+	t := mat.NewDense(n, n, nil)
+	t.Set(0, 0, 1)
+	for j := 1; j < n; j++ {
+		t.Set(0, j, -means[j]/scales[j])
+		t.Set(j, j, 1/scales[j])
+	}
+	b := make([]float64, n)
+	b[0] = g[0]
+	for j := 1; j < n; j++ {
+		b[0] += t.At(0, j) * g[j]
+		b[j] = t.At(j, j) * g[j]
+	}
+
+	if r.cov != nil {
+		var cov mat.Dense
+		cov.Product(t, r.cov, t.T())
+		sym := mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				sym.SetSym(i, j, cov.At(i, j))
+			}
+		}
+		r.cov = sym
+	}
+
+	// Column j of T⁻¹ is (mⱼ, 0, ..., sⱼ, ..., 0)
+	f := r.factor.r
+	for i := 0; i < n; i++ {
+		for j := max(i, 1); j < n; j++ {
+			f.SetTri(i, j, f.At(i, 0)*means[j]+f.At(i, j)*scales[j])
+		}
+	}
+	return b
+}
@@ -0,0 +1,52 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInternalScaling(t *testing.T) {
+	// Variables many orders of magnitude apart
+	data := make([][]float64, 20)
+	for i := range data {
+		x1 := 1e8 + float64(i)*1e3
+		x2 := 1e-6 * float64((i*7)%11)
+		data[i] = []float64{3 + 2e-3*x1 + 5e5*x2 + float64(i%3), x1, x2}
+	}
+
+	plain := new(Regression)
+	plain.Train(MakeDataPoints(data, 0)...)
+	plain.Run()
+
+	r := new(Regression)
+	r.SetInternalScaling(true)
+	r.Train(MakeDataPoints(data, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range plain.GetCoeffs() {
+		if got, want := r.Coeff(i), plain.Coeff(i); math.Abs(got-want) > 1e-6*math.Abs(want) {
+			t.Errorf("Coefficient %d: expected %v, got %v", i, want, got)
+		}
+		if got, want := r.StdErr(i), plain.StdErr(i); math.Abs(got-want) > 1e-6*math.Abs(want) {
+			t.Errorf("Standard error %d: expected %v, got %v", i, want, got)
+		}
+	}
+	if math.Abs(r.R2-plain.R2) > 1e-9 {
+		t.Errorf("Expected R2 %v, got %v", plain.R2, r.R2)
+	}
+
+	// The factorization is kept on the original scale, so the fit can still be updated
+	extra := []float64{4, 1e8 + 3.5e3, 2e-6}
+	full := new(Regression)
+	full.Train(MakeDataPoints(append(data, extra), 0)...)
+	full.Run()
+	if err := r.Update(MakeDataPoints([][]float64{extra}, 0)...); err != nil {
+		t.Fatal(err)
+	}
+	for i := range full.GetCoeffs() {
+		if got, want := r.Coeff(i), full.Coeff(i); math.Abs(got-want) > 1e-6*math.Abs(want) {
+			t.Errorf("Updated coefficient %d: expected %v, got %v", i, want, got)
+		}
+	}
+}
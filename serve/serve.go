@@ -0,0 +1,255 @@
+// Package serve exposes a regression over HTTP, with JSON endpoints to fit a model and to predict
+// with it, so that the package can back a scoring service with little glue code.
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+
+	"github.com/Synthace/regression"
+)
+
+// defaultMaxBodyBytes limits the size of request bodies unless Server.MaxBodyBytes is set.
+const defaultMaxBodyBytes = 10 << 20
+
+// Server holds the current model. Each successful fit replaces the model and increments its
+// version, so clients can check which model scored their request.
+type Server struct {
+	// Configure, if set, is called on each new regression before it is trained, for example
+	// to add feature crosses.
+	Configure func(r *regression.Regression)
+	// MaxBodyBytes limits the size of request bodies, defaulting to 10MB.
+	MaxBodyBytes int64
+
+	mu      sync.RWMutex
+	model   *regression.Regression
+	version int
+}
+
+// New returns a server for the fitted regression r, which is served as version 1. If r is nil
+// there is no model until one is fitted.
+func New(r *regression.Regression) *Server {
+	s := new(Server)
+	if r != nil {
+		s.model, s.version = r, 1
+	}
+	return s
+}
+
+// Float is a float64 that is encoded as JSON null when it is NaN or infinite, which JSON cannot
+// represent, and decoded from null as NaN.
+type Float float64
+
+// MarshalJSON encodes f, or null if it is not finite.
+func (f Float) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(f))
+}
+
+// UnmarshalJSON decodes f, with null as NaN.
+func (f *Float) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*f = Float(math.NaN())
+		return nil
+	}
+	return json.Unmarshal(b, (*float64)(f))
+}
+
+// Point is a data point in a fit request.
+type Point struct {
+	Label     string    `json:"label,omitempty"`
+	Observed  float64   `json:"observed"`
+	Variables []float64 `json:"variables"`
+}
+
+// FitRequest is the body of a request to fit a new model.
+type FitRequest struct {
+	// Observed and Variables optionally name the observed value and variables.
+	Observed  string   `json:"observed,omitempty"`
+	Variables []string `json:"variables,omitempty"`
+	Data      []Point  `json:"data"`
+}
+
+// Coefficient is a coefficient of a fitted model.
+type Coefficient struct {
+	Term     string `json:"term"`
+	Estimate Float  `json:"estimate"`
+	// StdErr is omitted if the standard error is unavailable.
+	StdErr *float64 `json:"std_err,omitempty"`
+}
+
+// FitResponse describes a newly fitted model.
+type FitResponse struct {
+	Version      int           `json:"version"`
+	Formula      string        `json:"formula"`
+	R2           Float         `json:"r2"`
+	Coefficients []Coefficient `json:"coefficients"`
+	Warnings     []string      `json:"warnings,omitempty"`
+}
+
+// PredictRequest is the body of a request for predictions. If Version is set the request fails
+// with 409 Conflict unless it matches the version of the current model.
+type PredictRequest struct {
+	Version   int         `json:"version,omitempty"`
+	Variables [][]float64 `json:"variables"`
+}
+
+// PredictResponse holds a prediction for each row of variables in the request. Predictions that are
+// not finite are null.
+type PredictResponse struct {
+	Version     int     `json:"version"`
+	Predictions []Float `json:"predictions"`
+}
+
+// ErrorResponse is the body of a failed request. Index is the data point at fault, if any.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Index *int   `json:"index,omitempty"`
+}
+
+// Handler returns a handler serving FitHandler at /fit and PredictHandler at /predict.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/fit", s.FitHandler())
+	mux.Handle("/predict", s.PredictHandler())
+	return mux
+}
+
+// FitHandler fits a new model to the POSTed FitRequest and makes it the current model.
+func (s *Server) FitHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body FitRequest
+		if !s.decode(w, req, &body) {
+			return
+		}
+		if len(body.Data) == 0 {
+			writeError(w, http.StatusBadRequest, errors.New("no data points"))
+			return
+		}
+
+		r := new(regression.Regression)
+		if s.Configure != nil {
+			s.Configure(r)
+		}
+		if body.Observed != "" {
+			r.SetObserved(body.Observed)
+		}
+		for i, name := range body.Variables {
+			r.SetVar(i, name)
+		}
+		for _, p := range body.Data {
+			r.Train(regression.LabeledDataPoint(p.Label, p.Observed, p.Variables))
+		}
+		if err := r.Run(); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		rows, err := r.Tidy()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		resp := FitResponse{Formula: r.Formula, R2: Float(r.R2)}
+		for _, row := range rows {
+			c := Coefficient{Term: row.Term, Estimate: Float(row.Estimate)}
+			if stdErr := row.StdErr; !math.IsNaN(stdErr) {
+				c.StdErr = &stdErr
+			}
+			resp.Coefficients = append(resp.Coefficients, c)
+		}
+		for _, warning := range r.Warnings() {
+			resp.Warnings = append(resp.Warnings, warning.Message)
+		}
+
+		s.mu.Lock()
+		s.version++
+		s.model = r
+		resp.Version = s.version
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// PredictHandler predicts each row of variables in the POSTed PredictRequest with the current model.
+func (s *Server) PredictHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body PredictRequest
+		if !s.decode(w, req, &body) {
+			return
+		}
+
+		s.mu.RLock()
+		model, version := s.model, s.version
+		s.mu.RUnlock()
+		if model == nil {
+			writeError(w, http.StatusNotFound, errors.New("no model has been fitted"))
+			return
+		}
+		if body.Version != 0 && body.Version != version {
+			writeError(w, http.StatusConflict, fmt.Errorf("requested model version %d, current version is %d", body.Version, version))
+			return
+		}
+
+		predictions, err := model.PredictBatch(body.Variables)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		resp := PredictResponse{Version: version, Predictions: make([]Float, len(predictions))}
+		for i, p := range predictions {
+			resp.Predictions[i] = Float(p)
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// decode reads the JSON body of a POST request into v, writing an error response if it fails.
+func (s *Server) decode(w http.ResponseWriter, req *http.Request, v interface{}) bool {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return false
+	}
+	limit := s.MaxBodyBytes
+	if limit <= 0 {
+		limit = defaultMaxBodyBytes
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, req.Body, limit))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	resp := ErrorResponse{Error: err.Error()}
+	var pointErr *regression.DataPointError
+	if errors.As(err, &pointErr) {
+		resp.Index = &pointErr.Index
+	}
+	writeJSON(w, status, resp)
+}
+
+// writeJSON encodes v before writing the status, so that a value that cannot be encoded is reported
+// as an internal error rather than as a successful response with an empty body.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		buf.Reset()
+		status = http.StatusInternalServerError
+		json.NewEncoder(&buf).Encode(ErrorResponse{Error: fmt.Sprintf("encoding response: %v", err)})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
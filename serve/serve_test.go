@@ -0,0 +1,123 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func post(t *testing.T, h http.Handler, path, body string, v interface{}) int {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body)))
+	if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return w.Code
+}
+
+func TestServer(t *testing.T) {
+	h := New(nil).Handler()
+
+	var errResp ErrorResponse
+	if code := post(t, h, "/predict", `{"variables": [[1]]}`, &errResp); code != http.StatusNotFound {
+		t.Errorf("Expected 404 before fitting, got %d: %s", code, errResp.Error)
+	}
+
+	var fit FitResponse
+	body := `{"observed": "y", "variables": ["x"], "data": [
+		{"observed": 1, "variables": [0]}, {"observed": 3, "variables": [1]},
+		{"observed": 5.1, "variables": [2]}, {"observed": 6.9, "variables": [3]}]}`
+	if code := post(t, h, "/fit", body, &fit); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if fit.Version != 1 || len(fit.Coefficients) != 2 || fit.Coefficients[1].Term != "x" || fit.Coefficients[1].StdErr == nil {
+		t.Errorf("Unexpected fit response %+v", fit)
+	}
+
+	var pred PredictResponse
+	if code := post(t, h, "/predict", `{"version": 1, "variables": [[0], [10]]}`, &pred); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	want := fit.Coefficients[0].Estimate + 10*fit.Coefficients[1].Estimate
+	if len(pred.Predictions) != 2 || pred.Predictions[1] != want {
+		t.Errorf("Expected prediction %v, got %v", want, pred.Predictions)
+	}
+
+	errResp = ErrorResponse{}
+	if code := post(t, h, "/predict", `{"version": 2, "variables": [[1]]}`, &errResp); code != http.StatusConflict {
+		t.Errorf("Expected 409 for a stale version, got %d", code)
+	}
+	errResp = ErrorResponse{}
+	if code := post(t, h, "/predict", `{"variables": [[1], [1, 2]]}`, &errResp); code != http.StatusBadRequest || errResp.Index == nil || *errResp.Index != 1 {
+		t.Errorf("Expected 400 for point 1, got %d: %+v", code, errResp)
+	}
+	errResp = ErrorResponse{}
+	if code := post(t, h, "/fit", `{"data": [], "extra": 1}`, &errResp); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown field, got %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/predict", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", w.Code)
+	}
+}
+
+func TestFitExact(t *testing.T) {
+	h := New(nil).Handler()
+
+	// Three data points and two variables leave no residual degrees of freedom
+	var fit FitResponse
+	body := `{"data": [{"observed": 1, "variables": [1, 2]}, {"observed": 4, "variables": [2, 1]},
+		{"observed": 6, "variables": [3, 5]}]}`
+	if code := post(t, h, "/fit", body, &fit); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if len(fit.Coefficients) != 3 {
+		t.Fatalf("Unexpected fit response %+v", fit)
+	}
+	for _, c := range fit.Coefficients {
+		if c.StdErr != nil {
+			t.Errorf("%s: expected no standard error, got %v", c.Term, *c.StdErr)
+		}
+	}
+}
+
+func TestNonFiniteResponses(t *testing.T) {
+	h := New(nil).Handler()
+
+	// A constant observed value has no variance to explain, so R² is NaN
+	var fit FitResponse
+	body := `{"data": [{"observed": 2, "variables": [0]}, {"observed": 2, "variables": [1]},
+		{"observed": 2, "variables": [2]}, {"observed": 2, "variables": [3]}]}`
+	if code := post(t, h, "/fit", body, &fit); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if !math.IsNaN(float64(fit.R2)) || len(fit.Coefficients) != 2 {
+		t.Errorf("Expected a null R², got %+v", fit)
+	}
+
+	body = `{"data": [{"observed": 0, "variables": [0]}, {"observed": 2, "variables": [1]},
+		{"observed": 4, "variables": [2]}, {"observed": 6.1, "variables": [3]}]}`
+	if code := post(t, h, "/fit", body, &fit); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	var pred PredictResponse
+	if code := post(t, h, "/predict", `{"variables": [[1], [1e308]]}`, &pred); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if len(pred.Predictions) != 2 || math.IsNaN(float64(pred.Predictions[0])) || !math.IsNaN(float64(pred.Predictions[1])) {
+		t.Errorf("Expected a null prediction for an overflowing row, got %v", pred.Predictions)
+	}
+
+	w := httptest.NewRecorder()
+	writeJSON(w, http.StatusOK, math.Inf(1))
+	var errResp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil || w.Code != http.StatusInternalServerError || errResp.Error == "" {
+		t.Errorf("Expected 500 for a response that cannot be encoded, got %d: %+v, %v", w.Code, errResp, err)
+	}
+}
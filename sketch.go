@@ -0,0 +1,92 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// minSketchRows is the fewest rows of the sketch used by ApproxLeverage by default.
+const minSketchRows = 200
+
+// ApproxLeverage estimates the leverage of each data point from a random sketch of the design
+// matrix, for datasets with too many data points for Leverage. Each row of the design is added,
+// with a random sign, to one of sketchRows rows chosen at random (a CountSketch), and the leverage
+// of each point is estimated as |R⁻ᵀx|², where R is the triangular factor of the sketch rather than
+// of the full design. The design matrix is never formed, so the memory needed grows with the size of
+// the sketch rather than the number of data points, and the work is a single pass over the data.
+//
+// The relative error of the estimates shrinks with the square root of the size of the sketch, and is
+// typically within 10% with 50k² rows for k coefficients, the default if sketchRows is not positive.
+// When the sketch would have as many rows as there are data points the exact leverage is returned.
+// Its random choices can be made reproducible with WithSeed or WithRand. Fits with fixed effects,
+// instruments or censored data return ErrIncompatibleOptions.
+func (r *Regression) ApproxLeverage(sketchRows int, opts ...Option) ([]float64, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	if err := r.requireHat("approximate leverages"); err != nil {
+		return nil, err
+	}
+	active := r.activeColumns(len(r.coeff))
+	k := len(active)
+	if sketchRows <= 0 {
+		sketchRows = max(minSketchRows, 50*k*k)
+	}
+	if sketchRows >= len(r.Data) {
+		return r.Leverage()
+	}
+	rng := newOptions(opts).rand
+
+	designRow := func(d *dataPoint) []float64 {
+		features := append([]float64{1}, r.features(d.Variables)...)
+		row := make([]float64, k)
+		for c, j := range active {
+			row[c] = features[j]
+		}
+		return row
+	}
+	sketch := mat.NewDense(sketchRows, k, nil)
+	for _, d := range r.Data {
+		i, sign := rng.Intn(sketchRows), float64(1-2*rng.Intn(2))
+		for c, v := range designRow(d) {
+			sketch.Set(i, c, sketch.At(i, c)+sign*v)
+		}
+	}
+	qr := new(mat.QR)
+	qr.Factorize(sketch)
+	var factor mat.Dense
+	qr.RTo(&factor)
+	for c := 0; c < k; c++ {
+		if factor.At(c, c) == 0 {
+			return nil, fmt.Errorf("%w: the sketch of column %d is a combination of the others", ErrSingular, c)
+		}
+	}
+
+	// Solve Rᵀz = x by forward substitution, so that |z|² = xᵀ(RᵀR)⁻¹x
+	leverage := make([]float64, len(r.Data))
+	z := make([]float64, k)
+	for i, d := range r.Data {
+		var h float64
+		for c, v := range designRow(d) {
+			for l := 0; l < c; l++ {
+				v -= factor.At(l, c) * z[l]
+			}
+			z[c] = v / factor.At(c, c)
+			h += z[c] * z[c]
+		}
+		leverage[i] = math.Min(1, h)
+	}
+	return leverage, nil
+}
+
+// ApproxInfluence is Influence using the leverage estimated by ApproxLeverage, so that influence
+// diagnostics scale to datasets with too many data points for the exact leverage.
+func (r *Regression) ApproxInfluence(sketchRows int, opts ...Option) (*InfluenceReport, error) {
+	leverage, err := r.ApproxLeverage(sketchRows, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return r.influence(leverage), nil
+}
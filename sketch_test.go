@@ -0,0 +1,55 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestApproxLeverage(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := new(Regression)
+	for i := 0; i < 2000; i++ {
+		x1, x2 := rng.NormFloat64(), rng.ExpFloat64()
+		r.Train(DataPoint(1+x1+x2+rng.NormFloat64(), []float64{x1, x2}))
+	}
+	r.Train(DataPoint(0, []float64{8, 20}))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	exact, err := r.Leverage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	approx, err := r.ApproxLeverage(0, WithSeed(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range exact {
+		if math.Abs(approx[i]/exact[i]-1) > 0.2 {
+			t.Fatalf("Data point %d: expected leverage %v, got %v", i, exact[i], approx[i])
+		}
+	}
+	again, _ := r.ApproxLeverage(0, WithSeed(2))
+	if again[0] != approx[0] {
+		t.Error("Expected the same estimates with the same seed")
+	}
+	if all, _ := r.ApproxLeverage(len(r.Data)); all[0] != exact[0] {
+		t.Errorf("Expected the exact leverage for a sketch as large as the data, got %v", all[0])
+	}
+
+	report, err := r.ApproxInfluence(0, WithSeed(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := report.Points[len(report.Points)-1]
+	if last.Index != 2000 || !last.HighLeverage || !last.HighCooksDistance {
+		t.Errorf("Expected the last point to be flagged, got %+v", last)
+	}
+
+	if _, err := new(Regression).ApproxLeverage(0); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+}
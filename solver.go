@@ -0,0 +1,283 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Solver selects how ordinary least squares fits are solved.
+type Solver int
+
+const (
+	// SolverQR solves by QR decomposition, the default.
+	SolverQR Solver = iota
+	// SolverSVD solves by singular value decomposition, truncating singular values that are zero
+	// to working precision so that rank deficient designs give the minimum norm solution.
+	SolverSVD
+	// SolverRidge solves by singular value decomposition with a small ridge penalty, which keeps
+	// every coefficient determined when the design is rank deficient at the cost of a slight bias.
+	SolverRidge
+	// SolverAuto estimates the condition number and rank of the design and chooses QR for well
+	// conditioned designs, SVD for ill-conditioned ones and the ridge for rank deficient ones.
+	SolverAuto
+	// SolverBigFloat solves the normal equations in arbitrary precision arithmetic, for benchmark
+	// problems so ill-conditioned that float64 QR loses most digits. It is much slower than the other
+	// solvers and is never chosen by SolverAuto.
+	SolverBigFloat
+)
+
+// autoMaxCondition is the condition number of the design matrix, with columns scaled to unit length,
+// above which SolverAuto switches from QR to SVD.
+const autoMaxCondition = 1e8
+
+// ridgeShrinkage is the ridge penalty of SolverRidge relative to the square of the largest singular value.
+const ridgeShrinkage = 1e-12
+
+// String returns the name of the solver.
+func (s Solver) String() string {
+	switch s {
+	case SolverQR:
+		return "QR"
+	case SolverSVD:
+		return "SVD"
+	case SolverRidge:
+		return "ridge"
+	case SolverAuto:
+		return "auto"
+	case SolverBigFloat:
+		return "big float"
+	}
+	return "unknown"
+}
+
+// SetSolver sets how ordinary least squares fits are solved. Other estimators, such as constrained
+// or instrumented fits, are unaffected.
+func (r *Regression) SetSolver(s Solver) {
+	r.solver = s
+}
+
+// SolverUsed returns the solver that fitted the model, after any automatic choice.
+func (r *Regression) SolverUsed() Solver {
+	return r.solverUsed
+}
+
+// chooseSolver resolves SolverAuto from the singular values of the design.
+func (r *Regression) chooseSolver(variables *mat.Dense) Solver {
+	if r.solver != SolverAuto {
+		return r.solver
+	}
+	svd, _, ok := equilibratedSVD(variables)
+	if !ok {
+		return SolverRidge
+	}
+	s := svd.Values(nil)
+	rank := numericalRank(s, variables)
+	switch {
+	case rank < len(s):
+		return SolverRidge
+	case s[0]/s[len(s)-1] > autoMaxCondition:
+		return SolverSVD
+	}
+	return SolverQR
+}
+
+// equilibratedSVD factorizes the design with each column scaled to unit length, returning the scale of each column.
+func equilibratedSVD(variables *mat.Dense) (*mat.SVD, []float64, bool) {
+	rows, cols := variables.Dims()
+	scale := make([]float64, cols)
+	a := mat.NewDense(rows, cols, nil)
+	col := make([]float64, rows)
+	for j := 0; j < cols; j++ {
+		mat.Col(col, j, variables)
+		scale[j] = floats.Norm(col, 2)
+		if scale[j] == 0 {
+			scale[j] = 1
+		}
+		floats.Scale(1/scale[j], col)
+		a.SetCol(j, col)
+	}
+	svd := new(mat.SVD)
+	ok := svd.Factorize(a, mat.SVDThin)
+	return svd, scale, ok
+}
+
+// numericalRank counts the singular values above max(rows, cols)·ε·s₁, the usual tolerance for numerical rank.
+func numericalRank(s []float64, variables mat.Matrix) int {
+	rows, cols := variables.Dims()
+	tol := float64(max(rows, cols)) * s[0] * (math.Nextafter(1, 2) - 1)
+	rank := 0
+	for _, v := range s {
+		if v > tol {
+			rank++
+		}
+	}
+	return rank
+}
+
+// svdLeastSquares fits the coefficients from the singular value decomposition of the design with
+// columns scaled to unit length, with a ridge penalty for SolverRidge, and records their covariance.
+func (r *Regression) svdLeastSquares(observed, variables *mat.Dense, solver Solver) []float64 {
+	rows, n := variables.Dims()
+	r.factor = nil
+	svd, scale, ok := equilibratedSVD(variables)
+	if !ok {
+		r.cov = nil
+		r.residualDF = rows - n
+		return make([]float64, n)
+	}
+	s := svd.Values(nil)
+	rank := numericalRank(s, variables)
+	var lambda float64
+	if solver == SolverRidge {
+		lambda = ridgeShrinkage * s[0] * s[0]
+	}
+
+	// The filter factors are 1/s for the truncated solution and s/(s² + λ) for the ridge
+	filter := make([]float64, len(s))
+	for i, v := range s {
+		if i < rank || lambda > 0 {
+			filter[i] = v / (v*v + lambda)
+		}
+	}
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	var uty mat.Dense
+	uty.Mul(u.T(), observed)
+	c := make([]float64, n)
+	for j := range c {
+		for i, f := range filter {
+			c[j] += v.At(j, i) * f * uty.At(i, 0)
+		}
+		c[j] /= scale[j]
+	}
+
+	if lambda == 0 {
+		n = rank
+	}
+	r.residualDF = rows - n
+	r.cov = nil
+	if r.residualDF > 0 && (lambda > 0 || rank == len(s)) {
+		sigma2 := sumOfSquaredResiduals(variables, observed, c) / float64(r.residualDF)
+		r.cov = mat.NewSymDense(len(c), nil)
+		for j := range c {
+			for k := j; k < len(c); k++ {
+				var cov float64
+				for i, f := range filter {
+					cov += v.At(j, i) * f * f * v.At(k, i)
+				}
+				r.cov.SetSym(j, k, sigma2*cov/(scale[j]*scale[k]))
+			}
+		}
+	}
+	return c
+}
+
+// bigFloatPrecision is the mantissa precision in bits of SolverBigFloat. Products of float64 values are
+// exact at 106 bits, and the remainder leaves about 45 correct decimal digits after forming and
+// inverting a cross product matrix with a condition number of 10^30.
+const bigFloatPrecision = 256
+
+// bigFloatLeastSquares fits the coefficients by Gauss-Jordan elimination with partial pivoting of the
+// normal equations, formed and solved in bigFloatPrecision arithmetic, and records their covariance.
+func (r *Regression) bigFloatLeastSquares(observed, variables *mat.Dense) ([]float64, error) {
+	rows, n := variables.Dims()
+	r.factor = nil
+	r.residualDF = rows - n
+	newFloat := func(x float64) *big.Float {
+		return new(big.Float).SetPrec(bigFloatPrecision).SetFloat64(x)
+	}
+	x := make([][]*big.Float, rows)
+	y := make([]*big.Float, rows)
+	for i := range x {
+		if v := observed.At(i, 0); math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, &DataPointError{Index: i, Err: fmt.Errorf("%w: observed value is %v", ErrDesign, v)}
+		}
+		y[i] = newFloat(observed.At(i, 0))
+		x[i] = make([]*big.Float, n)
+		for j := range x[i] {
+			if v := variables.At(i, j); math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, &DataPointError{Index: i, Err: fmt.Errorf("%w: feature %d is %v", ErrDesign, j, v)}
+			}
+			x[i][j] = newFloat(variables.At(i, j))
+		}
+	}
+
+	// Each row of a is that of the cross product matrix XᵀX, then the identity, then Xᵀy, so that
+	// elimination leaves the inverse of XᵀX and the coefficients in its place
+	a := make([][]*big.Float, n)
+	prod := newFloat(0)
+	for j := range a {
+		a[j] = make([]*big.Float, 2*n+1)
+		for k := range a[j] {
+			a[j][k] = newFloat(0)
+		}
+		a[j][n+j].SetInt64(1)
+		for i := range x {
+			for k := j; k < n; k++ {
+				a[j][k].Add(a[j][k], prod.Mul(x[i][j], x[i][k]))
+			}
+			a[j][2*n].Add(a[j][2*n], prod.Mul(x[i][j], y[i]))
+		}
+		for k := 0; k < j; k++ {
+			a[j][k].Set(a[k][j])
+		}
+	}
+
+	abs := newFloat(0)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if abs.Abs(a[row][col]).Cmp(new(big.Float).Abs(a[pivot][col])) > 0 {
+				pivot = row
+			}
+		}
+		if a[pivot][col].Sign() == 0 {
+			return nil, fmt.Errorf("%w: column %d is a combination of the others", ErrSingular, col)
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		inv := newFloat(1)
+		inv.Quo(inv, a[col][col])
+		for k := range a[col] {
+			a[col][k].Mul(a[col][k], inv)
+		}
+		for row := range a {
+			if row == col || a[row][col].Sign() == 0 {
+				continue
+			}
+			factor := newFloat(0).Set(a[row][col])
+			for k := range a[row] {
+				a[row][k].Sub(a[row][k], prod.Mul(factor, a[col][k]))
+			}
+		}
+	}
+
+	c := make([]float64, n)
+	for j := range c {
+		c[j], _ = a[j][2*n].Float64()
+	}
+	r.cov = nil
+	if r.residualDF > 0 {
+		sse, e := newFloat(0), newFloat(0)
+		for i := range x {
+			e.Set(y[i])
+			for j := range x[i] {
+				e.Sub(e, prod.Mul(x[i][j], a[j][2*n]))
+			}
+			sse.Add(sse, prod.Mul(e, e))
+		}
+		sigma2 := sse.Quo(sse, newFloat(float64(r.residualDF)))
+		r.cov = mat.NewSymDense(n, nil)
+		for j := 0; j < n; j++ {
+			for k := j; k < n; k++ {
+				v, _ := prod.Mul(sigma2, a[j][n+k]).Float64()
+				r.cov.SetSym(j, k, v)
+			}
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,120 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSolver(t *testing.T) {
+	fit := func(solver Solver, data [][]float64) *Regression {
+		r := new(Regression)
+		r.SetSolver(solver)
+		r.Train(MakeDataPoints(data, 0)...)
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	// A well conditioned design is solved by QR, and SVD gives the same fit
+	qr := fit(SolverAuto, anscombe)
+	if qr.SolverUsed() != SolverQR {
+		t.Errorf("Expected QR for a well conditioned design, got %v", qr.SolverUsed())
+	}
+	svd := fit(SolverSVD, anscombe)
+	for i := range qr.GetCoeffs() {
+		if math.Abs(svd.Coeff(i)-qr.Coeff(i)) > 1e-10 || math.Abs(svd.StdErr(i)-qr.StdErr(i)) > 1e-10 {
+			t.Errorf("Coefficient %d: expected %v ± %v from SVD, got %v ± %v", i, qr.Coeff(i), qr.StdErr(i), svd.Coeff(i), svd.StdErr(i))
+		}
+	}
+	if s, _ := svd.Glance(); s.Solver != SolverSVD {
+		t.Errorf("Expected Glance to report SVD, got %v", s.Solver)
+	}
+
+	// Nearly collinear variables are solved by SVD
+	nearly := make([][]float64, 10)
+	for i := range nearly {
+		x := float64(i)
+		nearly[i] = []float64{1 + 2*x + float64(i%3), x, x + 1e-9*float64(i%2)}
+	}
+	if r := fit(SolverAuto, nearly); r.SolverUsed() != SolverSVD {
+		t.Errorf("Expected SVD for an ill-conditioned design, got %v", r.SolverUsed())
+	}
+
+	// Exactly collinear variables are solved by the ridge, which shares the effect between them
+	exactly := make([][]float64, 10)
+	for i := range exactly {
+		x := float64(i)
+		exactly[i] = []float64{1 + 3*x + float64(i%3), x, 2 * x}
+	}
+	r := fit(SolverAuto, exactly)
+	if r.SolverUsed() != SolverRidge {
+		t.Errorf("Expected the ridge for a rank deficient design, got %v", r.SolverUsed())
+	}
+	if effect := r.Coeff(1) + 2*r.Coeff(2); math.Abs(effect-3) > 0.2 {
+		t.Errorf("Expected a combined effect of about 3, got %v", effect)
+	}
+	// Columns are scaled to unit length, so the effect is split equally on that scale
+	if math.Abs(r.Coeff(1)-2*r.Coeff(2)) > 1e-6 {
+		t.Errorf("Expected an equal split of the effect on the scaled columns, got %v and %v", r.Coeff(1), r.Coeff(2))
+	}
+	if math.IsNaN(r.StdErr(1)) {
+		t.Error("Expected a standard error from the ridge")
+	}
+}
+
+func TestSolverBigFloat(t *testing.T) {
+	// A quadratic far from the origin is so ill-conditioned that float64 QR loses most digits, though
+	// every value is an integer that float64 holds exactly. The expected fit was found in exact
+	// rational arithmetic.
+	data := make([][]float64, 12)
+	for i := range data {
+		x := 1e5 + float64(i)
+		data[i] = []float64{1 + 2*x + 3*x*x + float64(i%2), x, x * x}
+	}
+	coeffs := []float64{-2096.5174825174827, 2.020979020979021, 3}
+	stdErrs := []float64{1.5638559091750148e+08, 3127.5398062928844, 0.015636839003495034}
+	r := new(Regression)
+	r.SetSolver(SolverBigFloat)
+	r.Train(MakeDataPoints(data, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range coeffs {
+		if math.Abs(r.Coeff(i)-coeffs[i]) > 1e-12*math.Abs(coeffs[i]) || math.Abs(r.StdErr(i)-stdErrs[i]) > 1e-12*stdErrs[i] {
+			t.Errorf("Coefficient %d: expected %v ± %v, got %v ± %v", i, coeffs[i], stdErrs[i], r.Coeff(i), r.StdErr(i))
+		}
+	}
+	if r.SolverUsed() != SolverBigFloat || r.SolverUsed().String() != "big float" {
+		t.Errorf("Expected the big float solver, got %v", r.SolverUsed())
+	}
+
+	// An exactly singular design cannot be solved even in high precision
+	singular := make([][]float64, 6)
+	for i := range singular {
+		x := float64(i)
+		singular[i] = []float64{x + float64(i%2), x, 2 * x}
+	}
+	r = new(Regression)
+	r.SetSolver(SolverBigFloat)
+	r.Train(MakeDataPoints(singular, 0)...)
+	if err := r.Run(); !errors.Is(err, ErrSingular) {
+		t.Errorf("Expected ErrSingular, got %v", err)
+	}
+
+	// Values that big.Float cannot represent are rejected rather than panicking
+	for _, v := range []float64{math.NaN(), math.Inf(1)} {
+		observed := mat.NewDense(3, 1, []float64{1, v, 3})
+		variables := mat.NewDense(3, 2, []float64{1, 0, 1, 1, 1, 2})
+		if _, err := new(Regression).bigFloatLeastSquares(observed, variables); !errors.Is(err, ErrDesign) {
+			t.Errorf("Expected ErrDesign for an observed value of %v, got %v", v, err)
+		}
+		variables.Set(2, 1, v)
+		if _, err := new(Regression).bigFloatLeastSquares(mat.NewDense(3, 1, []float64{1, 2, 3}), variables); !errors.Is(err, ErrDesign) {
+			t.Errorf("Expected ErrDesign for a feature of %v, got %v", v, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// StabilityTest holds the path of a CUSUM or CUSUM of squares statistic along with the
+// boundaries it is compared against. Each slice has one entry per recursive residual.
+type StabilityTest struct {
+	Statistic    []float64
+	Lower        []float64
+	Upper        []float64
+	Significance float64
+	// Stable is false if the statistic crosses either boundary.
+	Stable bool
+}
+
+// cusumCritical holds the Brown, Durbin and Evans (1975) boundary constants for the CUSUM test.
+var cusumCritical = map[float64]float64{
+	0.01: 1.143,
+	0.05: 0.948,
+	0.10: 0.850,
+}
+
+// cusumsqCritical holds the asymptotic critical values for the CUSUM of squares test.
+var cusumsqCritical = map[float64]float64{
+	0.01: 1.6276,
+	0.05: 1.3581,
+	0.10: 1.2239,
+}
+
+// RecursiveResiduals returns the standardized one-step-ahead prediction errors of the model,
+// fitting on the first t observations to predict observation t+1, in the order the data was trained.
+// The first len(GetCoeffs()) observations are used to initialise the recursion and have no residual.
+func (r *Regression) RecursiveResiduals() ([]float64, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	observed, variables := r.designMatrix()
+	n, k := variables.Dims()
+	if n <= k {
+		return nil, fmt.Errorf("%w: %d observations for %d coefficients", ErrNotEnoughData, n, k)
+	}
+
+	residuals := make([]float64, 0, n-k)
+	for t := k; t < n; t++ {
+		x := variables.Slice(0, t, 0, k)
+		y := observed.Slice(0, t, 0, 1)
+		coeffs, err := leastSquares(x, y)
+		if err != nil {
+			return nil, err
+		}
+		inv, err := crossProductInverse(x)
+		if err != nil {
+			return nil, err
+		}
+		row := variables.RowView(t)
+		pred := mat.Dot(row, mat.NewVecDense(k, coeffs))
+		f := 1 + mat.Inner(row, inv, row)
+		residuals = append(residuals, (observed.At(t, 0)-pred)/math.Sqrt(f))
+	}
+	return residuals, nil
+}
+
+// CUSUM runs the Brown, Durbin and Evans cumulative sum test for parameter stability over the
+// order the data was trained in. Supported significance levels are 0.01, 0.05 and 0.10.
+func (r *Regression) CUSUM(significance float64) (*StabilityTest, error) {
+	a, ok := cusumCritical[significance]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrSignificance, significance)
+	}
+	w, err := r.RecursiveResiduals()
+	if err != nil {
+		return nil, err
+	}
+	m := len(w)
+	if m < 2 {
+		return nil, fmt.Errorf("%w: %d recursive residuals, need at least 2", ErrNotEnoughData, m)
+	}
+
+	var mean float64
+	for _, v := range w {
+		mean += v
+	}
+	mean /= float64(m)
+	var ss float64
+	for _, v := range w {
+		ss += (v - mean) * (v - mean)
+	}
+	sigma := math.Sqrt(ss / float64(m-1))
+
+	test := &StabilityTest{
+		Statistic:    make([]float64, m),
+		Lower:        make([]float64, m),
+		Upper:        make([]float64, m),
+		Significance: significance,
+		Stable:       true,
+	}
+	root := math.Sqrt(float64(m))
+	var sum float64
+	for i, v := range w {
+		sum += v
+		bound := a*root + 2*a*float64(i+1)/root
+		test.Statistic[i] = sum / sigma
+		test.Lower[i] = -bound
+		test.Upper[i] = bound
+		if math.Abs(test.Statistic[i]) > bound {
+			test.Stable = false
+		}
+	}
+	return test, nil
+}
+
+// CUSUMSQ runs the cumulative sum of squares test for parameter stability over the order the data
+// was trained in. The boundaries use the asymptotic critical values, so are approximate for small samples.
+// Supported significance levels are 0.01, 0.05 and 0.10.
+func (r *Regression) CUSUMSQ(significance float64) (*StabilityTest, error) {
+	c, ok := cusumsqCritical[significance]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrSignificance, significance)
+	}
+	w, err := r.RecursiveResiduals()
+	if err != nil {
+		return nil, err
+	}
+	m := len(w)
+	if m < 4 {
+		return nil, fmt.Errorf("%w: %d recursive residuals, need at least 4", ErrNotEnoughData, m)
+	}
+
+	var total float64
+	for _, v := range w {
+		total += v * v
+	}
+	c0 := c / math.Sqrt(float64(m)/2-1)
+
+	test := &StabilityTest{
+		Statistic:    make([]float64, m),
+		Lower:        make([]float64, m),
+		Upper:        make([]float64, m),
+		Significance: significance,
+		Stable:       true,
+	}
+	var sum float64
+	for i, v := range w {
+		sum += v * v
+		expected := float64(i+1) / float64(m)
+		test.Statistic[i] = sum / total
+		test.Lower[i] = expected - c0
+		test.Upper[i] = expected + c0
+		if test.Statistic[i] < test.Lower[i] || test.Statistic[i] > test.Upper[i] {
+			test.Stable = false
+		}
+	}
+	return test, nil
+}
+
+// ChowTest holds the result of a Chow test for a structural break, comparing the pooled model
+// against separate models fitted before and after the split.
+type ChowTest struct {
+	// Split is the index of the first observation after the break.
+	Split int
+	F     float64
+	DF1   int
+	DF2   int
+	P     float64
+}
+
+// Chow tests whether the coefficients are equal before and after the split index, in the order
+// the data was trained in. Both sub-periods must have at least as many observations as coefficients.
+func (r *Regression) Chow(split int) (*ChowTest, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	observed, variables := r.designMatrix()
+	return chowTest(observed, variables, split)
+}
+
+// ChowBreakpoints runs the Chow test at each of the candidate split indices, returning the tests
+// in the same order. If no candidates are given every feasible split is tested. Note that the
+// p-values do not account for searching over multiple break points.
+func (r *Regression) ChowBreakpoints(candidates ...int) ([]*ChowTest, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	observed, variables := r.designMatrix()
+	n, k := variables.Dims()
+	if len(candidates) == 0 {
+		for split := k; split <= n-k; split++ {
+			candidates = append(candidates, split)
+		}
+	}
+
+	tests := make([]*ChowTest, 0, len(candidates))
+	for _, split := range candidates {
+		test, err := chowTest(observed, variables, split)
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+func chowTest(observed, variables *mat.Dense, split int) (*ChowTest, error) {
+	n, k := variables.Dims()
+	if split < k || n-split < k || n <= 2*k {
+		return nil, fmt.Errorf("%w: split at %d of %d observations leaves fewer than %d on a side", ErrNotEnoughData, split, n, k)
+	}
+
+	sse := func(from, to int) (float64, error) {
+		x := variables.Slice(from, to, 0, k)
+		y := observed.Slice(from, to, 0, 1)
+		coeffs, err := leastSquares(x, y)
+		if err != nil {
+			return 0, err
+		}
+		return sumOfSquaredResiduals(x, y, coeffs), nil
+	}
+	pooled, err := sse(0, n)
+	if err != nil {
+		return nil, err
+	}
+	before, err := sse(0, split)
+	if err != nil {
+		return nil, err
+	}
+	after, err := sse(split, n)
+	if err != nil {
+		return nil, err
+	}
+
+	test := &ChowTest{Split: split, DF1: k, DF2: n - 2*k}
+	test.F = ((pooled - before - after) / float64(test.DF1)) / ((before + after) / float64(test.DF2))
+	test.P = distuv.F{D1: float64(test.DF1), D2: float64(test.DF2)}.Survival(test.F)
+	return test, nil
+}
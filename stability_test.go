@@ -0,0 +1,181 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// linearSeries builds a single variable data set following y = 2 + 3x with a small deterministic
+// wobble. After the break index the slope changes to slope.
+func linearSeries(n, breakAt int, slope float64) []*dataPoint {
+	points := make([]*dataPoint, 0, n)
+	for i := 0; i < n; i++ {
+		x := float64(i)
+		y := 2 + 3*x + math.Sin(float64(i)*1.7)
+		if i >= breakAt {
+			y = 2 + 3*float64(breakAt) + slope*(x-float64(breakAt)) + math.Sin(float64(i)*1.7)
+		}
+		points = append(points, DataPoint(y, []float64{x}))
+	}
+	return points
+}
+
+func TestRecursiveResiduals(t *testing.T) {
+	r := new(Regression)
+	r.Train(linearSeries(30, 30, 3)...)
+	if _, err := r.RecursiveResiduals(); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := r.RecursiveResiduals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w) != 28 {
+		t.Errorf("Expected 28 recursive residuals, got %v", len(w))
+	}
+
+	// The recursive residuals sum of squares is the same as the full fit's
+	var ssw, sse float64
+	for _, v := range w {
+		ssw += v * v
+	}
+	for _, d := range r.Data {
+		sse += d.Error * d.Error
+	}
+	if math.Abs(ssw-sse) > 1e-8 {
+		t.Errorf("Expected recursive residual sum of squares %v to equal the residual sum of squares %v", ssw, sse)
+	}
+}
+
+func TestCUSUM(t *testing.T) {
+	r := new(Regression)
+	r.Train(linearSeries(40, 40, 3)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CUSUM(0.2); !errors.Is(err, ErrSignificance) {
+		t.Errorf("Expected ErrSignificance, got %v", err)
+	}
+	test, err := r.CUSUM(0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !test.Stable {
+		t.Error("Expected a stable model")
+	}
+
+	r = new(Regression)
+	r.Train(linearSeries(40, 20, 6)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	test, err = r.CUSUM(0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Stable {
+		t.Error("Expected the structural break to be detected")
+	}
+}
+
+func TestCUSUMSQ(t *testing.T) {
+	r := new(Regression)
+	r.Train(linearSeries(40, 40, 3)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	test, err := r.CUSUMSQ(0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !test.Stable {
+		t.Error("Expected a stable model")
+	}
+	if last := test.Statistic[len(test.Statistic)-1]; math.Abs(last-1) > 1e-12 {
+		t.Errorf("Expected the statistic to end at 1, got %v", last)
+	}
+
+	r = new(Regression)
+	r.Train(linearSeries(40, 20, 6)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	test, err = r.CUSUMSQ(0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.Stable {
+		t.Error("Expected the structural break to be detected")
+	}
+}
+
+func TestChow(t *testing.T) {
+	r := new(Regression)
+	r.Train(linearSeries(40, 40, 3)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	test, err := r.Chow(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.DF1 != 2 || test.DF2 != 36 {
+		t.Errorf("Expected degrees of freedom 2 and 36, got %v and %v", test.DF1, test.DF2)
+	}
+	if test.P < 0.05 {
+		t.Errorf("Expected no structural break, got p = %.4f", test.P)
+	}
+	if _, err := r.Chow(1); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+
+	r = new(Regression)
+	r.Train(linearSeries(40, 20, 6)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	test, err = r.Chow(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test.P > 0.01 {
+		t.Errorf("Expected a structural break, got p = %.4f", test.P)
+	}
+}
+
+func TestChowBreakpoints(t *testing.T) {
+	r := new(Regression)
+	r.Train(linearSeries(40, 25, 6)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	tests, err := r.ChowBreakpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) != 37 {
+		t.Errorf("Expected 37 candidate splits, got %v", len(tests))
+	}
+	best := tests[0]
+	for _, test := range tests {
+		if test.F > best.F {
+			best = test
+		}
+	}
+	if best.Split < 23 || best.Split > 27 {
+		t.Errorf("Expected the break to be found near 25, got %v", best.Split)
+	}
+
+	tests, err = r.ChowBreakpoints(10, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) != 2 || tests[1].Split != 25 {
+		t.Errorf("Expected tests for the candidates in order, got %v", tests)
+	}
+}
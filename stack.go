@@ -0,0 +1,62 @@
+package regression
+
+import (
+	"fmt"
+)
+
+// Predictor predicts an observed value from the variables, as Regression and Ensemble do.
+type Predictor interface {
+	Predict(vars []float64) (float64, error)
+}
+
+// Stack blends the predictions of several models with a meta-regression.
+type Stack struct {
+	Models []Predictor
+	// Meta regresses the observed value on the predictions of the models, with variable i the
+	// prediction of model i.
+	Meta *Regression
+}
+
+// NewStack fits a meta-regression of the observed values of data on the predictions of the models. As
+// Breiman recommends, the weight of each model is constrained to be non-negative. The data should be held
+// out from the data the models were trained on, or the blend favours the models that overfit most.
+func NewStack(data DataPoints, models ...Predictor) (*Stack, error) {
+	meta := new(Regression)
+	meta.SetObserved("Observed")
+	for j := range models {
+		meta.SetVar(j, fmt.Sprintf("Model %d", j))
+		meta.SetCoeffSign(j, NonNegative)
+	}
+	for i, d := range data {
+		predictions, err := predictAll(models, d.Variables)
+		if err != nil {
+			return nil, &DataPointError{Index: i, Err: err}
+		}
+		meta.Train(DataPoint(d.Observed, predictions))
+	}
+	if err := meta.Run(); err != nil {
+		return nil, err
+	}
+	return &Stack{Models: models, Meta: meta}, nil
+}
+
+// Predict blends the predictions of the models for the variables.
+func (s *Stack) Predict(vars []float64) (float64, error) {
+	predictions, err := predictAll(s.Models, vars)
+	if err != nil {
+		return 0, err
+	}
+	return s.Meta.Predict(predictions)
+}
+
+func predictAll(models []Predictor, vars []float64) ([]float64, error) {
+	predictions := make([]float64, len(models))
+	for j, m := range models {
+		p, err := m.Predict(vars)
+		if err != nil {
+			return nil, fmt.Errorf("model %d: %w", j, err)
+		}
+		predictions[j] = p
+	}
+	return predictions, nil
+}
@@ -0,0 +1,59 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	// One model sees x0 and the other x1; the truth needs both
+	var train, holdout DataPoints
+	for i := 0; i < 60; i++ {
+		x0, x1 := float64(i%7), math.Sin(float64(i))
+		d := DataPoint(x0+4*x1+0.1*math.Cos(float64(i*i)), []float64{x0, x1})
+		if i%2 == 0 {
+			train = append(train, d)
+		} else {
+			holdout = append(holdout, d)
+		}
+	}
+	onlyX0, onlyX1 := new(Regression), new(Regression)
+	for _, d := range train {
+		onlyX0.Train(DataPoint(d.Observed, []float64{d.Variables[0], 0}))
+		onlyX1.Train(DataPoint(d.Observed, []float64{0, d.Variables[1]}))
+	}
+	onlyX0.SetDropConstant(true)
+	onlyX1.SetDropConstant(true)
+	onlyX0.Run()
+	onlyX1.Run()
+
+	s, err := NewStack(holdout, onlyX0, onlyX1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.Meta.Evaluate(s.Meta.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, base := range []*Regression{onlyX0, onlyX1} {
+		b, _ := base.Evaluate(holdout)
+		if m.RMSE >= b.RMSE {
+			t.Errorf("Expected the stack to beat each model, got RMSE %v against %v", m.RMSE, b.RMSE)
+		}
+	}
+	for j := 1; j <= 2; j++ {
+		if s.Meta.Coeff(j) < 0 {
+			t.Errorf("Expected non-negative weights, got %v", s.Meta.GetCoeffs())
+		}
+	}
+
+	p, err := s.Predict([]float64{3, 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p0, _ := onlyX0.Predict([]float64{3, 0.5})
+	p1, _ := onlyX1.Predict([]float64{3, 0.5})
+	if expected := s.Meta.Coeff(0) + s.Meta.Coeff(1)*p0 + s.Meta.Coeff(2)*p1; math.Abs(p-expected) > 1e-12 {
+		t.Errorf("Expected the blended prediction %v, got %v", expected, p)
+	}
+}
@@ -0,0 +1,189 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Transform is a candidate transformation of a variable or of the observed value.
+type Transform int
+
+const (
+	// TransformLog takes the natural logarithm, for positive values that are right skewed or whose
+	// spread grows in proportion to their level. It is LogResponse in a pipeline.
+	TransformLog Transform = iota
+	// TransformSqrt takes the square root, for non-negative values such as counts, whose variance
+	// grows with their level. It is equivalent to BoxCox(0.5).
+	TransformSqrt
+	// TransformReciprocal takes 1/x, for positive values that are strongly right skewed, such as
+	// times that are better expressed as rates. It is equivalent to BoxCox(-1).
+	TransformReciprocal
+)
+
+// skewThreshold is the absolute skewness above which SuggestTransforms considers a column skewed.
+const skewThreshold = 1
+
+// suggestSignificance is the significance level of the tests of residual patterns in SuggestTransforms.
+const suggestSignificance = 0.05
+
+// String returns the name of the transform.
+func (t Transform) String() string {
+	switch t {
+	case TransformLog:
+		return "log"
+	case TransformSqrt:
+		return "sqrt"
+	case TransformReciprocal:
+		return "reciprocal"
+	}
+	return "unknown"
+}
+
+// apply returns the transformed value, or NaN outside the domain of the transform.
+func (t Transform) apply(x float64) float64 {
+	switch {
+	case t == TransformSqrt && x >= 0:
+		return math.Sqrt(x)
+	case t == TransformLog && x > 0:
+		return math.Log(x)
+	case t == TransformReciprocal && x > 0:
+		return 1 / x
+	}
+	return math.NaN()
+}
+
+// TransformSuggestion is a transformation that may improve the model, with the evidence for it.
+type TransformSuggestion struct {
+	// Index is the index of the variable to transform, or -1 for the observed value, and Name its name.
+	Index     int
+	Name      string
+	Transform Transform
+	Reason    string
+	// Skewness is the skewness of the column before and after the transformation. For suggestions
+	// made from a residual pattern they are both NaN.
+	Skewness            float64
+	TransformedSkewness float64
+}
+
+// SuggestTransforms inspects the training data and residuals of the fitted model for signs that a
+// transformation would help, and suggests candidates without applying them:
+//
+//   - a skewed observed value or variable, with absolute skewness above 1, is matched with the log,
+//     square root or reciprocal transform that most reduces its skewness;
+//   - residuals whose spread grows with the fitted values suggest a log, or for values including
+//     zero a square root, of the observed value;
+//   - residuals with a curved trend against a positive variable suggest its log.
+//
+// Residual patterns are tested at the 5% level. The suggestions are candidates to be compared by
+// refitting, for example in a Pipeline, not conclusions.
+func (r *Regression) SuggestTransforms() ([]TransformSuggestion, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	n := len(r.Data)
+	observed := make([]float64, n)
+	fitted := make([]float64, n)
+	spread := make([]float64, n)
+	residuals := make([]float64, n)
+	for i, d := range r.Data {
+		observed[i] = d.Observed
+		fitted[i] = d.Predicted
+		residuals[i] = d.Observed - d.Predicted
+		spread[i] = math.Abs(residuals[i])
+	}
+	name := r.GetObserved()
+	if name == "" {
+		name = "Observed"
+	}
+
+	var suggestions []TransformSuggestion
+	if s, ok := suggestForSkew(-1, name, observed); ok {
+		suggestions = append(suggestions, s)
+	}
+	if rho, p := correlationTest(fitted, spread); rho > 0 && p < suggestSignificance {
+		t := TransformLog
+		if floats.Min(observed) <= 0 {
+			t = TransformSqrt
+		}
+		if !math.IsNaN(t.apply(floats.Min(observed))) {
+			suggestions = append(suggestions, TransformSuggestion{
+				Index:               -1,
+				Name:                name,
+				Transform:           t,
+				Reason:              fmt.Sprintf("the spread of the residuals grows with the fitted values (r = %.2f, p = %.2g)", rho, p),
+				Skewness:            math.NaN(),
+				TransformedSkewness: math.NaN(),
+			})
+		}
+	}
+
+	col := make([]float64, n)
+	for j := 0; j < r.rawVars; j++ {
+		for i, d := range r.Data {
+			col[i] = d.Variables[j]
+		}
+		if s, ok := suggestForSkew(j, r.GetVar(j), col); ok {
+			suggestions = append(suggestions, s)
+		}
+		if floats.Min(col) <= 0 {
+			continue
+		}
+		mean := stat.Mean(col, nil)
+		curve := make([]float64, n)
+		for i, x := range col {
+			curve[i] = (x - mean) * (x - mean)
+		}
+		if rho, p := correlationTest(curve, residuals); p < suggestSignificance {
+			suggestions = append(suggestions, TransformSuggestion{
+				Index:               j,
+				Name:                r.GetVar(j),
+				Transform:           TransformLog,
+				Reason:              fmt.Sprintf("the residuals curve against the variable (r = %.2f with its squared deviation, p = %.2g)", rho, p),
+				Skewness:            math.NaN(),
+				TransformedSkewness: math.NaN(),
+			})
+		}
+	}
+	return suggestions, nil
+}
+
+// suggestForSkew returns the transform of a skewed column that most reduces its absolute skewness,
+// if it is skewed and a transform reduces it.
+func suggestForSkew(index int, name string, x []float64) (TransformSuggestion, bool) {
+	skew := stat.Skew(x, nil)
+	if !(math.Abs(skew) > skewThreshold) {
+		return TransformSuggestion{}, false
+	}
+	best := TransformSuggestion{Index: index, Name: name, Skewness: skew, TransformedSkewness: skew}
+	transformed := make([]float64, len(x))
+	for _, t := range []Transform{TransformLog, TransformSqrt, TransformReciprocal} {
+		ok := true
+		for i, v := range x {
+			transformed[i] = t.apply(v)
+			ok = ok && !math.IsNaN(transformed[i])
+		}
+		if !ok {
+			continue
+		}
+		if s := stat.Skew(transformed, nil); math.Abs(s) < math.Abs(best.TransformedSkewness) {
+			best.Transform, best.TransformedSkewness = t, s
+		}
+	}
+	if best.TransformedSkewness == skew {
+		return TransformSuggestion{}, false
+	}
+	best.Reason = fmt.Sprintf("skewness %.2f falls to %.2f", skew, best.TransformedSkewness)
+	return best, true
+}
+
+// correlationTest returns the correlation of x and y and the two-sided p-value of the t test that it is zero.
+func correlationTest(x, y []float64) (rho, p float64) {
+	rho = stat.Correlation(x, y, nil)
+	df := float64(len(x) - 2)
+	t := rho * math.Sqrt(df/(1-rho*rho))
+	return rho, 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}.Survival(math.Abs(t))
+}
@@ -0,0 +1,52 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSuggestTransforms(t *testing.T) {
+	// y grows exponentially in x with multiplicative errors, so its spread grows with its level
+	rng := rand.New(rand.NewSource(1))
+	r := new(Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "x")
+	r.SetVar(1, "z")
+	for i := 0; i < 200; i++ {
+		x := rng.Float64() * 4
+		z := math.Exp(rng.NormFloat64())
+		r.Train(DataPoint(math.Exp(1+x+0.3*rng.NormFloat64()), []float64{x, z}))
+	}
+	if _, err := r.SuggestTransforms(); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	suggestions, err := r.SuggestTransforms()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var skewedY, funnel, skewedZ bool
+	for _, s := range suggestions {
+		switch {
+		case s.Index == -1 && !math.IsNaN(s.Skewness):
+			skewedY = s.Transform == TransformLog && math.Abs(s.TransformedSkewness) < math.Abs(s.Skewness)
+		case s.Index == -1:
+			funnel = s.Transform == TransformLog
+		case s.Index == 1 && !math.IsNaN(s.Skewness):
+			skewedZ = s.Transform == TransformLog && s.Name == "z"
+		case s.Index == 0 && !math.IsNaN(s.Skewness):
+			t.Errorf("Expected no skewness suggestion for the uniform x, got %+v", s)
+		}
+	}
+	if !skewedY || !funnel || !skewedZ {
+		t.Errorf("Expected log suggestions for skewed y, its residual spread and the lognormal z, got %+v", suggestions)
+	}
+	if TransformReciprocal.String() != "reciprocal" {
+		t.Errorf("Expected reciprocal, got %v", TransformReciprocal)
+	}
+}
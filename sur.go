@@ -0,0 +1,130 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SUR is a system of seemingly unrelated regressions, estimated jointly by feasible generalized least
+// squares so that the correlation between the errors of the equations sharpens the estimates.
+type SUR struct {
+	Equations []*Regression
+	// Coeffs and StdErrs hold the coefficients of each equation and their standard errors, starting
+	// with the offset as in GetCoeffs.
+	Coeffs  [][]float64
+	StdErrs [][]float64
+	// ResidualCov is the covariance of the errors between equations, estimated from the least squares residuals.
+	ResidualCov *mat.SymDense
+}
+
+// FitSUR jointly estimates the equations, each a regression that has been run on the same samples in
+// the same order, possibly with different variables. Zellner's two step estimator is used: the
+// covariance of the errors is estimated from the residuals of each equation, then all the equations
+// are refitted together by generalized least squares. If every equation has the same variables the
+// estimates equal those of the separate fits.
+func FitSUR(equations ...*Regression) (*SUR, error) {
+	if len(equations) == 0 {
+		return nil, fmt.Errorf("%w: no equations", ErrNotEnoughData)
+	}
+	n := len(equations[0].Data)
+	xs := make([]*mat.Dense, len(equations))
+	ys := make([]*mat.Dense, len(equations))
+	residuals := mat.NewDense(n, len(equations), nil)
+	for m, eq := range equations {
+		if err := eq.requireData(); err != nil {
+			return nil, fmt.Errorf("equation %d: %w", m, err)
+		}
+		if len(eq.Data) != n {
+			return nil, fmt.Errorf("%w: equation %d has %d samples, expected %d as in equation 0", ErrDesign, m, len(eq.Data), n)
+		}
+		if len(eq.dropped) > 0 || eq.fixedEffects || len(eq.endogenous) > 0 || len(eq.instruments) > 0 || len(eq.constraints) > 0 || eq.signConstrained() {
+			return nil, fmt.Errorf("%w: equation %d is not an unrestricted least squares fit", ErrIncompatibleOptions, m)
+		}
+		ys[m], xs[m] = eq.designMatrix()
+		for i, d := range eq.Data {
+			residuals.Set(i, m, d.Observed-d.Predicted)
+		}
+	}
+
+	// Estimate the covariance of the errors between equations
+	sigma := mat.NewSymDense(len(equations), nil)
+	sigma.SymOuterK(1/float64(n), residuals.T())
+	var chol mat.Cholesky
+	if ok := chol.Factorize(sigma); !ok {
+		return nil, fmt.Errorf("%w: the errors of the equations are perfectly correlated", ErrSingular)
+	}
+	var inv mat.SymDense
+	if err := chol.InverseTo(&inv); err != nil {
+		return nil, err
+	}
+
+	// Build the normal equations of the stacked system, whose blocks are σᵐˡXₘᵀXₗ and Σₗ σᵐˡXₘᵀyₗ
+	offsets := make([]int, len(equations)+1)
+	for m, x := range xs {
+		_, cols := x.Dims()
+		offsets[m+1] = offsets[m] + cols
+	}
+	total := offsets[len(equations)]
+	a := mat.NewSymDense(total, nil)
+	b := mat.NewVecDense(total, nil)
+	for m := range equations {
+		for l := range equations {
+			var block, xty mat.Dense
+			block.Mul(xs[m].T(), xs[l])
+			xty.Mul(xs[m].T(), ys[l])
+			rows, cols := block.Dims()
+			for i := 0; i < rows; i++ {
+				b.SetVec(offsets[m]+i, b.AtVec(offsets[m]+i)+inv.At(m, l)*xty.At(i, 0))
+				for j := 0; j < cols; j++ {
+					if offsets[m]+i <= offsets[l]+j {
+						a.SetSym(offsets[m]+i, offsets[l]+j, inv.At(m, l)*block.At(i, j))
+					}
+				}
+			}
+		}
+	}
+	var normal mat.Cholesky
+	if ok := normal.Factorize(a); !ok {
+		return nil, fmt.Errorf("%w: the stacked design is singular", ErrSingular)
+	}
+	var beta mat.VecDense
+	if err := normal.SolveVecTo(&beta, b); err != nil {
+		return nil, err
+	}
+	var cov mat.SymDense
+	if err := normal.InverseTo(&cov); err != nil {
+		return nil, err
+	}
+
+	s := &SUR{Equations: equations, ResidualCov: sigma}
+	for m := range equations {
+		coeffs := make([]float64, offsets[m+1]-offsets[m])
+		stdErrs := make([]float64, len(coeffs))
+		for j := range coeffs {
+			coeffs[j] = beta.AtVec(offsets[m] + j)
+			stdErrs[j] = math.Sqrt(cov.At(offsets[m]+j, offsets[m]+j))
+		}
+		s.Coeffs = append(s.Coeffs, coeffs)
+		s.StdErrs = append(s.StdErrs, stdErrs)
+	}
+	return s, nil
+}
+
+// Predict predicts the observed value of equation m for its variables, before any feature crosses.
+func (s *SUR) Predict(m int, vars []float64) (float64, error) {
+	if m < 0 || m >= len(s.Equations) {
+		return 0, fmt.Errorf("%w: equation %d of %d", ErrVariableIndex, m, len(s.Equations))
+	}
+	eq := s.Equations[m]
+	if len(vars) != eq.rawVars {
+		return 0, fmt.Errorf("%w: got %d, expected %d", ErrVariableCount, len(vars), eq.rawVars)
+	}
+	vars = eq.features(vars)
+	p := s.Coeffs[m][0]
+	for j, v := range vars {
+		p += s.Coeffs[m][j+1] * v
+	}
+	return p, nil
+}
@@ -0,0 +1,80 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFitSUR(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var demand, supply [][]float64
+	for i := 0; i < 100; i++ {
+		price, income, cost := rng.Float64()*10, rng.Float64()*5, rng.Float64()*3
+		// Correlated errors between the equations
+		u := rng.NormFloat64()
+		v := 0.9*u + 0.3*rng.NormFloat64()
+		demand = append(demand, []float64{20 - 1.5*price + 2*income + u, price, income})
+		supply = append(supply, []float64{5 + 1*price - 2*cost + v, price, cost})
+	}
+	fit := func(data [][]float64) *Regression {
+		r := new(Regression)
+		r.Train(MakeDataPoints(data, 0)...)
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	d, s := fit(demand), fit(supply)
+
+	sur, err := FitSUR(d, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sur.Coeffs) != 2 || len(sur.Coeffs[0]) != 3 {
+		t.Fatalf("Expected two equations of three coefficients, got %v", sur.Coeffs)
+	}
+	want := [][]float64{{20, -1.5, 2}, {5, 1, -2}}
+	for m := range want {
+		for j, w := range want[m] {
+			if math.Abs(sur.Coeffs[m][j]-w) > 4*sur.StdErrs[m][j] {
+				t.Errorf("Equation %d coefficient %d: expected about %v, got %v ± %v", m, j, w, sur.Coeffs[m][j], sur.StdErrs[m][j])
+			}
+		}
+	}
+	// Joint estimation is more precise than the separate fits when the errors are correlated
+	if sur.StdErrs[0][2] >= d.StdErr(2) {
+		t.Errorf("Expected a smaller standard error than least squares, got %v and %v", sur.StdErrs[0][2], d.StdErr(2))
+	}
+	if corr := sur.ResidualCov.At(0, 1) / math.Sqrt(sur.ResidualCov.At(0, 0)*sur.ResidualCov.At(1, 1)); corr < 0.8 {
+		t.Errorf("Expected a residual correlation near 0.95, got %v", corr)
+	}
+	p, err := sur.Predict(1, []float64{2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := sur.Coeffs[1][0] + 2*sur.Coeffs[1][1] + sur.Coeffs[1][2]; math.Abs(p-want) > 1e-12 {
+		t.Errorf("Expected prediction %v, got %v", want, p)
+	}
+
+	// With the same variables in every equation SUR reduces to least squares
+	same := make([][]float64, len(supply))
+	for i := range supply {
+		same[i] = []float64{supply[i][0], demand[i][1], demand[i][2]}
+	}
+	s2 := fit(same)
+	sur, err = FitSUR(d, s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j, c := range s2.GetCoeffs() {
+		if math.Abs(sur.Coeffs[1][j]-c) > 1e-9 {
+			t.Errorf("Coefficient %d: expected %v, got %v", j, c, sur.Coeffs[1][j])
+		}
+	}
+
+	if _, err := FitSUR(d, fit(supply[:50])); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// offsetName is the term name of the offset in tabulated output.
+const offsetName = "(Offset)"
+
+// TidyCoefficient is a row of the coefficient table of a fitted model, in the style of R's broom::tidy.
+type TidyCoefficient struct {
+	Term     string
+	Estimate float64
+	StdErr   float64
+	TValue   float64
+	PValue   float64
+	// ConfLow and ConfHigh bound the 95% confidence interval of the estimate.
+	ConfLow  float64
+	ConfHigh float64
+}
+
+// Tidy returns a row for the offset and each variable with its estimate, standard error, t statistic,
+// two-sided p-value and 95% confidence interval. Statistics that are unavailable are NaN.
+func (r *Regression) Tidy() ([]TidyCoefficient, error) {
+	if !r.hasRun {
+		return nil, ErrRegressionNotRun
+	}
+	// Without residual degrees of freedom the t distribution is undefined
+	var t *distuv.StudentsT
+	critical := math.NaN()
+	if r.residualDF > 0 {
+		t = &distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(r.residualDF)}
+		critical = t.Quantile(0.975)
+	}
+
+	rows := make([]TidyCoefficient, len(r.coeff))
+	for i := range rows {
+		term := r.coeffName(i)
+		estimate, stdErr := r.Coeff(i), r.StdErr(i)
+		tValue := estimate / stdErr
+		pValue := math.NaN()
+		if t != nil {
+			pValue = 2 * t.Survival(math.Abs(tValue))
+		}
+		rows[i] = TidyCoefficient{
+			Term:     term,
+			Estimate: estimate,
+			StdErr:   stdErr,
+			TValue:   tValue,
+			PValue:   pValue,
+			ConfLow:  estimate - critical*stdErr,
+			ConfHigh: estimate + critical*stdErr,
+		}
+	}
+	return rows, nil
+}
+
+// ModelSummary holds the model level statistics of a fitted model, in the style of R's broom::glance.
+type ModelSummary struct {
+	R2         float64
+	AdjustedR2 float64
+	// Sigma is the residual standard error.
+	Sigma float64
+	// FStatistic and PValue test whether any of the variables explain the observed value.
+	FStatistic float64
+	PValue     float64
+	// DF is the number of fitted variables and DFResidual the residual degrees of freedom.
+	DF         int
+	DFResidual int
+	NObs       int
+	// LogLik, AIC and BIC assume normal errors, counting the residual variance as a parameter.
+	LogLik float64
+	AIC    float64
+	BIC    float64
+	// Solver is the solver that fitted the model.
+	Solver Solver
+}
+
+// Glance returns the model level statistics of the fitted model.
+func (r *Regression) Glance() (*ModelSummary, error) {
+	if err := r.requireData(); err != nil {
+		return nil, err
+	}
+	n := len(r.Data)
+	sse, sst := r.residualSS, r.totalSS
+
+	s := &ModelSummary{
+		R2:         r.R2,
+		AdjustedR2: r.AdjustedR2,
+		Sigma:      r.residualStdErr,
+		DF:         n - 1 - r.residualDF,
+		DFResidual: r.residualDF,
+		NObs:       n,
+		Solver:     r.solverUsed,
+	}
+	s.FStatistic, s.PValue = math.NaN(), math.NaN()
+	// The F distribution is undefined without degrees of freedom on either side
+	if s.DF > 0 && s.DFResidual > 0 {
+		s.FStatistic = ((sst - sse) / float64(s.DF)) / (sse / float64(s.DFResidual))
+		s.PValue = distuv.F{D1: float64(s.DF), D2: float64(s.DFResidual)}.Survival(s.FStatistic)
+	}
+
+	params := float64(s.DF + 2)
+	s.LogLik = -float64(n) / 2 * (math.Log(2*math.Pi) + math.Log(sse/float64(n)) + 1)
+	s.AIC = -2*s.LogLik + 2*params
+	s.BIC = -2*s.LogLik + math.Log(float64(n))*params
+	return s, nil
+}
@@ -0,0 +1,119 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTidy(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "x")
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	if _, err := r.Tidy(); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Run()
+
+	tidy, err := r.Tidy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reference values from R's broom::tidy(lm(y1 ~ x1), conf.int = TRUE)
+	expected := []TidyCoefficient{
+		{Term: "(Offset)", Estimate: 3.0001, StdErr: 1.1247, TValue: 2.667, PValue: 0.02573, ConfLow: 0.4557, ConfHigh: 5.5444},
+		{Term: "x", Estimate: 0.5001, StdErr: 0.1179, TValue: 4.241, PValue: 0.00217, ConfLow: 0.2334, ConfHigh: 0.7668},
+	}
+	for i, e := range expected {
+		g := tidy[i]
+		if g.Term != e.Term {
+			t.Errorf("Expected term %q, got %q", e.Term, g.Term)
+		}
+		for _, v := range [][2]float64{
+			{g.Estimate, e.Estimate}, {g.StdErr, e.StdErr}, {g.TValue, e.TValue},
+			{g.PValue, e.PValue}, {g.ConfLow, e.ConfLow}, {g.ConfHigh, e.ConfHigh},
+		} {
+			if math.Abs(v[0]-v[1]) > 1e-3*math.Max(1, math.Abs(v[1])) {
+				t.Errorf("%s: expected %v, got %v", e.Term, v[1], v[0])
+			}
+		}
+	}
+}
+
+func TestGlance(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Run()
+
+	g, err := r.Glance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NObs != 11 || g.DF != 1 || g.DFResidual != 9 {
+		t.Errorf("Expected 11 observations with 1 and 9 degrees of freedom, got %d, %d, %d", g.NObs, g.DF, g.DFResidual)
+	}
+	// Reference values from R's broom::glance(lm(y1 ~ x1))
+	for _, v := range []struct {
+		name          string
+		got, expected float64
+	}{
+		{"R2", g.R2, 0.6665},
+		{"AdjustedR2", g.AdjustedR2, 0.6295},
+		{"Sigma", g.Sigma, 1.2366},
+		{"FStatistic", g.FStatistic, 17.99},
+		{"PValue", g.PValue, 0.00217},
+		{"LogLik", g.LogLik, -16.8407},
+		{"AIC", g.AIC, 39.6814},
+		{"BIC", g.BIC, 40.8751},
+	} {
+		if math.Abs(v.got-v.expected) > 1e-3*math.Max(1, math.Abs(v.expected)) {
+			t.Errorf("Expected %s %v, got %v", v.name, v.expected, v.got)
+		}
+	}
+}
+
+func TestTidyNoResidualDF(t *testing.T) {
+	// Three data points fit by two variables and the offset leave no residual degrees of freedom
+	r := new(Regression)
+	r.Train(MakeDataPoints([][]float64{{1, 1, 2}, {4, 2, 1}, {6, 3, 5}}, 0)...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	tidy, err := r.Tidy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range tidy {
+		if !math.IsNaN(row.PValue) || !math.IsNaN(row.ConfLow) || !math.IsNaN(row.ConfHigh) {
+			t.Errorf("%s: expected NaN p-value and interval, got %+v", row.Term, row)
+		}
+	}
+	g, err := r.Glance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.DFResidual != 0 || !math.IsNaN(g.FStatistic) || !math.IsNaN(g.PValue) {
+		t.Errorf("Expected NaN F statistic with no residual degrees of freedom, got %+v", g)
+	}
+
+	c := NewCrossProducts(2)
+	for _, row := range [][]float64{{1, 1, 2}, {4, 2, 1}, {6, 3, 5}} {
+		c.Add(row[0], row[1:])
+	}
+	fit, err := c.Fit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fit.Tidy(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := FromCoefficients(1, map[string]float64{"x": 2})
+	tidy, err = m.Tidy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tidy) != 2 || tidy[1].Estimate != 2 || !math.IsNaN(tidy[1].PValue) {
+		t.Errorf("Expected estimates with NaN statistics, got %+v", tidy)
+	}
+}
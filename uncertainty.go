@@ -0,0 +1,85 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+)
+
+// UncertainPrediction is a prediction from variables that are themselves uncertain, such as
+// measurements with a known standard uncertainty.
+type UncertainPrediction struct {
+	Value float64
+	// StdErr is the standard error of the predicted mean from the covariance of the coefficients, or
+	// NaN if it is unavailable.
+	StdErr float64
+	// InputStdErr is the standard deviation of the prediction propagated from the uncertainties of
+	// the variables.
+	InputStdErr float64
+	// Total combines the two, as the root of the sum of their squares.
+	Total float64
+	// Sensitivities holds the derivative of the prediction with respect to each variable, before any
+	// feature crosses, at which the uncertainties were propagated.
+	Sensitivities []float64
+}
+
+// PredictUncertain predicts the observed value for vars, where stdDevs holds the standard uncertainty
+// of each variable and the uncertainties are independent. See PredictUncertainCov.
+func (r *Regression) PredictUncertain(vars, stdDevs []float64) (*UncertainPrediction, error) {
+	if len(stdDevs) != len(vars) {
+		return nil, fmt.Errorf("%w: %d uncertainties for %d variables", ErrVariableCount, len(stdDevs), len(vars))
+	}
+	cov := make([][]float64, len(vars))
+	for i, s := range stdDevs {
+		cov[i] = make([]float64, len(vars))
+		cov[i][i] = s * s
+	}
+	return r.PredictUncertainCov(vars, cov)
+}
+
+// PredictUncertainCov predicts the observed value for vars, propagating the uncertainty of the
+// variables, whose covariance matrix is cov, to the prediction by the first order delta method:
+// the variance is gᵀ·cov·g, where g holds the derivatives of the prediction with respect to the
+// variables. With feature crosses the derivatives are estimated by central differences, and the
+// propagated uncertainty is only accurate while the prediction is close to linear over the spread
+// of the variables.
+func (r *Regression) PredictUncertainCov(vars []float64, cov [][]float64) (*UncertainPrediction, error) {
+	value, err := r.Predict(vars)
+	if err != nil {
+		return nil, err
+	}
+	if len(cov) != len(vars) {
+		return nil, fmt.Errorf("%w: covariance of %d variables for %d", ErrVariableCount, len(cov), len(vars))
+	}
+	for i, row := range cov {
+		if len(row) != len(vars) {
+			return nil, fmt.Errorf("%w: covariance row %d has %d columns, expected %d", ErrDesign, i, len(row), len(vars))
+		}
+		if !(row[i] >= 0) {
+			return nil, fmt.Errorf("%w: variance %v of variable %d", ErrDesign, row[i], i)
+		}
+	}
+
+	g := make([]float64, len(vars))
+	for j := range g {
+		if len(r.crosses) == 0 {
+			g[j] = r.Coeff(j + 1)
+			continue
+		}
+		if g[j], err = r.partialDerivative(vars, j); err != nil {
+			return nil, err
+		}
+	}
+	var inputVar float64
+	for i := range g {
+		for j := range g {
+			inputVar += g[i] * cov[i][j] * g[j]
+		}
+	}
+
+	p := &UncertainPrediction{Value: value, StdErr: math.NaN(), InputStdErr: math.Sqrt(inputVar), Sensitivities: g}
+	if r.cov != nil {
+		p.StdErr = math.Sqrt(r.meanVariance(append([]float64{1}, r.features(vars)...)))
+	}
+	p.Total = math.Hypot(p.StdErr, p.InputStdErr)
+	return p, nil
+}
@@ -0,0 +1,62 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPredictUncertain(t *testing.T) {
+	r := FromCoefficients(1, map[string]float64{"a": 2, "b": -3})
+	p, err := r.PredictUncertain([]float64{1, 1}, []float64{0.3, 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Value != 0 || math.Abs(p.InputStdErr-math.Hypot(2*0.3, 3*0.1)) > 1e-12 {
+		t.Errorf("Expected 0 ± %v, got %v ± %v", math.Hypot(0.6, 0.3), p.Value, p.InputStdErr)
+	}
+	if !math.IsNaN(p.StdErr) || !math.IsNaN(p.Total) {
+		t.Errorf("Expected no model uncertainty without training data, got %v", p.StdErr)
+	}
+
+	// Positively correlated errors in variables with opposite effects partly cancel
+	q, err := r.PredictUncertainCov([]float64{1, 1}, [][]float64{{0.09, 0.02}, {0.02, 0.01}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := math.Sqrt(0.36 + 0.09 - 2*2*3*0.02); math.Abs(q.InputStdErr-want) > 1e-12 {
+		t.Errorf("Expected input uncertainty %v with correlation, got %v", want, q.InputStdErr)
+	}
+
+	if _, err := r.PredictUncertain([]float64{1, 1}, []float64{0.3}); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+	if _, err := r.PredictUncertain([]float64{1, 1}, []float64{0.3, math.NaN()}); !errors.Is(err, ErrDesign) {
+		t.Errorf("Expected ErrDesign, got %v", err)
+	}
+}
+
+func TestPredictUncertainCrosses(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	x, s := 9.0, 0.5
+	p, err := r.PredictUncertain([]float64{x}, []float64{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slope := r.Coeff(1) + 2*r.Coeff(2)*x
+	if math.Abs(p.Sensitivities[0]-slope) > 1e-6 || math.Abs(p.InputStdErr-math.Abs(slope)*s) > 1e-6 {
+		t.Errorf("Expected sensitivity %v and uncertainty %v, got %v and %v", slope, math.Abs(slope)*s, p.Sensitivities[0], p.InputStdErr)
+	}
+	interval, err := r.PredictInterval([]float64{x}, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(p.StdErr-interval.StdErr) > 1e-12 || math.Abs(p.Total-math.Hypot(p.StdErr, p.InputStdErr)) > 1e-12 {
+		t.Errorf("Expected model uncertainty %v, got %v with total %v", interval.StdErr, p.StdErr, p.Total)
+	}
+}
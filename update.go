@@ -0,0 +1,206 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// triangularFactor is the triangular factor R of the QR factorization of an ordinary least squares
+// design, along with Qᵀy and the residual sum of squares, so that rows can be added to or removed
+// from the fit without refactorizing the design.
+type triangularFactor struct {
+	r   *mat.TriDense
+	qty []float64
+	sse float64
+}
+
+// newTriangularFactor keeps the k×k triangle of r and the first k elements of qty, with the signs
+// of rows flipped as needed so that the diagonal is positive.
+func newTriangularFactor(r, qty mat.Matrix, k int, sse float64) *triangularFactor {
+	f := &triangularFactor{r: mat.NewTriDense(k, mat.Upper, nil), qty: make([]float64, k), sse: sse}
+	for i := 0; i < k; i++ {
+		sign := 1.0
+		if r.At(i, i) < 0 {
+			sign = -1
+		}
+		for j := i; j < k; j++ {
+			f.r.SetTri(i, j, sign*r.At(i, j))
+		}
+		f.qty[i] = sign * qty.At(i, 0)
+	}
+	return f
+}
+
+// addRow updates the factorization for a new row x with observed value y using Givens rotations,
+// in O(k²) time.
+func (f *triangularFactor) addRow(x []float64, y float64) {
+	x = append([]float64(nil), x...)
+	k := len(f.qty)
+	for j := 0; j < k; j++ {
+		if x[j] == 0 {
+			continue
+		}
+		a := f.r.At(j, j)
+		h := math.Hypot(a, x[j])
+		c, s := a/h, x[j]/h
+		for l := j; l < k; l++ {
+			rl := f.r.At(j, l)
+			f.r.SetTri(j, l, c*rl+s*x[l])
+			x[l] = c*x[l] - s*rl
+		}
+		f.qty[j], y = c*f.qty[j]+s*y, c*y-s*f.qty[j]
+	}
+	f.sse += y * y
+}
+
+// removeRow downdates the factorization to remove the row x with observed value y. It returns false
+// if removing the row would leave the design rank deficient.
+func (f *triangularFactor) removeRow(x []float64, y float64) bool {
+	k := len(f.qty)
+	xv := mat.NewVecDense(k, x)
+	var z mat.VecDense
+	if err := z.SolveVec(f.r.TTri(), xv); err != nil {
+		return false
+	}
+	// The leverage of the row and its residual give the change in the residual sum of squares.
+	h := mat.Dot(&z, &z)
+	if h >= 1-1e-12 {
+		return false
+	}
+	e := y - mat.Dot(xv, mat.NewVecDense(k, f.coefficients()))
+
+	var chol, down mat.Cholesky
+	chol.SetFromU(f.r)
+	if ok := down.SymRankOne(&chol, -1, xv); !ok {
+		return false
+	}
+	// Qᵀy is recovered from Xᵀy = RᵀQᵀy with the row's contribution removed.
+	var xty mat.VecDense
+	xty.MulVec(f.r.TTri(), mat.NewVecDense(k, f.qty))
+	xty.AddScaledVec(&xty, -y, xv)
+	u := mat.NewTriDense(k, mat.Upper, nil)
+	down.UTo(u)
+	var qty mat.VecDense
+	if err := qty.SolveVec(u.TTri(), &xty); err != nil {
+		return false
+	}
+
+	f.r = u
+	for i := range f.qty {
+		f.qty[i] = qty.AtVec(i)
+	}
+	f.sse = math.Max(f.sse-e*e/(1-h), 0)
+	return true
+}
+
+// coefficients solves Rb = Qᵀy by back substitution.
+func (f *triangularFactor) coefficients() []float64 {
+	k := len(f.qty)
+	b := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		b[i] = f.qty[i]
+		for j := i + 1; j < k; j++ {
+			b[i] -= b[j] * f.r.At(i, j)
+		}
+		b[i] /= f.r.At(i, i)
+	}
+	return b
+}
+
+// covariance returns σ²(RᵀR)⁻¹ with σ² estimated on df degrees of freedom, or nil if R is singular.
+func (f *triangularFactor) covariance(df int) *mat.SymDense {
+	var chol mat.Cholesky
+	chol.SetFromU(f.r)
+	inv := new(mat.SymDense)
+	if err := chol.InverseTo(inv); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return nil
+		}
+	}
+	sigma2 := math.NaN()
+	if df > 0 {
+		sigma2 = f.sse / float64(df)
+	}
+	inv.ScaleSym(sigma2, inv)
+	return inv
+}
+
+// Update adds data points to a model fitted by ordinary least squares and refits it without
+// refactorizing the design matrix: each point updates the factorization in O(k²) time for k
+// coefficients. The fitted values and statistics of the training data are then refreshed once.
+// Any feature crosses are applied to the new points, as by Run, and points with NaN or infinite
+// values are skipped with a DroppedRows warning.
+func (r *Regression) Update(points ...*dataPoint) error {
+	if err := r.requireData(); err != nil {
+		return err
+	}
+	if r.factor == nil || len(r.dropped) > 0 || r.covType != Classical || r.varianceModel {
+		return fmt.Errorf("%w: only models fitted by ordinary least squares with classical errors and without dropped variables can be updated", ErrIncompatibleOptions)
+	}
+	for i, d := range points {
+		if len(d.Variables) != r.rawVars {
+			return &DataPointError{Index: i, Err: fmt.Errorf("%w: has %d, expected %d", ErrVariableCount, len(d.Variables), r.rawVars)}
+		}
+	}
+
+	var rows []int
+	var dropped []*dataPoint
+	for i, d := range points {
+		if !r.finiteFeatures(d) {
+			rows = append(rows, i)
+			dropped = append(dropped, d)
+			continue
+		}
+		r.factor.addRow(append([]float64{1}, r.features(d.Variables)...), d.Observed)
+		r.Data = append(r.Data, d)
+	}
+	if len(rows) > 0 {
+		r.warn(DroppedRows, rows, dropped, "dropped %d updated data points with NaN or infinite values", len(rows))
+	}
+	r.reportProgress(Progress{Stage: "update", Rows: len(r.Data)})
+	r.refitFromFactor()
+	return nil
+}
+
+// Remove deletes the data point at index i from a model fitted by ordinary least squares and
+// refits it by downdating the factorization in O(k²) time, which makes it cheap to explore the
+// effect of dropping a single outlier. As with Update, the fitted values and statistics of the
+// remaining data are refreshed afterwards.
+func (r *Regression) Remove(i int) error {
+	if err := r.requireData(); err != nil {
+		return err
+	}
+	if r.factor == nil || len(r.dropped) > 0 || r.covType != Classical || r.varianceModel {
+		return fmt.Errorf("%w: only models fitted by ordinary least squares with classical errors and without dropped variables can be downdated", ErrIncompatibleOptions)
+	}
+	if i < 0 || i >= len(r.Data) {
+		return fmt.Errorf("%w: %d of %d", ErrDataIndex, i, len(r.Data))
+	}
+	if len(r.Data)-1 <= len(r.factor.qty) {
+		return fmt.Errorf("%w: removing a point leaves %d for %d coefficients", ErrNotEnoughData, len(r.Data)-1, len(r.factor.qty))
+	}
+
+	d := r.Data[i]
+	if !r.factor.removeRow(append([]float64{1}, r.features(d.Variables)...), d.Observed) {
+		return &DataPointError{Index: i, Err: fmt.Errorf("%w: removing the point leaves the design rank deficient", ErrSingular)}
+	}
+	r.Data = append(r.Data[:i:i], r.Data[i+1:]...)
+	r.refitFromFactor()
+	return nil
+}
+
+// refitFromFactor sets the coefficients, their covariance and the fit statistics from the factorization.
+func (r *Regression) refitFromFactor() {
+	k := len(r.factor.qty)
+	r.residualDF = len(r.Data) - k
+	r.coeff = r.factor.coefficients()
+	r.cov = r.factor.covariance(r.residualDF)
+	r.extrapolation = new(extrapolationBounds)
+	r.setFormula()
+	r.calcPredicted()
+	r.calcVariance()
+	r.calcR2()
+	r.metadata = r.newMetadata()
+}
@@ -0,0 +1,104 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	data := [][]float64{
+		{1, 1, 2}, {3, 2, 1}, {4, 3, 5}, {6, 4, 2}, {7, 5, 6}, {9, 6, 1},
+		{10, 7, 4}, {13, 8, 8}, {14, 9, 2}, {15, 10, 7}, {18, 11, 3}, {19, 12, 9},
+	}
+	full := new(Regression)
+	full.Train(MakeDataPoints(data, 0)...)
+	full.AddCross(PowCross(0, 2))
+	full.Run()
+
+	r := new(Regression)
+	r.Train(MakeDataPoints(data[:6], 0)...)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Update(MakeDataPoints(data[6:], 0)...); !errors.Is(err, ErrRegressionNotRun) {
+		t.Errorf("Expected ErrRegressionNotRun, got %v", err)
+	}
+	r.Run()
+	if err := r.Update(MakeDataPoints(data[6:], 0)...); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, c := range full.GetCoeffs() {
+		if math.Abs(r.Coeff(i)-c) > 1e-9 {
+			t.Errorf("Coefficient %d: expected %v, got %v", i, c, r.Coeff(i))
+		}
+		if math.Abs(r.StdErr(i)-full.StdErr(i)) > 1e-9 {
+			t.Errorf("Standard error %d: expected %v, got %v", i, full.StdErr(i), r.StdErr(i))
+		}
+	}
+	if math.Abs(r.R2-full.R2) > 1e-12 {
+		t.Errorf("Expected R2 %v, got %v", full.R2, r.R2)
+	}
+	if len(r.Data) != len(data) {
+		t.Errorf("Expected %d data points, got %d", len(data), len(r.Data))
+	}
+
+	if err := r.Update(DataPoint(1, []float64{1, 2, 3})); !errors.Is(err, ErrVariableCount) {
+		t.Errorf("Expected ErrVariableCount, got %v", err)
+	}
+
+	c := new(Regression)
+	c.Train(MakeDataPoints(data, 0)...)
+	c.AddConstraint([]float64{0, 1, 1}, 1)
+	c.Run()
+	if err := c.Update(DataPoint(1, []float64{1, 2})); !errors.Is(err, ErrIncompatibleOptions) {
+		t.Errorf("Expected ErrIncompatibleOptions, got %v", err)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	data := [][]float64{
+		{1, 1, 2}, {3, 2, 1}, {4, 3, 5}, {6, 4, 2}, {7, 5, 6}, {9, 6, 1},
+		{10, 7, 4}, {13, 8, 8}, {30, 9, 2}, {15, 10, 7}, {18, 11, 3}, {19, 12, 9},
+	}
+	r := new(Regression)
+	r.Train(MakeDataPoints(data, 0)...)
+	r.Run()
+	if err := r.Remove(len(data)); !errors.Is(err, ErrDataIndex) {
+		t.Errorf("Expected ErrDataIndex, got %v", err)
+	}
+	if err := r.Remove(8); err != nil {
+		t.Fatal(err)
+	}
+
+	without := append(append([][]float64{}, data[:8]...), data[9:]...)
+	full := new(Regression)
+	full.Train(MakeDataPoints(without, 0)...)
+	full.Run()
+	for i, c := range full.GetCoeffs() {
+		if math.Abs(r.Coeff(i)-c) > 1e-9 {
+			t.Errorf("Coefficient %d: expected %v, got %v", i, c, r.Coeff(i))
+		}
+		if math.Abs(r.StdErr(i)-full.StdErr(i)) > 1e-9 {
+			t.Errorf("Standard error %d: expected %v, got %v", i, full.StdErr(i), r.StdErr(i))
+		}
+	}
+	if math.Abs(r.R2-full.R2) > 1e-12 {
+		t.Errorf("Expected R2 %v, got %v", full.R2, r.R2)
+	}
+	if len(r.Data) != len(without) {
+		t.Errorf("Expected %d data points, got %d", len(without), len(r.Data))
+	}
+
+	// Removing and re-adding a point restores the original fit
+	if err := r.Update(MakeDataPoints(data[8:9], 0)...); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Remove(len(r.Data) - 1); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range full.GetCoeffs() {
+		if math.Abs(r.Coeff(i)-c) > 1e-9 {
+			t.Errorf("Coefficient %d after round trip: expected %v, got %v", i, c, r.Coeff(i))
+		}
+	}
+}
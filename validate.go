@@ -0,0 +1,68 @@
+package regression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Validate checks a dataset before it is trained, so that ingestion pipelines and fuzz tests can
+// reject bad data before it reaches a solver. It checks every data point rather than stopping at the
+// first problem, and returns the problems joined by errors.Join, each a *DataPointError wrapping a
+// sentinel error, or nil if there are none:
+//
+//   - ErrNotEnoughData if the dataset is empty,
+//   - ErrDesign for a nil data point, a NaN or infinite observed value or variable, or a malformed
+//     censoring interval,
+//   - ErrVariableCount for a data point with a different number of variables to the first, and
+//   - ErrDuplicate for a data point included twice.
+//
+// Data points with equal values are not duplicates, as replicate measurements often are, and neither
+// are data points with the same label, as labels may identify units measured repeatedly, as in
+// DiffInDiff.
+func Validate(data DataPoints) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: no data points", ErrNotEnoughData)
+	}
+	var errs []error
+	fail := func(i int, err error) {
+		errs = append(errs, &DataPointError{Index: i, Err: err})
+	}
+	vars := -1
+	seen := make(map[*dataPoint]int, len(data))
+	for i, d := range data {
+		if d == nil {
+			fail(i, fmt.Errorf("%w: nil data point", ErrDesign))
+			continue
+		}
+		if first, ok := seen[d]; ok {
+			fail(i, fmt.Errorf("%w: the same data point as %d", ErrDuplicate, first))
+			continue
+		}
+		seen[d] = i
+
+		if vars < 0 {
+			vars = len(d.Variables)
+		} else if len(d.Variables) != vars {
+			fail(i, fmt.Errorf("%w: has %d, expected %d as in the first data point", ErrVariableCount, len(d.Variables), vars))
+		}
+		for j, v := range d.Variables {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				fail(i, fmt.Errorf("%w: variable %d is %v", ErrDesign, j, v))
+			}
+		}
+		switch c := d.censored; {
+		case c == nil:
+			if math.IsNaN(d.Observed) || math.IsInf(d.Observed, 0) {
+				fail(i, fmt.Errorf("%w: observed value is %v", ErrDesign, d.Observed))
+			}
+		case !(c.lower <= c.upper):
+			fail(i, fmt.Errorf("%w: censoring interval [%v, %v]", ErrDesign, c.lower, c.upper))
+		case math.IsInf(c.lower, -1) && math.IsInf(c.upper, 1):
+			fail(i, fmt.Errorf("%w: censoring interval [%v, %v] is unbounded", ErrDesign, c.lower, c.upper))
+		case math.IsInf(c.lower, 1) || math.IsInf(c.upper, -1):
+			fail(i, fmt.Errorf("%w: censoring interval [%v, %v] holds no finite value", ErrDesign, c.lower, c.upper))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package regression
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Validate(MakeDataPoints(anscombe, 0)); err != nil {
+		t.Errorf("Expected valid data, got %v", err)
+	}
+	if err := Validate(nil); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("Expected ErrNotEnoughData for no data, got %v", err)
+	}
+
+	shared := DataPoint(1, []float64{1, 2})
+	data := DataPoints{
+		DataPoint(1, []float64{1, 2}),
+		DataPoint(1, []float64{1, 2}),
+		shared,
+		nil,
+		DataPoint(math.NaN(), []float64{1, 2}),
+		DataPoint(1, []float64{1}),
+		DataPoint(1, []float64{1, math.Inf(1)}),
+		shared,
+		// Labels may repeat, as for a unit measured in several periods
+		LabeledDataPoint("A1", 1, []float64{1, 2}),
+		LabeledDataPoint("A1", 2, []float64{3, 4}),
+		CensoredDataPoint(math.Inf(-1), 3, []float64{1, 2}),
+		CensoredDataPoint(4, 3, []float64{1, 2}),
+		CensoredDataPoint(math.Inf(-1), math.Inf(1), []float64{1, 2}),
+	}
+	err := Validate(data)
+	want := map[int]error{3: ErrDesign, 4: ErrDesign, 5: ErrVariableCount, 6: ErrDesign, 7: ErrDuplicate, 11: ErrDesign, 12: ErrDesign}
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		t.Fatalf("Expected joined errors, got %v", err)
+	}
+	got := make(map[int]error)
+	for _, e := range joined.Unwrap() {
+		var pointErr *DataPointError
+		if !errors.As(e, &pointErr) {
+			t.Fatalf("Expected a DataPointError, got %v", e)
+		}
+		got[pointErr.Index] = pointErr.Err
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected problems with data points %v, got %v", want, got)
+	}
+	for i, sentinel := range want {
+		if !errors.Is(got[i], sentinel) {
+			t.Errorf("Expected %v for data point %d, got %v", sentinel, i, got[i])
+		}
+	}
+}
+
+// FuzzValidate checks that Validate does not panic on arbitrary data and that data it accepts can be
+// fitted without panicking.
+func FuzzValidate(f *testing.F) {
+	f.Add(uint8(1), []byte{})
+	f.Add(uint8(2), make([]byte, 8*12))
+	seed := make([]byte, 8*15)
+	for i := 0; i < 15; i++ {
+		binary.LittleEndian.PutUint64(seed[8*i:], math.Float64bits(float64(i*i%7)))
+	}
+	f.Add(uint8(2), seed)
+	f.Add(uint8(4), append(seed, 0xff, 0xf0, 0, 0, 0, 0, 0, 0))
+	f.Fuzz(func(t *testing.T, vars uint8, b []byte) {
+		k := int(vars%4) + 1
+		var data DataPoints
+		for len(b) >= 8*(k+1) {
+			x := make([]float64, k+1)
+			for j := range x {
+				x[j] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+				b = b[8:]
+			}
+			data = append(data, DataPoint(x[0], x[1:]))
+		}
+		if err := Validate(data); err != nil || len(data) < 3 {
+			return
+		}
+		r := new(Regression)
+		r.Train(data...)
+		_ = r.Run()
+	})
+}
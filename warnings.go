@@ -0,0 +1,157 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// WarningKind identifies the kind of problem a Warning reports.
+type WarningKind int
+
+const (
+	// HighConditionNumber warns that the variables are close to collinear, so the coefficients are
+	// poorly determined and sensitive to small changes in the data.
+	HighConditionNumber WarningKind = iota
+	// HighLeverage warns that some data points have unusual values of the variables, more than
+	// twice the average leverage, and may have a large influence on the fit.
+	HighLeverage
+	// DroppedRows warns that some data points had NaN or infinite values and were left out of the fit.
+	DroppedRows
+	// NotConverged warns that an iterative fit stopped at its iteration limit before converging, so
+	// the coefficients may not be those of the best fit.
+	NotConverged
+)
+
+// conditionThreshold is the condition number of the design matrix, with columns scaled to unit length,
+// above which Run warns of collinearity. Belsley, Kuh and Welsch suggest 30 as the point at which
+// collinearity starts to affect the estimates.
+const conditionThreshold = 30
+
+// Warning is a non-fatal problem found while running the regression.
+type Warning struct {
+	Kind    WarningKind
+	Message string
+	// Rows holds the indices of the data points the warning concerns. Dropped rows are indexed in the
+	// order they were trained, and other rows index Data, from which the dropped rows have been removed.
+	Rows []int
+	// Labels holds the label of each of the data points in Rows, or is nil if none of them are labeled.
+	Labels []string
+}
+
+// Warnings returns the problems found while running the regression that did not prevent the fit,
+// such as dropped rows, collinear variables or high leverage points.
+func (r *Regression) Warnings() []Warning {
+	return r.warnings
+}
+
+// warn records a warning about the data points at the given rows, and logs it.
+func (r *Regression) warn(kind WarningKind, rows []int, points []*dataPoint, format string, args ...interface{}) {
+	w := Warning{Kind: kind, Message: fmt.Sprintf(format, args...), Rows: rows, Labels: labels(points)}
+	r.warnings = append(r.warnings, w)
+	if w.Labels != nil {
+		r.logger().Warn(w.Message, "rows", rows, "labels", w.Labels)
+	} else {
+		r.logger().Warn(w.Message, "rows", rows)
+	}
+}
+
+// labels returns the label of each data point, or nil if none of them are labeled.
+func labels(points []*dataPoint) []string {
+	labeled := false
+	out := make([]string, len(points))
+	for i, d := range points {
+		out[i] = d.Label
+		labeled = labeled || d.Label != ""
+	}
+	if !labeled {
+		return nil
+	}
+	return out
+}
+
+// dropNonFinite removes data points with a NaN or infinite observed value, variable or crossed
+// feature.
+func (r *Regression) dropNonFinite() {
+	var rows []int
+	var dropped []*dataPoint
+	kept := r.Data[:0:0]
+	for i, d := range r.Data {
+		if r.finiteFeatures(d) {
+			kept = append(kept, d)
+		} else {
+			rows = append(rows, i)
+			dropped = append(dropped, d)
+		}
+	}
+	if len(rows) > 0 {
+		r.Data = kept
+		r.warn(DroppedRows, rows, dropped, "dropped %d data points with NaN or infinite values", len(rows))
+	}
+}
+
+// finite reports whether the observed value and all variables of the point are finite.
+func (d *dataPoint) finite() bool {
+	ok := !math.IsNaN(d.Observed) && !math.IsInf(d.Observed, 0)
+	for _, v := range d.Variables {
+		ok = ok && !math.IsNaN(v) && !math.IsInf(v, 0)
+	}
+	return ok
+}
+
+// finiteFeatures reports whether the point is finite and so are the features crossed from its
+// variables, such as the square root of a negative value.
+func (r *Regression) finiteFeatures(d *dataPoint) bool {
+	if !d.finite() {
+		return false
+	}
+	for _, v := range r.features(d.Variables)[len(d.Variables):] {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkConditioning warns if the design matrix is badly conditioned.
+func (r *Regression) checkConditioning(variables mat.Matrix) {
+	rows, cols := variables.Dims()
+	scaled := mat.DenseCopyOf(variables)
+	for j := 0; j < cols; j++ {
+		norm := mat.Norm(scaled.ColView(j), 2)
+		if norm == 0 {
+			continue
+		}
+		for i := 0; i < rows; i++ {
+			scaled.Set(i, j, scaled.At(i, j)/norm)
+		}
+	}
+	if cond := mat.Cond(scaled, 2); cond > conditionThreshold {
+		r.warn(HighConditionNumber, nil, nil, "design matrix has a high condition number, %.3g, suggesting collinear variables", cond)
+	}
+}
+
+// checkLeverage warns of data points with more than twice the average leverage.
+func (r *Regression) checkLeverage() {
+	leverage, err := r.Leverage()
+	if err != nil {
+		return
+	}
+	var total float64
+	for _, h := range leverage {
+		total += h
+	}
+	threshold := 2 * total / float64(len(leverage))
+	var rows []int
+	var points []*dataPoint
+	for i, h := range leverage {
+		if h > threshold {
+			rows = append(rows, i)
+			points = append(points, r.Data[i])
+		}
+	}
+	if len(rows) > 0 {
+		r.warn(HighLeverage, rows, points, "%d data points have leverage above %.3g", len(rows), threshold)
+	}
+}
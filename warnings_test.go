@@ -0,0 +1,60 @@
+package regression
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Train(DataPoint(math.NaN(), []float64{3}), DataPoint(5, []float64{math.Inf(1)}))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Data) != len(anscombe) {
+		t.Errorf("Expected the non-finite rows to be removed, got %d rows", len(r.Data))
+	}
+	warnings := r.Warnings()
+	if len(warnings) != 1 || warnings[0].Kind != DroppedRows || !reflect.DeepEqual(warnings[0].Rows, []int{11, 12}) || warnings[0].Labels != nil {
+		t.Errorf("Expected a warning for dropped rows 11 and 12, got %+v", warnings)
+	}
+}
+
+func TestDroppedCrossedRows(t *testing.T) {
+	r := new(Regression)
+	r.Train(MakeDataPoints(anscombe, 0)...)
+	r.Train(DataPoint(5, []float64{-4}))
+	r.AddCross(PowCross(0, 0.5))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	warnings := r.Warnings()
+	if len(warnings) == 0 || warnings[0].Kind != DroppedRows || !reflect.DeepEqual(warnings[0].Rows, []int{11}) {
+		t.Errorf("Expected a warning for dropped row 11, whose square root is NaN, got %+v", warnings)
+	}
+}
+
+func TestHighLeverageAndConditionWarnings(t *testing.T) {
+	r := new(Regression)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		r.Train(DataPoint(2*x+math.Sin(x), []float64{x, x + 1e-3*math.Cos(x)}))
+	}
+	r.Train(LabeledDataPoint("outlier", 100, []float64{50, 50}))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := make(map[WarningKind]Warning)
+	for _, w := range r.Warnings() {
+		kinds[w.Kind] = w
+	}
+	if _, ok := kinds[HighConditionNumber]; !ok {
+		t.Errorf("Expected a high condition number warning, got %+v", r.Warnings())
+	}
+	if w, ok := kinds[HighLeverage]; !ok || !reflect.DeepEqual(w.Rows, []int{10}) || !reflect.DeepEqual(w.Labels, []string{"outlier"}) {
+		t.Errorf("Expected a high leverage warning for row 10, labeled outlier, got %+v", r.Warnings())
+	}
+}